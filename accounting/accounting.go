@@ -0,0 +1,348 @@
+// Package accounting lleva la cuenta, por cliente, de conexiones abiertas,
+// peticiones por segundo y bytes transferidos. Sus lecturas se exponen por
+// una API administrativa y sirven como señal para el rate limiter (ver
+// Tracker.Key y Tracker.Guard) y los middlewares de descarte de carga.
+package accounting
+
+import (
+	"cmp"
+	"io"
+	"net"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/profe-ajedrez/transwarp/middleware"
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// windowSize es la cantidad de segundos que cubre el cálculo de tasa.
+const windowSize = 60
+
+// maxClients es el valor por defecto de Tracker.MaxClients.
+const maxClients = 10000
+
+// overflowKey agrupa a los clientes nuevos cuando el Tracker está lleno y
+// ninguno puede descartarse.
+const overflowKey = "(otros)"
+
+// ClientStats es una lectura de la actividad de un cliente.
+type ClientStats struct {
+	Key         string    `json:"key"`
+	Connections int64     `json:"connections"`
+	Requests    int64     `json:"requests"`
+	RequestRate float64   `json:"request_rate"`
+	BytesIn     int64     `json:"bytes_in"`
+	BytesOut    int64     `json:"bytes_out"`
+	LastSeen    time.Time `json:"last_seen"`
+}
+
+type client struct {
+	conns    int64
+	requests int64
+	bytesIn  int64
+	bytesOut int64
+	lastSeen time.Time
+	// buckets cuenta peticiones por segundo en un anillo indexado por
+	// unix%windowSize; stamps guarda a qué segundo corresponde cada bucket.
+	buckets [windowSize]int64
+	stamps  [windowSize]int64
+}
+
+// window retorna las peticiones de los últimos windowSize segundos.
+func (c *client) window(now time.Time) int64 {
+	sec := now.Unix()
+	var sum int64
+	for i := range windowSize {
+		if sec-c.stamps[i] < windowSize {
+			sum += c.buckets[i]
+		}
+	}
+	return sum
+}
+
+func (c *client) rate(now time.Time) float64 {
+	return float64(c.window(now)) / windowSize
+}
+
+// relief retorna cuánto falta, si no llegan más peticiones, para que la
+// ventana baje a limit: cada segundo deja de contar el bucket más viejo.
+func (c *client) relief(now time.Time, limit int64) time.Duration {
+	sec := now.Unix()
+	sum := c.window(now)
+	for k := int64(1); k < windowSize; k++ {
+		old := sec + k - windowSize
+		if i := old % windowSize; c.stamps[i] == old {
+			sum -= c.buckets[i]
+		}
+		if sum <= limit {
+			return time.Duration(k) * time.Second
+		}
+	}
+	return windowSize * time.Second
+}
+
+// Tracker acumula la actividad de cada cliente. El valor cero no es
+// utilizable; se crea con New.
+type Tracker struct {
+	// KeyFunc identifica al cliente de una petición; por defecto su IP.
+	// Las conexiones siempre se cuentan por IP remota.
+	KeyFunc func(*http.Request) string
+	// MaxClients acota los clientes que se siguen; por defecto 10000. Al
+	// llenarse se descartan los inactivos hace más tiempo, de modo que un
+	// cliente que rota IPs o claves no haga crecer la memoria sin límite.
+	MaxClients int
+
+	mu      sync.Mutex
+	clients map[string]*client
+	conns   map[net.Conn]string
+	now     func() time.Time
+}
+
+// New crea un Tracker vacío.
+func New() *Tracker {
+	return &Tracker{
+		clients: make(map[string]*client),
+		conns:   make(map[net.Conn]string),
+		now:     time.Now,
+	}
+}
+
+// get retorna el cliente key, creándolo si no existe, junto con la clave
+// bajo la que quedó registrado. Debe llamarse con mu tomado.
+func (t *Tracker) get(key string) (*client, string) {
+	if c, ok := t.clients[key]; ok {
+		return c, key
+	}
+	limit := cmp.Or(t.MaxClients, maxClients)
+	if len(t.clients) >= limit {
+		t.evict(limit)
+	}
+	if len(t.clients) >= limit {
+		key = overflowKey
+		if c, ok := t.clients[key]; ok {
+			return c, key
+		}
+	}
+	c := &client{}
+	t.clients[key] = c
+	return c, key
+}
+
+// evict descarta el 10% de los clientes sin conexiones abiertas que llevan
+// más tiempo inactivos. Ordenar solo al llenarse mantiene el costo
+// amortizado bajo. Debe llamarse con mu tomado.
+func (t *Tracker) evict(limit int) {
+	type idle struct {
+		key      string
+		lastSeen time.Time
+	}
+	var candidates []idle
+	for key, c := range t.clients {
+		if c.conns == 0 && key != overflowKey {
+			candidates = append(candidates, idle{key, c.lastSeen})
+		}
+	}
+	slices.SortFunc(candidates, func(a, b idle) int { return a.lastSeen.Compare(b.lastSeen) })
+	for _, c := range candidates[:min(max(limit/10, 1), len(candidates))] {
+		delete(t.clients, c.key)
+	}
+}
+
+// ConnState registra aperturas y cierres de conexión; se pasa a
+// Router.ConnState.
+func (t *Tracker) ConnState(conn net.Conn, state http.ConnState) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch state {
+	case http.StateNew:
+		c, key := t.get(hostOf(conn.RemoteAddr().String()))
+		t.conns[conn] = key
+		c.conns++
+		c.lastSeen = t.now()
+	case http.StateClosed, http.StateHijacked:
+		key, ok := t.conns[conn]
+		if !ok {
+			return
+		}
+		delete(t.conns, conn)
+		if c, ok := t.clients[key]; ok && c.conns > 0 {
+			c.conns--
+		}
+	}
+}
+
+// Middleware cuenta cada petición y los bytes leídos y escritos.
+func (t *Tracker) Middleware() router.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := t.Key(r)
+			t.hit(key)
+
+			body := &countingReader{ReadCloser: r.Body}
+			r.Body = body
+			cw := &countingWriter{ResponseWriter: w}
+			next.ServeHTTP(cw, r)
+
+			t.mu.Lock()
+			c, _ := t.get(key)
+			c.bytesIn += body.n
+			c.bytesOut += cw.n
+			t.mu.Unlock()
+		})
+	}
+}
+
+// Key identifica al cliente de r con KeyFunc, o por su IP. Asignada a
+// middleware.RateLimitOptions.Key, el rate limiter y el Tracker cuentan
+// al mismo cliente.
+func (t *Tracker) Key(r *http.Request) string {
+	if t.KeyFunc != nil {
+		return t.KeyFunc(r)
+	}
+	return hostOf(r.RemoteAddr)
+}
+
+func (t *Tracker) hit(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := t.now()
+	sec := now.Unix()
+	i := sec % windowSize
+	c, _ := t.get(key)
+	if c.stamps[i] != sec {
+		c.stamps[i] = sec
+		c.buckets[i] = 0
+	}
+	c.buckets[i]++
+	c.requests++
+	c.lastSeen = now
+}
+
+// Get retorna las estadísticas de key.
+func (t *Tracker) Get(key string) ClientStats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	c, ok := t.clients[key]
+	if !ok {
+		return ClientStats{Key: key}
+	}
+	return t.stats(key, c)
+}
+
+// GetRequest retorna las estadísticas del cliente que hizo r.
+func (t *Tracker) GetRequest(r *http.Request) ClientStats {
+	return t.Get(t.Key(r))
+}
+
+func (t *Tracker) stats(key string, c *client) ClientStats {
+	return ClientStats{
+		Key:         key,
+		Connections: c.conns,
+		Requests:    c.requests,
+		RequestRate: c.rate(t.now()),
+		BytesIn:     c.bytesIn,
+		BytesOut:    c.bytesOut,
+		LastSeen:    c.lastSeen,
+	}
+}
+
+// Guard rechaza con middleware.TooManyRequests a los clientes cuya tasa,
+// según GetRequest, supera maxRate peticiones por segundo en promedio
+// durante el último minuto. Se instala después de Middleware, para que la
+// petición actual ya esté contada; las rechazadas también cuentan. A
+// diferencia de middleware.RateLimit, no lleva contadores propios.
+func (t *Tracker) Guard(maxRate float64) router.Middleware {
+	limit := int64(maxRate * windowSize)
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			st := t.GetRequest(r)
+			if st.RequestRate <= maxRate {
+				next.ServeHTTP(w, r)
+				return
+			}
+			t.mu.Lock()
+			var retry time.Duration
+			if c, ok := t.clients[st.Key]; ok {
+				retry = c.relief(t.now(), limit)
+			}
+			t.mu.Unlock()
+			middleware.TooManyRequests(w, r, middleware.LimitInfo{
+				Limit:      int(limit),
+				Window:     windowSize * time.Second,
+				Reset:      retry,
+				RetryAfter: retry,
+			})
+		})
+	}
+}
+
+// Top retorna los n clientes con mayor tasa de peticiones.
+func (t *Tracker) Top(n int) []ClientStats {
+	t.mu.Lock()
+	all := make([]ClientStats, 0, len(t.clients))
+	for key, c := range t.clients {
+		all = append(all, t.stats(key, c))
+	}
+	t.mu.Unlock()
+
+	slices.SortFunc(all, func(a, b ClientStats) int {
+		if c := cmp.Compare(b.RequestRate, a.RequestRate); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Requests, a.Requests)
+	})
+	if n > 0 && len(all) > n {
+		all = all[:n]
+	}
+	return all
+}
+
+// Prune descarta los clientes sin conexiones abiertas inactivos por más de
+// idle, acotando la memoria del Tracker.
+func (t *Tracker) Prune(idle time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	cutoff := t.now().Add(-idle)
+	for key, c := range t.clients {
+		if c.conns == 0 && c.lastSeen.Before(cutoff) {
+			delete(t.clients, key)
+		}
+	}
+}
+
+func hostOf(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+type countingReader struct {
+	io.ReadCloser
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+type countingWriter struct {
+	http.ResponseWriter
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+// Unwrap expone el writer original a http.ResponseController.
+func (w *countingWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,273 @@
+package accounting
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clock es un reloj manual para Tracker.now.
+type clock struct{ t time.Time }
+
+func (c *clock) now() time.Time          { return c.t }
+func (c *clock) advance(d time.Duration) { c.t = c.t.Add(d) }
+
+func newTracker(max int) (*Tracker, *clock) {
+	c := &clock{t: time.Unix(1_000_000, 0)}
+	t := New()
+	t.MaxClients = max
+	t.now = c.now
+	return t, c
+}
+
+func TestRate(t *testing.T) {
+	tr, c := newTracker(0)
+	steps := []struct {
+		name     string
+		advance  time.Duration
+		hits     int
+		requests int64
+		rate     float64
+	}{
+		{"primer segundo", 0, 30, 30, 0.5},
+		{"mismo segundo", 500 * time.Millisecond, 30, 60, 1},
+		{"otro segundo", 10 * time.Second, 60, 120, 2},
+		// A los 60s del primero, sus 60 peticiones salen de la ventana.
+		{"sale el primer segundo", 50 * time.Second, 0, 120, 1},
+		// El bucket del primer segundo se reutiliza desde cero.
+		{"reutiliza el bucket", 60 * time.Second, 6, 126, 0.1},
+		{"ventana vacía", 2 * time.Minute, 0, 126, 0},
+	}
+	for _, st := range steps {
+		c.advance(st.advance)
+		for range st.hits {
+			tr.hit("a")
+		}
+		got := tr.Get("a")
+		if got.Requests != st.requests || got.RequestRate != st.rate || (st.hits > 0 && !got.LastSeen.Equal(c.t)) {
+			t.Errorf("%s: Requests = %d, RequestRate = %v, LastSeen = %v; se esperaba %d, %v", st.name, got.Requests, got.RequestRate, got.LastSeen, st.requests, st.rate)
+		}
+	}
+	if got := tr.Get("nadie"); got != (ClientStats{Key: "nadie"}) {
+		t.Errorf("Get de un cliente desconocido = %+v", got)
+	}
+}
+
+func TestEvict(t *testing.T) {
+	tr, c := newTracker(10)
+	// El cliente 0 mantiene una conexión abierta: nunca se descarta.
+	conn := fakeConn{addr: "10.0.0.0:1"}
+	tr.ConnState(conn, http.StateNew)
+	for i := 1; i < 10; i++ {
+		c.advance(time.Second)
+		tr.hit(fmt.Sprint("10.0.0.", i))
+	}
+
+	// Lleno, el cliente nuevo desplaza al inactivo hace más tiempo (10%
+	// de 10 = 1).
+	tr.hit("nuevo")
+	if _, ok := tr.clients["10.0.0.1"]; ok {
+		t.Error("el cliente inactivo hace más tiempo debía descartarse")
+	}
+	if _, ok := tr.clients["10.0.0.0"]; !ok {
+		t.Error("un cliente con conexiones abiertas no debe descartarse")
+	}
+	if len(tr.clients) != 10 {
+		t.Errorf("clientes = %d, se esperaban 10", len(tr.clients))
+	}
+
+	// Si todos tienen conexiones abiertas, los nuevos se agrupan.
+	tr2, _ := newTracker(2)
+	tr2.ConnState(fakeConn{addr: "1.1.1.1:1"}, http.StateNew)
+	tr2.ConnState(fakeConn{addr: "2.2.2.2:1"}, http.StateNew)
+	tr2.hit("3.3.3.3")
+	tr2.hit("4.4.4.4")
+	if got := tr2.Get(overflowKey).Requests; got != 2 || len(tr2.clients) != 3 {
+		t.Errorf("%s: Requests = %d, clientes = %d; se esperaba 2 y 3", overflowKey, got, len(tr2.clients))
+	}
+}
+
+func TestTopAndPrune(t *testing.T) {
+	tr, c := newTracker(0)
+	for key, n := range map[string]int{"a": 5, "b": 20, "c": 1} {
+		for range n {
+			tr.hit(key)
+		}
+	}
+	c.advance(59 * time.Second)
+	tr.hit("c")
+	tr.hit("c")
+
+	var got []string
+	for _, st := range tr.Top(2) {
+		got = append(got, st.Key)
+	}
+	if strings.Join(got, ",") != "b,a" {
+		t.Errorf("Top(2) = %v, se esperaba b,a", got)
+	}
+	if all := tr.Top(0); len(all) != 3 {
+		t.Errorf("Top(0) = %d clientes, se esperaban todos", len(all))
+	}
+
+	// Un segundo después salen las peticiones iniciales: c pasa a liderar.
+	c.advance(time.Second)
+	if top := tr.Top(1); top[0].Key != "c" || top[0].Requests != 3 {
+		t.Errorf("Top(1) = %+v, se esperaba c", top)
+	}
+
+	conn := fakeConn{addr: "a:1"}
+	tr.ConnState(conn, http.StateNew)
+	tr.Prune(30 * time.Second)
+	if _, ok := tr.clients["b"]; ok {
+		t.Error("Prune debía descartar a b, inactivo hace 60s")
+	}
+	for _, key := range []string{"a", "c"} {
+		if _, ok := tr.clients[key]; !ok {
+			t.Errorf("Prune descartó a %s", key)
+		}
+	}
+}
+
+type fakeConn struct {
+	net.Conn
+	addr string
+}
+
+func (c fakeConn) RemoteAddr() net.Addr { return addr(c.addr) }
+
+type addr string
+
+func (a addr) Network() string { return "tcp" }
+func (a addr) String() string  { return string(a) }
+
+func TestConnState(t *testing.T) {
+	tr, _ := newTracker(0)
+	a, b := fakeConn{addr: "10.0.0.1:1000"}, fakeConn{addr: "10.0.0.1:1001"}
+	steps := []struct {
+		conn  fakeConn
+		state http.ConnState
+		want  int64
+	}{
+		{a, http.StateNew, 1},
+		{b, http.StateNew, 2},
+		{a, http.StateActive, 2},
+		{a, http.StateClosed, 1},
+		{a, http.StateClosed, 1},
+		{b, http.StateHijacked, 0},
+	}
+	for i, st := range steps {
+		tr.ConnState(st.conn, st.state)
+		if got := tr.Get("10.0.0.1").Connections; got != st.want {
+			t.Errorf("paso %d: Connections = %d, se esperaba %d", i, got, st.want)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	tr, _ := newTracker(0)
+	tr.KeyFunc = func(r *http.Request) string { return r.Header.Get("X-Api-Key") }
+	h := tr.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		w.Write(append(b, b...))
+	}))
+	for _, body := range []string{"hola", "mundo"} {
+		r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+		r.Header.Set("X-Api-Key", "k1")
+		h.ServeHTTP(httptest.NewRecorder(), r)
+	}
+	got := tr.Get("k1")
+	if got.Requests != 2 || got.BytesIn != 9 || got.BytesOut != 18 {
+		t.Errorf("Get = %+v, se esperaban 2 peticiones, 9 bytes de entrada y 18 de salida", got)
+	}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("X-Api-Key", "k1")
+	if tr.GetRequest(r).Requests != 2 {
+		t.Error("GetRequest debe usar KeyFunc")
+	}
+}
+
+func TestGuard(t *testing.T) {
+	tr, c := newTracker(0)
+	// 3 peticiones por minuto.
+	h := tr.Middleware()(tr.Guard(0.05)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	steps := []struct {
+		advance time.Duration
+		ip      string
+		status  int
+		retry   string
+	}{
+		{0, "10.0.0.1:1", http.StatusOK, ""},
+		{10 * time.Second, "10.0.0.1:1", http.StatusOK, ""},
+		{10 * time.Second, "10.0.0.1:1", http.StatusOK, ""},
+		// La cuarta excede; la primera sale de la ventana en 30s.
+		{10 * time.Second, "10.0.0.1:1", http.StatusTooManyRequests, "30"},
+		{0, "10.0.0.2:1", http.StatusOK, ""},
+		{30 * time.Second, "10.0.0.1:1", http.StatusTooManyRequests, "10"},
+		{60 * time.Second, "10.0.0.1:1", http.StatusOK, ""},
+	}
+	for i, st := range steps {
+		c.advance(st.advance)
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.RemoteAddr = st.ip
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != st.status || w.Header().Get("Retry-After") != st.retry {
+			t.Errorf("paso %d: status = %d, Retry-After = %q; se esperaba %d, %q", i, w.Code, w.Header().Get("Retry-After"), st.status, st.retry)
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	tr, _ := newTracker(0)
+	for i := range 150 {
+		tr.hit(fmt.Sprint("c", i))
+	}
+	tests := []struct {
+		name   string
+		method string
+		query  string
+		status int
+		count  int
+	}{
+		{"top por defecto", http.MethodGet, "", http.StatusOK, defaultTop},
+		{"top explícito", http.MethodGet, "?top=5", http.StatusOK, 5},
+		{"top cero", http.MethodGet, "?top=0", http.StatusOK, defaultTop},
+		{"top acotado", http.MethodGet, "?top=1000", http.StatusOK, maxTop},
+		{"top negativo", http.MethodGet, "?top=-1", http.StatusBadRequest, 0},
+		{"top inválido", http.MethodGet, "?top=x", http.StatusBadRequest, 0},
+		{"cliente", http.MethodGet, "?client=c7", http.StatusOK, 1},
+		{"método no permitido", http.MethodPost, "", http.StatusMethodNotAllowed, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tr.Handler().ServeHTTP(w, httptest.NewRequest(tt.method, "/"+tt.query, nil))
+			if w.Code != tt.status {
+				t.Fatalf("status = %d, se esperaba %d", w.Code, tt.status)
+			}
+			if tt.status != http.StatusOK {
+				return
+			}
+			var list []ClientStats
+			if strings.HasPrefix(tt.query, "?client") {
+				var one ClientStats
+				json.Unmarshal(w.Body.Bytes(), &one)
+				list = append(list, one)
+				if one.Key != "c7" || one.Requests != 1 {
+					t.Errorf("cliente = %+v", one)
+				}
+			} else if err := json.Unmarshal(w.Body.Bytes(), &list); err != nil {
+				t.Fatal(err)
+			}
+			if len(list) != tt.count {
+				t.Errorf("%d clientes, se esperaban %d", len(list), tt.count)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package accounting
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// Límites de ?top en Handler: sin valor o con 0 se usa defaultTop.
+const (
+	defaultTop = 20
+	maxTop     = 100
+)
+
+// Handler expone el Tracker como API administrativa en JSON:
+//
+//	GET ?client=<key>  estadísticas de un cliente
+//	GET ?top=<n>       los n clientes más activos (20 por defecto, 100 como máximo)
+//
+// Debe montarse detrás de la autenticación de administración.
+func (t *Tracker) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		var payload any
+		if key := r.URL.Query().Get("client"); key != "" {
+			payload = t.Get(key)
+		} else {
+			n := defaultTop
+			if v := r.URL.Query().Get("top"); v != "" {
+				parsed, err := strconv.Atoi(v)
+				if err != nil || parsed < 0 {
+					http.Error(w, "top inválido", http.StatusBadRequest)
+					return
+				}
+				if parsed > 0 {
+					n = min(parsed, maxTop)
+				}
+			}
+			payload = t.Top(n)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Cache-Control", "no-store")
+		_ = json.NewEncoder(w).Encode(payload)
+	})
+}
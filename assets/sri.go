@@ -0,0 +1,86 @@
+// Package assets reúne utilidades para los archivos estáticos servidos por
+// la aplicación.
+package assets
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"html/template"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strings"
+)
+
+// DefaultSRIExtensions son las extensiones para las que BuildSRI calcula
+// hashes cuando no se indican otras.
+var DefaultSRIExtensions = []string{".js", ".mjs", ".css"}
+
+// SRI guarda los hashes de Subresource Integrity (sha384) de un conjunto de
+// archivos, indexados por su ruta dentro del fs.FS.
+type SRI struct {
+	hashes map[string]string
+}
+
+// BuildSRI recorre fsys y calcula el hash de cada archivo cuya extensión
+// esté en exts (DefaultSRIExtensions si se omite).
+func BuildSRI(fsys fs.FS, exts ...string) (*SRI, error) {
+	if len(exts) == 0 {
+		exts = DefaultSRIExtensions
+	}
+	m := &SRI{hashes: make(map[string]string)}
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !slices.Contains(exts, path.Ext(p)) {
+			return err
+		}
+		f, err := fsys.Open(p)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		h := sha512.New384()
+		if _, err := io.Copy(h, f); err != nil {
+			return err
+		}
+		m.hashes[p] = "sha384-" + base64.StdEncoding.EncodeToString(h.Sum(nil))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// Integrity retorna el valor del atributo integrity para name, que puede
+// llevar "/" inicial. Retorna "" si el archivo no está en el manifiesto.
+func (m *SRI) Integrity(name string) string {
+	return m.hashes[strings.TrimPrefix(name, "/")]
+}
+
+// Manifest retorna una copia de todos los hashes calculados.
+func (m *SRI) Manifest() map[string]string {
+	out := make(map[string]string, len(m.hashes))
+	for k, v := range m.hashes {
+		out[k] = v
+	}
+	return out
+}
+
+// FuncMap expone a las plantillas la función sri, que retorna el hash de
+// un archivo, y sri_attrs, que retorna los atributos integrity y
+// crossorigin listos para insertar en la etiqueta:
+//
+//	<script src="/static/app.js" {{sri_attrs "app.js"}}></script>
+func (m *SRI) FuncMap() template.FuncMap {
+	return template.FuncMap{
+		"sri": m.Integrity,
+		"sri_attrs": func(name string) template.HTMLAttr {
+			v := m.Integrity(name)
+			if v == "" {
+				return ""
+			}
+			return template.HTMLAttr(`integrity="` + v + `" crossorigin="anonymous"`)
+		},
+	}
+}
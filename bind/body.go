@@ -0,0 +1,218 @@
+package bind
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+
+	"github.com/profe-ajedrez/transwarp/codec"
+)
+
+// Errores retornados por Body.
+var (
+	ErrUnsupportedMediaType = errors.New("bind: content-type no soportado")
+	ErrTooLarge             = errors.New("bind: el cuerpo excede el tamaño máximo")
+	ErrUnknownField         = errors.New("bind: campo desconocido")
+)
+
+// Binder decodifica cuerpos de petición según su Content-Type.
+type Binder struct {
+	// MaxBytes limita el tamaño del cuerpo; cero significa 10 MiB.
+	MaxBytes int64
+	// MaxMemory es cuánto de un multipart se retiene en memoria antes de
+	// pasar a archivos temporales; cero significa 32 MiB.
+	MaxMemory int64
+	// Strict rechaza campos desconocidos en JSON y en formularios.
+	Strict bool
+}
+
+// DefaultBinder es el Binder que usa Body.
+var DefaultBinder = &Binder{}
+
+// Body decodifica el cuerpo de r en dst con DefaultBinder.
+func Body(r *http.Request, dst any) error {
+	return DefaultBinder.Body(r, dst)
+}
+
+// Body decodifica el cuerpo de r en dst según su Content-Type:
+//
+//   - application/json y +json con encoding/json
+//   - application/xml, text/xml y +xml con encoding/xml
+//   - application/x-www-form-urlencoded con etiquetas `form:"nombre"`
+//   - multipart/form-data con etiquetas `form:"nombre"`; los campos de
+//     tipo *multipart.FileHeader o []*multipart.FileHeader reciben los
+//     archivos
+//   - cualquier otro media type registrado con codec.Register
+//
+// Para formularios, dst debe ser un puntero a struct.
+func (b *Binder) Body(r *http.Request, dst any) error {
+	mediaType, _, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if err != nil {
+		return ErrUnsupportedMediaType
+	}
+
+	limit := b.MaxBytes
+	if limit <= 0 {
+		limit = 10 << 20
+	}
+	body := &limitedReader{r: r.Body, n: limit}
+	r.Body = struct {
+		io.Reader
+		io.Closer
+	}{body, r.Body}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		dec := json.NewDecoder(r.Body)
+		if b.Strict {
+			dec.DisallowUnknownFields()
+		}
+		err = dec.Decode(dst)
+		if err == nil && dec.More() {
+			err = errors.New("bind: contenido extra después del JSON")
+		}
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		err = xml.NewDecoder(r.Body).Decode(dst)
+	case mediaType == "application/x-www-form-urlencoded":
+		if err = r.ParseForm(); err == nil {
+			err = b.decodeForm(r.PostForm, nil, dst)
+		}
+	case mediaType == "multipart/form-data":
+		maxMemory := b.MaxMemory
+		if maxMemory <= 0 {
+			maxMemory = 32 << 20
+		}
+		if err = r.ParseMultipartForm(maxMemory); err == nil {
+			err = b.decodeForm(r.MultipartForm.Value, r.MultipartForm.File, dst)
+		}
+	default:
+		c, ok := codec.Lookup(mediaType)
+		if !ok {
+			return ErrUnsupportedMediaType
+		}
+		var data []byte
+		if data, err = io.ReadAll(r.Body); err == nil {
+			if len(data) == 0 {
+				err = io.EOF
+			} else {
+				err = c.Unmarshal(data, dst)
+			}
+		}
+	}
+
+	if body.exceeded {
+		return ErrTooLarge
+	}
+	if errors.Is(err, io.EOF) {
+		return errors.New("bind: cuerpo vacío")
+	}
+	return err
+}
+
+func (b *Binder) decodeForm(values url.Values, files map[string][]*multipart.FileHeader, dst any) error {
+	if b.Strict {
+		known := formFields(reflect.TypeOf(dst))
+		for key := range values {
+			if !known[key] {
+				return fmt.Errorf("%w: %s", ErrUnknownField, key)
+			}
+		}
+		for key := range files {
+			if !known[key] {
+				return fmt.Errorf("%w: %s", ErrUnknownField, key)
+			}
+		}
+	}
+	if err := decodeValues(values, dst, "form"); err != nil {
+		return err
+	}
+	if len(files) > 0 {
+		assignFiles(reflect.ValueOf(dst).Elem(), files)
+	}
+	return nil
+}
+
+var (
+	fileHeaderType  = reflect.TypeFor[*multipart.FileHeader]()
+	fileHeadersType = reflect.TypeFor[[]*multipart.FileHeader]()
+)
+
+func assignFiles(v reflect.Value, files map[string][]*multipart.FileHeader) {
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			assignFiles(v.Field(i), files)
+			continue
+		}
+		name := field.Tag.Get("form")
+		fhs := files[name]
+		if !field.IsExported() || name == "" || len(fhs) == 0 {
+			continue
+		}
+		switch field.Type {
+		case fileHeaderType:
+			v.Field(i).Set(reflect.ValueOf(fhs[0]))
+		case fileHeadersType:
+			v.Field(i).Set(reflect.ValueOf(fhs))
+		}
+	}
+}
+
+// formFields retorna los nombres de formulario declarados en t.
+func formFields(t reflect.Type) map[string]bool {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	known := map[string]bool{}
+	if t.Kind() != reflect.Struct {
+		return known
+	}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			for k := range formFields(field.Type) {
+				known[k] = true
+			}
+			continue
+		}
+		if name := field.Tag.Get("form"); name != "" && name != "-" {
+			known[name] = true
+		}
+	}
+	return known
+}
+
+// limitedReader es como io.LimitReader pero recuerda si el cuerpo superó
+// el límite, para distinguirlo de un cuerpo mal formado.
+type limitedReader struct {
+	r        io.Reader
+	n        int64
+	exceeded bool
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	if l.n <= 0 {
+		// Se intenta leer un byte más para saber si el cuerpo continúa.
+		var one [1]byte
+		if n, _ := l.r.Read(one[:]); n > 0 {
+			l.exceeded = true
+			return 0, ErrTooLarge
+		}
+		return 0, io.EOF
+	}
+	if int64(len(p)) > l.n {
+		p = p[:l.n]
+	}
+	n, err := l.r.Read(p)
+	l.n -= int64(n)
+	return n, err
+}
@@ -0,0 +1,107 @@
+package bind
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/profe-ajedrez/transwarp/codec"
+)
+
+type order struct {
+	ID    int      `json:"id" xml:"id" form:"id"`
+	Name  string   `json:"name" xml:"name" form:"name"`
+	Tags  []string `json:"tags" xml:"tag" form:"tag"`
+	Price float64  `json:"price" xml:"price" form:"price"`
+}
+
+func request(contentType, body string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	r.Header.Set("Content-Type", contentType)
+	return r
+}
+
+func TestBodyRoundTrip(t *testing.T) {
+	want := order{ID: 7, Name: "café", Tags: []string{"a", "b"}, Price: 9.5}
+	codec.Register("application/x-test", codec.Funcs{MarshalFunc: json.Marshal, UnmarshalFunc: json.Unmarshal})
+
+	jsonBody, _ := json.Marshal(want)
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{"json", "application/json; charset=utf-8", string(jsonBody)},
+		{"json con sufijo", "application/vnd.api+json", string(jsonBody)},
+		{"xml", "application/xml", "<order><id>7</id><name>café</name><tag>a</tag><tag>b</tag><price>9.5</price></order>"},
+		{"formulario", "application/x-www-form-urlencoded", "id=7&name=caf%C3%A9&tag=a&tag=b&price=9.5"},
+		{"codec registrado", "application/x-test", string(jsonBody)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got order
+			if err := Body(request(tt.contentType, tt.body), &got); err != nil {
+				t.Fatal(err)
+			}
+			if got.ID != want.ID || got.Name != want.Name || strings.Join(got.Tags, ",") != "a,b" || got.Price != want.Price {
+				t.Errorf("Body = %+v, se esperaba %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestBodyMultipart(t *testing.T) {
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	mw.WriteField("name", "café")
+	fw, _ := mw.CreateFormFile("file", "a.txt")
+	fw.Write([]byte("hola"))
+	mw.Close()
+
+	var dst struct {
+		Name string                `form:"name"`
+		File *multipart.FileHeader `form:"file"`
+	}
+	if err := Body(request(mw.FormDataContentType(), buf.String()), &dst); err != nil {
+		t.Fatal(err)
+	}
+	if dst.Name != "café" || dst.File == nil || dst.File.Filename != "a.txt" || dst.File.Size != 4 {
+		t.Errorf("Body = %+v", dst)
+	}
+}
+
+func TestBodyErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		binder      *Binder
+		contentType string
+		body        string
+		want        error
+	}{
+		{"sin Content-Type", DefaultBinder, "", "{}", ErrUnsupportedMediaType},
+		{"tipo desconocido", DefaultBinder, "application/x-desconocido", "{}", ErrUnsupportedMediaType},
+		{"excede MaxBytes", &Binder{MaxBytes: 8}, "application/json", `{"name":"largo"}`, ErrTooLarge},
+		{"campo desconocido JSON", &Binder{Strict: true}, "application/json", `{"otro":1}`, nil},
+		{"campo desconocido formulario", &Binder{Strict: true}, "application/x-www-form-urlencoded", "otro=1", ErrUnknownField},
+		{"valor inválido", DefaultBinder, "application/x-www-form-urlencoded", "id=x", nil},
+		{"cuerpo vacío", DefaultBinder, "application/json", "", nil},
+		{"JSON con contenido extra", DefaultBinder, "application/json", `{"id":1}{"id":2}`, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var dst order
+			err := tt.binder.Body(request(tt.contentType, tt.body), &dst)
+			if err == nil {
+				t.Fatal("se esperaba error")
+			}
+			if tt.want != nil && !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, se esperaba %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+// Package bind decodifica los datos de una petición en structs.
+package bind
+
+import "net/http"
+
+// Query asigna los parámetros de query de r a los campos de dst
+// etiquetados con `query:"nombre"`. Admite strings, enteros, flotantes,
+// booleanos, time.Time (RFC 3339 o fecha), time.Duration, punteros,
+// encoding.TextUnmarshaler y slices de todos ellos a partir de
+// parámetros repetidos (?tag=a&tag=b). Los parámetros ausentes dejan el
+// campo intacto, de modo que dst puede llevar valores por defecto.
+func Query(r *http.Request, dst any) error {
+	return decodeValues(r.URL.Query(), dst, "query")
+}
@@ -0,0 +1,104 @@
+package bind
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+	"time"
+)
+
+type filter struct {
+	Q       string        `query:"q"`
+	Page    int           `query:"page"`
+	Ratio   float64       `query:"ratio"`
+	Active  bool          `query:"active"`
+	Since   time.Time     `query:"since"`
+	Timeout time.Duration `query:"timeout"`
+	Limit   *int          `query:"limit"`
+	Tags    []string      `query:"tag"`
+	IDs     []int         `query:"id"`
+	Addr    netip.Addr    `query:"addr"`
+	Ignored string        `query:"-"`
+	pager
+}
+
+type pager struct {
+	Size int `query:"size"`
+}
+
+func TestQuery(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/?q=caf%C3%A9&page=2&ratio=0.5&active=true&since=2024-01-02"+
+		"&timeout=1m30s&limit=10&tag=a&tag=b&id=1&id=2&addr=10.0.0.1&Ignored=x&size=50", nil)
+	var f filter
+	if err := Query(r, &f); err != nil {
+		t.Fatal(err)
+	}
+	checks := []struct {
+		field string
+		got   any
+		want  any
+	}{
+		{"q", f.Q, "café"},
+		{"page", f.Page, 2},
+		{"ratio", f.Ratio, 0.5},
+		{"active", f.Active, true},
+		{"since", f.Since, time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"timeout", f.Timeout, 90 * time.Second},
+		{"limit", f.Limit != nil && *f.Limit == 10, true},
+		{"tag", len(f.Tags) == 2 && f.Tags[1] == "b", true},
+		{"id", len(f.IDs) == 2 && f.IDs[1] == 2, true},
+		{"addr", f.Addr, netip.MustParseAddr("10.0.0.1")},
+		{"-", f.Ignored, ""},
+		{"size", f.Size, 50},
+	}
+	for _, c := range checks {
+		if c.got != c.want {
+			t.Errorf("%s = %v, se esperaba %v", c.field, c.got, c.want)
+		}
+	}
+}
+
+func TestQueryKeepsDefaults(t *testing.T) {
+	f := filter{Page: 1, Q: "todo"}
+	if err := Query(httptest.NewRequest(http.MethodGet, "/?size=5", nil), &f); err != nil {
+		t.Fatal(err)
+	}
+	if f.Page != 1 || f.Q != "todo" {
+		t.Errorf("los campos ausentes deben conservar su valor: %+v", f)
+	}
+}
+
+func TestQueryErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		field string
+	}{
+		{"entero inválido", "page=uno", "page"},
+		{"booleano inválido", "active=quizás", "active"},
+		{"fecha inválida", "since=ayer", "since"},
+		{"slice inválido", "id=1&id=x", "id"},
+		{"TextUnmarshaler inválido", "addr=999.0.0.1", "addr"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var f filter
+			err := Query(httptest.NewRequest(http.MethodGet, "/?"+tt.query, nil), &f)
+			var fe *FieldError
+			if !errors.As(err, &fe) || fe.Field != tt.field {
+				t.Errorf("err = %v, se esperaba FieldError de %s", err, tt.field)
+			}
+		})
+	}
+}
+
+func TestQueryInvalidDst(t *testing.T) {
+	var n int
+	for _, dst := range []any{nil, n, &n, filter{}} {
+		if err := Query(httptest.NewRequest(http.MethodGet, "/", nil), dst); err == nil {
+			t.Errorf("Query(%T) debe fallar", dst)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package bind
+
+import (
+	"encoding"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"time"
+)
+
+// FieldError describe un valor que no pudo asignarse a un campo.
+type FieldError struct {
+	Field string
+	Value string
+	Err   error
+}
+
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("bind: %s=%q: %v", e.Field, e.Value, e.Err)
+}
+
+func (e *FieldError) Unwrap() error { return e.Err }
+
+// timeLayouts son los formatos aceptados para time.Time, en orden.
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02T15:04:05", "2006-01-02"}
+
+var (
+	timeType            = reflect.TypeFor[time.Time]()
+	durationType        = reflect.TypeFor[time.Duration]()
+	textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// decodeValues asigna values a los campos de dst (puntero a struct)
+// etiquetados con tag. Los campos sin etiqueta o con "-" se ignoran y los
+// structs embebidos se recorren.
+func decodeValues(values url.Values, dst any, tag string) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New("bind: el destino debe ser un puntero a struct")
+	}
+	return decodeStruct(values, v.Elem(), tag)
+}
+
+func decodeStruct(values url.Values, v reflect.Value, tag string) error {
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		fv := v.Field(i)
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if err := decodeStruct(values, fv, tag); err != nil {
+				return err
+			}
+			continue
+		}
+		if !field.IsExported() {
+			continue
+		}
+		name := field.Tag.Get(tag)
+		if name == "" || name == "-" {
+			continue
+		}
+		raw, ok := values[name]
+		if !ok || len(raw) == 0 {
+			continue
+		}
+		if err := setField(fv, raw); err != nil {
+			return &FieldError{Field: name, Value: raw[0], Err: err}
+		}
+	}
+	return nil
+}
+
+func setField(fv reflect.Value, raw []string) error {
+	if fv.Kind() == reflect.Slice && fv.Type() != reflect.TypeFor[[]byte]() {
+		out := reflect.MakeSlice(fv.Type(), len(raw), len(raw))
+		for i, s := range raw {
+			if err := setScalar(out.Index(i), s); err != nil {
+				return err
+			}
+		}
+		fv.Set(out)
+		return nil
+	}
+	return setScalar(fv, raw[len(raw)-1])
+}
+
+func setScalar(fv reflect.Value, s string) error {
+	if fv.Kind() == reflect.Pointer {
+		elem := reflect.New(fv.Type().Elem())
+		if err := setScalar(elem.Elem(), s); err != nil {
+			return err
+		}
+		fv.Set(elem)
+		return nil
+	}
+
+	if fv.Type() == timeType {
+		for _, layout := range timeLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				fv.Set(reflect.ValueOf(t))
+				return nil
+			}
+		}
+		return errors.New("fecha inválida")
+	}
+	if fv.Type() == durationType {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return errors.New("duración inválida")
+		}
+		fv.SetInt(int64(d))
+		return nil
+	}
+	if fv.CanAddr() && fv.Addr().Type().Implements(textUnmarshalerType) {
+		return fv.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+
+	switch fv.Kind() {
+	case reflect.String:
+		fv.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			if s != "on" && s != "off" {
+				return errors.New("se esperaba un booleano")
+			}
+			b = s == "on"
+		}
+		fv.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, fv.Type().Bits())
+		if err != nil {
+			return errors.New("se esperaba un entero")
+		}
+		fv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, fv.Type().Bits())
+		if err != nil {
+			return errors.New("se esperaba un entero sin signo")
+		}
+		fv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(s, fv.Type().Bits())
+		if err != nil {
+			return errors.New("se esperaba un número")
+		}
+		fv.SetFloat(f)
+	default:
+		return fmt.Errorf("tipo no soportado %s", fv.Type())
+	}
+	return nil
+}
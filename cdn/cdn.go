@@ -0,0 +1,72 @@
+// Package cdn une la caché de origen con la del borde: emite los headers
+// Surrogate-Key y Cache-Tag que los CDN usan para agrupar objetos, y define
+// un cliente de purga por clave con implementaciones para Fastly y
+// Cloudflare.
+package cdn
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// SetKeys agrega keys a los headers Surrogate-Key (Fastly y compatibles,
+// separado por espacios) y Cache-Tag (Cloudflare, separado por comas),
+// sin duplicar claves ya presentes.
+func SetKeys(h http.Header, keys ...string) {
+	current := strings.Fields(h.Get("Surrogate-Key"))
+	for _, k := range keys {
+		k = strings.TrimSpace(k)
+		if k != "" && !slices.Contains(current, k) {
+			current = append(current, k)
+		}
+	}
+	if len(current) == 0 {
+		return
+	}
+	h.Set("Surrogate-Key", strings.Join(current, " "))
+	h.Set("Cache-Tag", strings.Join(current, ","))
+}
+
+// Keys retorna un middleware que etiqueta las respuestas de una ruta o
+// grupo con claves fijas.
+func Keys(keys ...string) router.Middleware {
+	return KeysFunc(func(*http.Request) []string { return keys })
+}
+
+// KeysFunc retorna un middleware que etiqueta las respuestas con las
+// claves derivadas de la petición, p. ej. "user-" + Param(r, "id").
+func KeysFunc(fn func(r *http.Request) []string) router.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			SetKeys(w.Header(), fn(r)...)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// Purger invalida en el CDN los objetos etiquetados con alguna de las claves.
+type Purger interface {
+	Purge(ctx context.Context, keys ...string) error
+}
+
+// Multi propaga cada purga a todos los purgers y reúne sus errores.
+func Multi(purgers ...Purger) Purger {
+	return multi(purgers)
+}
+
+type multi []Purger
+
+func (m multi) Purge(ctx context.Context, keys ...string) error {
+	var errs []error
+	for _, p := range m {
+		if err := p.Purge(ctx, keys...); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
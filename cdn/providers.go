@@ -0,0 +1,111 @@
+package cdn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Fastly purga por surrogate key usando la API de purga por lotes.
+type Fastly struct {
+	ServiceID string
+	Token     string
+	// Soft marca los objetos como obsoletos en lugar de eliminarlos.
+	Soft bool
+	// Client es el cliente HTTP; por defecto http.DefaultClient.
+	Client *http.Client
+	// Endpoint permite apuntar a otra URL base (pruebas, proxies).
+	Endpoint string
+}
+
+// Purge implementa Purger.
+func (f *Fastly) Purge(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	endpoint := f.Endpoint
+	if endpoint == "" {
+		endpoint = "https://api.fastly.com"
+	}
+	// Fastly acepta hasta 256 claves por petición.
+	for chunk := range slices.Chunk(keys, 256) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+			strings.TrimRight(endpoint, "/")+"/service/"+url.PathEscape(f.ServiceID)+"/purge", nil)
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Fastly-Key", f.Token)
+		req.Header.Set("Surrogate-Key", strings.Join(chunk, " "))
+		req.Header.Set("Accept", "application/json")
+		if f.Soft {
+			req.Header.Set("Fastly-Soft-Purge", "1")
+		}
+		if err := do(f.Client, req, "fastly"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Cloudflare purga por Cache-Tag en una zona.
+type Cloudflare struct {
+	ZoneID string
+	// Token es un API token con permiso de purga de caché.
+	Token string
+	// Client es el cliente HTTP; por defecto http.DefaultClient.
+	Client *http.Client
+	// Endpoint permite apuntar a otra URL base (pruebas, proxies).
+	Endpoint string
+}
+
+// Purge implementa Purger.
+func (c *Cloudflare) Purge(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	endpoint := c.Endpoint
+	if endpoint == "" {
+		endpoint = "https://api.cloudflare.com/client/v4"
+	}
+	// Cloudflare acepta hasta 30 tags por petición.
+	for chunk := range slices.Chunk(keys, 30) {
+		body, err := json.Marshal(map[string][]string{"tags": chunk})
+		if err != nil {
+			return err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+			strings.TrimRight(endpoint, "/")+"/zones/"+url.PathEscape(c.ZoneID)+"/purge_cache", bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+c.Token)
+		req.Header.Set("Content-Type", "application/json")
+		if err := do(c.Client, req, "cloudflare"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func do(client *http.Client, req *http.Request, provider string) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("cdn: %s: %w", provider, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("cdn: %s: purga rechazada (%d): %s", provider, resp.StatusCode, bytes.TrimSpace(msg))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
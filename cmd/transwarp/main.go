@@ -0,0 +1,35 @@
+// Command transwarp agrupa herramientas de desarrollo para aplicaciones
+// transwarp.
+//
+//	transwarp size [-tags "gin;chi;fiber"] [-init] [paquete]
+//
+// size compila el paquete una vez por cada conjunto de build tags
+// (separados por ";"; dentro de un conjunto, por ",") y reporta el tamaño
+// del binario, la cantidad de dependencias y, con -init, el costo de
+// inicialización medido con GODEBUG=inittrace=1.
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+	switch os.Args[1] {
+	case "size":
+		if err := size(os.Args[2:]); err != nil {
+			fmt.Fprintln(os.Stderr, "transwarp:", err)
+			os.Exit(1)
+		}
+	default:
+		usage()
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "uso: transwarp size [-tags \"a;b,c\"] [-init] [paquete]")
+	os.Exit(2)
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+type sizeReport struct {
+	tags    string
+	bytes   int64
+	deps    int
+	modules int
+	init    time.Duration
+	initErr error
+}
+
+func size(args []string) error {
+	fs := flag.NewFlagSet("size", flag.ExitOnError)
+	tags := fs.String("tags", "", `conjuntos de build tags separados por ";" (vacío compila sin tags)`)
+	measureInit := fs.Bool("init", false, "ejecuta el binario con GODEBUG=inittrace=1 para medir la inicialización")
+	fs.Parse(args)
+
+	pkg := "."
+	if fs.NArg() > 0 {
+		pkg = fs.Arg(0)
+	}
+	sets := strings.Split(*tags, ";")
+
+	dir, err := os.MkdirTemp("", "transwarp-size-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	var reports []sizeReport
+	for i, set := range sets {
+		set = strings.TrimSpace(set)
+		rep := sizeReport{tags: set}
+		bin := filepath.Join(dir, "app"+strconv.Itoa(i))
+		if out, err := goCmd("build", "-tags", set, "-o", bin, pkg); err != nil {
+			return fmt.Errorf("build -tags %q: %v\n%s", set, err, out)
+		}
+		st, err := os.Stat(bin)
+		if err != nil {
+			return err
+		}
+		rep.bytes = st.Size()
+
+		out, err := goCmd("list", "-deps", "-tags", set, "-f", "{{if not .Standard}}{{.ImportPath}} {{with .Module}}{{.Path}}{{end}}{{end}}", pkg)
+		if err != nil {
+			return fmt.Errorf("list -tags %q: %v\n%s", set, err, out)
+		}
+		mods := map[string]bool{}
+		sc := bufio.NewScanner(bytes.NewReader(out))
+		for sc.Scan() {
+			fields := strings.Fields(sc.Text())
+			if len(fields) == 0 {
+				continue
+			}
+			rep.deps++
+			if len(fields) > 1 {
+				mods[fields[1]] = true
+			}
+		}
+		rep.modules = len(mods)
+
+		if *measureInit {
+			rep.init, rep.initErr = initCost(bin)
+		}
+		reports = append(reports, rep)
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "TAGS\tTAMAÑO\tPAQUETES\tMÓDULOS\tINIT")
+	for _, r := range reports {
+		name := r.tags
+		if name == "" {
+			name = "(sin tags)"
+		}
+		init := "-"
+		switch {
+		case r.initErr != nil:
+			init = "error: " + r.initErr.Error()
+		case *measureInit:
+			init = r.init.Round(10 * time.Microsecond).String()
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%s\n", name, humanBytes(r.bytes), r.deps, r.modules, init)
+	}
+	return tw.Flush()
+}
+
+func goCmd(args ...string) ([]byte, error) {
+	cmd := exec.Command("go", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return stderr.Bytes(), err
+	}
+	return out, nil
+}
+
+var initLine = regexp.MustCompile(`^init \S+ @\S+ ms, ([0-9.]+) ms clock`)
+
+// initCost ejecuta bin brevemente con inittrace y suma el tiempo de reloj
+// de cada init. El proceso se detiene pasado un segundo, ya que una
+// aplicación normalmente queda sirviendo.
+func initCost(bin string) (time.Duration, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	cmd := exec.CommandContext(ctx, bin)
+	cmd.Env = append(os.Environ(), "GODEBUG=inittrace=1")
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if err != nil && ctx.Err() == nil && !errors.As(err, &exitErr) {
+		return 0, err
+	}
+
+	var total float64
+	found := false
+	sc := bufio.NewScanner(&stderr)
+	for sc.Scan() {
+		if m := initLine.FindStringSubmatch(sc.Text()); m != nil {
+			ms, _ := strconv.ParseFloat(m[1], 64)
+			total += ms
+			found = true
+		}
+	}
+	if !found {
+		return 0, errors.New("sin datos de inittrace")
+	}
+	return time.Duration(total * float64(time.Millisecond)), nil
+}
+
+func humanBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return strconv.FormatInt(n, 10) + " B"
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
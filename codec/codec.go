@@ -0,0 +1,132 @@
+// Package codec registra codificaciones de cuerpo por media type, para que
+// bind y render acepten y produzcan formatos distintos de JSON (por
+// ejemplo MessagePack o Protobuf) sin salir del modelo de handlers de
+// transwarp.
+//
+// El módulo no depende de librerías de MessagePack ni de Protobuf: se
+// registran con Funcs usando la librería que prefiera la aplicación.
+//
+//	codec.Register("application/x-protobuf", codec.Funcs{
+//		MarshalFunc: func(v any) ([]byte, error) { return proto.Marshal(v.(proto.Message)) },
+//		UnmarshalFunc: func(b []byte, v any) error { return proto.Unmarshal(b, v.(proto.Message)) },
+//	})
+//	codec.Register("application/msgpack", codec.Funcs{
+//		MarshalFunc: msgpack.Marshal, UnmarshalFunc: msgpack.Unmarshal,
+//	})
+package codec
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Codec serializa y deserializa valores en un formato.
+type Codec interface {
+	Marshal(v any) ([]byte, error)
+	Unmarshal(data []byte, v any) error
+}
+
+// Funcs adapta un par de funciones a Codec.
+type Funcs struct {
+	MarshalFunc   func(v any) ([]byte, error)
+	UnmarshalFunc func(data []byte, v any) error
+}
+
+func (f Funcs) Marshal(v any) ([]byte, error)      { return f.MarshalFunc(v) }
+func (f Funcs) Unmarshal(data []byte, v any) error { return f.UnmarshalFunc(data, v) }
+
+var (
+	mu     sync.RWMutex
+	codecs = map[string]Codec{}
+	order  []string
+)
+
+func init() {
+	Register("application/json", Funcs{MarshalFunc: json.Marshal, UnmarshalFunc: json.Unmarshal})
+	Register("application/xml", Funcs{MarshalFunc: xml.Marshal, UnmarshalFunc: xml.Unmarshal})
+}
+
+// Register asocia c al media type mediaType (sin parámetros), reemplazando
+// el anterior si existía.
+func Register(mediaType string, c Codec) {
+	mediaType = strings.ToLower(mediaType)
+	mu.Lock()
+	defer mu.Unlock()
+	if _, ok := codecs[mediaType]; !ok {
+		order = append(order, mediaType)
+	}
+	codecs[mediaType] = c
+}
+
+// Lookup retorna el Codec del Content-Type contentType, que puede incluir
+// parámetros.
+func Lookup(contentType string) (Codec, bool) {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, false
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	c, ok := codecs[mt]
+	return c, ok
+}
+
+// Negotiate elige, según el header Accept, el media type registrado
+// preferido por el cliente. Sin Accept, o con */*, elige fallback.
+// ok=false indica que ningún codec registrado es aceptable.
+func Negotiate(accept, fallback string) (mediaType string, c Codec, ok bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	if strings.TrimSpace(accept) == "" {
+		c, ok = codecs[fallback]
+		return fallback, c, ok
+	}
+
+	type pref struct {
+		mt string
+		q  float64
+	}
+	var prefs []pref
+	for _, part := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if s, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(s, 64); err != nil {
+				continue
+			}
+		}
+		if q > 0 {
+			prefs = append(prefs, pref{mt, q})
+		}
+	}
+	sort.SliceStable(prefs, func(i, j int) bool { return prefs[i].q > prefs[j].q })
+
+	for _, p := range prefs {
+		switch {
+		case p.mt == "*/*":
+			if c, ok := codecs[fallback]; ok {
+				return fallback, c, true
+			}
+		case strings.HasSuffix(p.mt, "/*"):
+			prefix := strings.TrimSuffix(p.mt, "*")
+			for _, mt := range order {
+				if strings.HasPrefix(mt, prefix) {
+					return mt, codecs[mt], true
+				}
+			}
+		default:
+			if c, ok := codecs[p.mt]; ok {
+				return p.mt, c, true
+			}
+		}
+	}
+	return "", nil, false
+}
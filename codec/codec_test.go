@@ -0,0 +1,75 @@
+package codec
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestLookup(t *testing.T) {
+	tests := []struct {
+		contentType string
+		want        bool
+	}{
+		{"application/json", true},
+		{"application/json; charset=utf-8", true},
+		{"Application/XML", true},
+		{"text/plain", false},
+		{"", false},
+		{";;", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.contentType, func(t *testing.T) {
+			if _, ok := Lookup(tt.contentType); ok != tt.want {
+				t.Errorf("Lookup = %v, se esperaba %v", ok, tt.want)
+			}
+		})
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	Register("application/x-msgpack-test", Funcs{MarshalFunc: json.Marshal, UnmarshalFunc: json.Unmarshal})
+
+	tests := []struct {
+		accept string
+		want   string
+		ok     bool
+	}{
+		{"", "application/json", true},
+		{"*/*", "application/json", true},
+		{"application/xml", "application/xml", true},
+		{"application/json;q=0.5, application/xml", "application/xml", true},
+		{"application/xml;q=0, application/json", "application/json", true},
+		{"text/html, application/*;q=0.8", "application/json", true},
+		{"application/x-msgpack-test", "application/x-msgpack-test", true},
+		{"image/png", "", false},
+		{"application/xml;q=abc", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			mt, c, ok := Negotiate(tt.accept, "application/json")
+			if mt != tt.want || ok != tt.ok || (ok && c == nil) {
+				t.Errorf("Negotiate = %q, %v; se esperaba %q, %v", mt, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	type point struct{ X, Y int }
+	for _, mt := range []string{"application/json", "application/xml"} {
+		t.Run(mt, func(t *testing.T) {
+			c, _ := Lookup(mt)
+			data, err := c.Marshal(point{1, 2})
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got point
+			if err := c.Unmarshal(data, &got); err != nil {
+				t.Fatal(err)
+			}
+			if got != (point{1, 2}) {
+				t.Errorf("ida y vuelta = %+v", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Package cookies escribe y lee cookies con valores por defecto seguros y,
+// opcionalmente, firmadas (HMAC) o cifradas (AES-GCM) con rotación de
+// claves. Trabaja sobre http.ResponseWriter y *http.Request, así que sirve
+// con cualquier driver.
+package cookies
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// maxCookieSize es el tamaño máximo que los navegadores aceptan para
+// nombre, valor y atributos.
+const maxCookieSize = 4096
+
+// Errores retornados al leer o escribir cookies.
+var (
+	ErrNotFound = http.ErrNoCookie
+	ErrInvalid  = errors.New("cookies: valor inválido o adulterado")
+	ErrTooLarge = errors.New("cookies: la cookie excede 4096 bytes")
+)
+
+// Options son los atributos de una cookie. Secure y HttpOnly se activan
+// por defecto; Insecure y AllowScript los desactivan.
+type Options struct {
+	Path     string
+	Domain   string
+	MaxAge   time.Duration
+	SameSite http.SameSite
+	// Insecure permite enviar la cookie sobre HTTP plano (desarrollo).
+	Insecure bool
+	// AllowScript permite leer la cookie desde JavaScript.
+	AllowScript bool
+}
+
+// Defaults son los atributos que se usan cuando opts es nil.
+var Defaults = Options{Path: "/", SameSite: http.SameSiteLaxMode}
+
+func (o *Options) cookie(name, value string) *http.Cookie {
+	if o == nil {
+		o = &Defaults
+	}
+	c := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     o.Path,
+		Domain:   o.Domain,
+		SameSite: o.SameSite,
+		Secure:   !o.Insecure,
+		HttpOnly: !o.AllowScript,
+	}
+	if c.Path == "" {
+		c.Path = "/"
+	}
+	if c.SameSite == 0 {
+		c.SameSite = http.SameSiteLaxMode
+	}
+	if o.MaxAge > 0 {
+		c.MaxAge = int(o.MaxAge.Seconds())
+		c.Expires = time.Now().Add(o.MaxAge)
+	}
+	return c
+}
+
+// Set escribe la cookie name=value. opts nil usa Defaults.
+func Set(w http.ResponseWriter, name, value string, opts *Options) error {
+	c := opts.cookie(name, value)
+	s := c.String()
+	if s == "" {
+		return ErrInvalid
+	}
+	if len(s) > maxCookieSize {
+		return ErrTooLarge
+	}
+	w.Header().Add("Set-Cookie", s)
+	return nil
+}
+
+// Get retorna el valor de la cookie name o ErrNotFound.
+func Get(r *http.Request, name string) (string, error) {
+	c, err := r.Cookie(name)
+	if err != nil {
+		return "", err
+	}
+	return c.Value, nil
+}
+
+// Delete borra la cookie name. opts debe tener el mismo Path y Domain con
+// que se creó.
+func Delete(w http.ResponseWriter, name string, opts *Options) {
+	c := opts.cookie(name, "")
+	c.MaxAge = -1
+	c.Expires = time.Unix(0, 0)
+	http.SetCookie(w, c)
+}
@@ -0,0 +1,170 @@
+package cookies
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// echo retorna una petición que trae la cookie escrita en w, con el valor
+// transformado por alter cuando no es nil.
+func echo(t *testing.T, w *httptest.ResponseRecorder, name string, alter func(string) string) *http.Request {
+	t.Helper()
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("se esperaba una cookie, hay %d", len(cookies))
+	}
+	c := cookies[0]
+	if alter != nil {
+		c.Value = alter(c.Value)
+	}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.AddCookie(&http.Cookie{Name: name, Value: c.Value})
+	return r
+}
+
+func TestSetDefaults(t *testing.T) {
+	tests := []struct {
+		name string
+		opts *Options
+		want []string
+		not  []string
+	}{
+		{"por defecto", nil, []string{"Path=/", "Secure", "HttpOnly", "SameSite=Lax"}, []string{"Max-Age"}},
+		{"desarrollo", &Options{Insecure: true, AllowScript: true}, []string{"Path=/", "SameSite=Lax"}, []string{"Secure", "HttpOnly"}},
+		{"con MaxAge", &Options{MaxAge: time.Hour, SameSite: http.SameSiteStrictMode}, []string{"Max-Age=3600", "SameSite=Strict"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := Set(w, "sid", "abc", tt.opts); err != nil {
+				t.Fatal(err)
+			}
+			h := w.Header().Get("Set-Cookie")
+			for _, s := range tt.want {
+				if !strings.Contains(h, s) {
+					t.Errorf("Set-Cookie = %q, falta %q", h, s)
+				}
+			}
+			for _, s := range tt.not {
+				if strings.Contains(h, s) {
+					t.Errorf("Set-Cookie = %q, no debe contener %q", h, s)
+				}
+			}
+		})
+	}
+}
+
+func TestSetTooLarge(t *testing.T) {
+	if err := Set(httptest.NewRecorder(), "big", strings.Repeat("a", maxCookieSize), nil); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("err = %v, se esperaba ErrTooLarge", err)
+	}
+}
+
+func TestSigned(t *testing.T) {
+	k := New([]byte("clave"))
+	old := payloadAt("usuario-1", time.Now().Add(-2*time.Hour))
+
+	tests := []struct {
+		name    string
+		keys    *Keys
+		cookie  string
+		maxAge  time.Duration
+		alter   func(string) string
+		want    string
+		wantErr error
+	}{
+		{"ida y vuelta", k, "sid", 0, nil, "usuario-1", nil},
+		{"otra clave", New([]byte("otra")), "sid", 0, nil, "", ErrInvalid},
+		{"otro nombre", k, "otra", 0, nil, "", ErrInvalid},
+		{"valor alterado", k, "sid", 0, func(v string) string { return "x" + v }, "", ErrInvalid},
+		{"sin firma", k, "sid", 0, func(v string) string { return v[:strings.LastIndexByte(v, '.')] }, "", ErrInvalid},
+		{"dentro de MaxAge", k, "sid", time.Hour, nil, "usuario-1", nil},
+		{"fuera de MaxAge", k, "sid", time.Hour, func(string) string {
+			return old + "." + b64.EncodeToString(mac([]byte("clave"), "sid", old))
+		}, "", ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := k.SetSigned(w, "sid", "usuario-1", nil); err != nil {
+				t.Fatal(err)
+			}
+			got, err := tt.keys.GetSigned(echo(t, w, tt.cookie, tt.alter), tt.cookie, tt.maxAge)
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetSigned = %q, %v; se esperaba %q, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestEncrypted(t *testing.T) {
+	k := New([]byte("clave"))
+
+	tests := []struct {
+		name    string
+		keys    *Keys
+		cookie  string
+		alter   func(string) string
+		want    string
+		wantErr error
+	}{
+		{"ida y vuelta", k, "sid", nil, "secreto", nil},
+		{"otra clave", New([]byte("otra")), "sid", nil, "", ErrInvalid},
+		{"otro nombre", k, "otra", nil, "", ErrInvalid},
+		{"valor alterado", k, "sid", flip, "", ErrInvalid},
+		{"valor truncado", k, "sid", func(v string) string { return v[:4] }, "", ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := k.SetEncrypted(w, "sid", "secreto", nil); err != nil {
+				t.Fatal(err)
+			}
+			if v := w.Result().Cookies()[0].Value; strings.Contains(v, b64.EncodeToString([]byte("secreto"))) {
+				t.Fatalf("el valor cifrado expone el contenido: %q", v)
+			}
+			got, err := tt.keys.GetEncrypted(echo(t, w, tt.cookie, tt.alter), tt.cookie, 0)
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("GetEncrypted = %q, %v; se esperaba %q, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	k := New([]byte("k1"))
+	w := httptest.NewRecorder()
+	if err := k.SetEncrypted(w, "sid", "v", nil); err != nil {
+		t.Fatal(err)
+	}
+	r := echo(t, w, "sid", nil)
+
+	k.Rotate([]byte("k2"))
+	if got, err := k.GetEncrypted(r, "sid", 0); err != nil || got != "v" {
+		t.Errorf("tras rotar: GetEncrypted = %q, %v", got, err)
+	}
+	k.Rotate([]byte("k3"))
+	k.Rotate([]byte("k4"))
+	if _, err := k.GetEncrypted(r, "sid", 0); !errors.Is(err, ErrInvalid) {
+		t.Errorf("tras %d rotaciones la clave inicial debe descartarse, err = %v", maxKeys, err)
+	}
+}
+
+func payloadAt(value string, at time.Time) string {
+	return b64.EncodeToString([]byte(value)) + "." + strconv.FormatInt(at.Unix(), 10)
+}
+
+// flip cambia un carácter intermedio de v, cuyos bits son todos
+// significativos en base64.
+func flip(v string) string {
+	c := byte('A')
+	if v[10] == c {
+		c = 'B'
+	}
+	return v[:10] + string(c) + v[11:]
+}
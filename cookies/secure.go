@@ -0,0 +1,201 @@
+package cookies
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/profe-ajedrez/transwarp/secrets"
+)
+
+// maxKeys acota cuántas claves previas siguen aceptándose tras sucesivas
+// rotaciones.
+const maxKeys = 3
+
+var b64 = base64.RawURLEncoding
+
+// Keys firma y cifra cookies. La primera clave se usa para escribir; todas
+// se aceptan al leer, lo que permite rotar claves sin cerrar sesiones.
+type Keys struct {
+	mu   sync.RWMutex
+	keys [][]byte
+}
+
+// New crea Keys con la clave actual y, opcionalmente, claves previas aún
+// aceptadas.
+func New(current []byte, previous ...[]byte) *Keys {
+	return &Keys{keys: append([][]byte{current}, previous...)}
+}
+
+// FromProvider crea Keys cuya clave es el secreto name de p. Para seguir
+// sus rotaciones, pase Rotate como callback de secrets.Watch.
+func FromProvider(ctx context.Context, p secrets.Provider, name string) (*Keys, error) {
+	key, err := p.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return New(key), nil
+}
+
+// Rotate pasa a escribir con key y conserva la clave anterior para leer
+// las cookies emitidas con ella.
+func (k *Keys) Rotate(key []byte) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if len(k.keys) > 0 && hmac.Equal(k.keys[0], key) {
+		return
+	}
+	k.keys = append([][]byte{key}, k.keys[:min(len(k.keys), maxKeys-1)]...)
+}
+
+func (k *Keys) snapshot() ([][]byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	if len(k.keys) == 0 || len(k.keys[0]) == 0 {
+		return nil, errors.New("cookies: no hay clave configurada")
+	}
+	return k.keys, nil
+}
+
+// derive obtiene subclaves independientes para firmar y cifrar.
+func derive(key []byte, purpose string) []byte {
+	m := hmac.New(sha256.New, key)
+	m.Write([]byte("transwarp/cookies/" + purpose))
+	return m.Sum(nil)
+}
+
+// SetSigned escribe una cookie cuyo valor queda legible pero protegido
+// contra adulteración. La firma cubre el nombre y el instante de emisión,
+// de modo que GetSigned rechaza la cookie pasado opts.MaxAge aunque el
+// cliente la conserve.
+func (k *Keys) SetSigned(w http.ResponseWriter, name, value string, opts *Options) error {
+	keys, err := k.snapshot()
+	if err != nil {
+		return err
+	}
+	payload := b64.EncodeToString([]byte(value)) + "." + strconv.FormatInt(time.Now().Unix(), 10)
+	return Set(w, name, payload+"."+b64.EncodeToString(mac(keys[0], name, payload)), opts)
+}
+
+// GetSigned lee una cookie escrita con SetSigned. maxAge cero no limita la
+// antigüedad.
+func (k *Keys) GetSigned(r *http.Request, name string, maxAge time.Duration) (string, error) {
+	raw, err := Get(r, name)
+	if err != nil {
+		return "", err
+	}
+	i := strings.LastIndexByte(raw, '.')
+	if i < 0 {
+		return "", ErrInvalid
+	}
+	payload, sig := raw[:i], raw[i+1:]
+	got, err := b64.DecodeString(sig)
+	if err != nil {
+		return "", ErrInvalid
+	}
+	keys, err := k.snapshot()
+	if err != nil {
+		return "", err
+	}
+	for _, key := range keys {
+		if hmac.Equal(got, mac(key, name, payload)) {
+			return decodePayload(payload, maxAge)
+		}
+	}
+	return "", ErrInvalid
+}
+
+func mac(key []byte, name, payload string) []byte {
+	m := hmac.New(sha256.New, derive(key, "sign"))
+	m.Write([]byte(name + "|" + payload))
+	return m.Sum(nil)
+}
+
+func decodePayload(payload string, maxAge time.Duration) (string, error) {
+	enc, ts, ok := strings.Cut(payload, ".")
+	if !ok {
+		return "", ErrInvalid
+	}
+	issued, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return "", ErrInvalid
+	}
+	if maxAge > 0 && time.Since(time.Unix(issued, 0)) > maxAge {
+		return "", ErrInvalid
+	}
+	value, err := b64.DecodeString(enc)
+	if err != nil {
+		return "", ErrInvalid
+	}
+	return string(value), nil
+}
+
+// SetEncrypted escribe una cookie cifrada con AES-256-GCM: el cliente no
+// puede leerla ni alterarla. El nombre se autentica como dato adicional
+// para impedir intercambiar cookies.
+func (k *Keys) SetEncrypted(w http.ResponseWriter, name, value string, opts *Options) error {
+	keys, err := k.snapshot()
+	if err != nil {
+		return err
+	}
+	aead, err := newAEAD(keys[0])
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	payload := b64.EncodeToString([]byte(value)) + "." + strconv.FormatInt(time.Now().Unix(), 10)
+	sealed := aead.Seal(nonce, nonce, []byte(payload), []byte(name))
+	return Set(w, name, b64.EncodeToString(sealed), opts)
+}
+
+// GetEncrypted lee una cookie escrita con SetEncrypted. maxAge cero no
+// limita la antigüedad.
+func (k *Keys) GetEncrypted(r *http.Request, name string, maxAge time.Duration) (string, error) {
+	raw, err := Get(r, name)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := b64.DecodeString(raw)
+	if err != nil {
+		return "", ErrInvalid
+	}
+	keys, err := k.snapshot()
+	if err != nil {
+		return "", err
+	}
+	for _, key := range keys {
+		aead, err := newAEAD(key)
+		if err != nil {
+			return "", err
+		}
+		if len(sealed) < aead.NonceSize() {
+			return "", ErrInvalid
+		}
+		nonce, ct := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+		if plain, err := aead.Open(nil, nonce, ct, []byte(name)); err == nil {
+			return decodePayload(string(plain), maxAge)
+		}
+	}
+	return "", ErrInvalid
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(derive(key, "encrypt"))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,191 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultMaxBodyBytes es el tamaño máximo de payload aceptado por Handler
+// cuando Bridge.MaxBodyBytes es cero.
+const DefaultMaxBodyBytes = 1 << 20
+
+// DefaultRetryInterval es la pausa entre reintentos de Run cuando
+// Bridge.RetryInterval es cero.
+const DefaultRetryInterval = 5 * time.Second
+
+// Bridge recibe payloads por HTTP y los publica mediante Publisher.
+type Bridge struct {
+	Publisher Publisher
+	Spool     Spool
+
+	// MaxBodyBytes limita el tamaño del payload aceptado.
+	MaxBodyBytes int64
+	// RetryInterval es la pausa entre reintentos de eventos pendientes.
+	RetryInterval time.Duration
+	// OnError recibe los errores de publicación; puede ser nil.
+	OnError func(e Event, err error)
+
+	mu       sync.Mutex
+	inflight map[string]struct{}
+}
+
+// NewBridge crea un Bridge. Si spool es nil se usa un MemorySpool.
+func NewBridge(pub Publisher, spool Spool) *Bridge {
+	if spool == nil {
+		spool = NewMemorySpool()
+	}
+	return &Bridge{Publisher: pub, Spool: spool}
+}
+
+// Handler retorna un handler que publica el cuerpo de la petición en topic.
+// Responde 202 Accepted en cuanto el evento queda persistido en el Spool,
+// aunque la publicación inmediata falle: el evento se reintentará desde Run.
+// Si la petición trae Idempotency-Key, el ID del evento se deriva de ella
+// junto con topic y la ruta (ver idempotentID), así los reintentos del
+// cliente producen el mismo ID sin chocar con los de otras rutas.
+func (b *Bridge) Handler(topic string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		limit := b.MaxBodyBytes
+		if limit <= 0 {
+			limit = DefaultMaxBodyBytes
+		}
+		payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, limit))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+				return
+			}
+			http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+			return
+		}
+
+		e := Event{
+			ID:      newID(),
+			Topic:   topic,
+			Payload: payload,
+			Time:    time.Now().UTC(),
+		}
+		if key := r.Header.Get("Idempotency-Key"); key != "" {
+			route := r.Pattern
+			if route == "" {
+				route = r.URL.Path
+			}
+			e.ID = idempotentID(topic, route, key)
+		}
+		if ct := r.Header.Get("Content-Type"); ct != "" {
+			e.Headers = map[string]string{"Content-Type": ct}
+		}
+
+		if err := b.Spool.Put(e); err != nil {
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+
+		b.deliver(r.Context(), e)
+
+		w.Header().Set("X-Event-Id", e.ID)
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// Publish persiste y publica un evento generado por la aplicación, con la
+// misma garantía al-menos-una-vez que Handler.
+func (b *Bridge) Publish(ctx context.Context, topic string, payload []byte) (string, error) {
+	e := Event{ID: newID(), Topic: topic, Payload: payload, Time: time.Now().UTC()}
+	if err := b.Spool.Put(e); err != nil {
+		return "", err
+	}
+	b.deliver(ctx, e)
+	return e.ID, nil
+}
+
+// Redeliver intenta publicar todos los eventos pendientes del Spool, en
+// orden de llegada pero sin detenerse en los que fallan, para que un
+// evento que el broker rechaza no bloquee al resto. No garantiza el orden
+// de publicación: Handler y Publish publican cada evento apenas llega,
+// aunque haya otros anteriores pendientes. Retorna un error si quedó
+// alguno sin publicar.
+func (b *Bridge) Redeliver(ctx context.Context) error {
+	pending, err := b.Spool.Pending()
+	if err != nil {
+		return err
+	}
+	failed := 0
+	for _, e := range pending {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if !b.deliver(ctx, e) {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("events: %d publicaciones pendientes", failed)
+	}
+	return nil
+}
+
+// Run reintenta periódicamente los eventos pendientes hasta que ctx se
+// cancele. Debe ejecutarse en su propia goroutine.
+func (b *Bridge) Run(ctx context.Context) {
+	interval := b.RetryInterval
+	if interval <= 0 {
+		interval = DefaultRetryInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			_ = b.Redeliver(ctx)
+		}
+	}
+}
+
+// deliver publica e y lo confirma en el Spool. Retorna false si la
+// publicación falló. Un evento que ya se está publicando se omite para no
+// duplicarlo innecesariamente entre Handler y Run.
+func (b *Bridge) deliver(ctx context.Context, e Event) bool {
+	if !b.acquire(e.ID) {
+		return true
+	}
+	defer b.release(e.ID)
+
+	if err := b.Publisher.Publish(ctx, e); err != nil {
+		if b.OnError != nil {
+			b.OnError(e, err)
+		}
+		return false
+	}
+	if err := b.Spool.Ack(e.ID); err != nil && b.OnError != nil {
+		b.OnError(e, err)
+	}
+	return true
+}
+
+func (b *Bridge) acquire(id string) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.inflight == nil {
+		b.inflight = make(map[string]struct{})
+	}
+	if _, busy := b.inflight[id]; busy {
+		return false
+	}
+	b.inflight[id] = struct{}{}
+	return true
+}
+
+func (b *Bridge) release(id string) {
+	b.mu.Lock()
+	delete(b.inflight, id)
+	b.mu.Unlock()
+}
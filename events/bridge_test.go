@@ -0,0 +1,176 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// broker es un Publisher en memoria que rechaza los eventos de los topics
+// en fail.
+type broker struct {
+	mu        sync.Mutex
+	published []Event
+	fail      map[string]bool
+}
+
+func (p *broker) Publish(_ context.Context, e Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.fail[e.Topic] {
+		return errors.New("broker caído")
+	}
+	p.published = append(p.published, e)
+	return nil
+}
+
+func (p *broker) topics() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var out []string
+	for _, e := range p.published {
+		out = append(out, e.Topic)
+	}
+	return out
+}
+
+func post(h http.Handler, body, key string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/ingest", strings.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	if key != "" {
+		r.Header.Set("Idempotency-Key", key)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestHandler(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		fail        bool
+		wantStatus  int
+		wantPending int
+	}{
+		{"publicado", `{"a":1}`, false, http.StatusAccepted, 0},
+		{"broker caído queda pendiente", `{"a":1}`, true, http.StatusAccepted, 1},
+		{"payload demasiado grande", strings.Repeat("x", 65), false, http.StatusRequestEntityTooLarge, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pub := &broker{fail: map[string]bool{"orders": tt.fail}}
+			spool := NewMemorySpool()
+			b := NewBridge(pub, spool)
+			b.MaxBodyBytes = 64
+			var errs int
+			b.OnError = func(Event, error) { errs++ }
+
+			w := post(b.Handler("orders"), tt.body, "")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, se esperaba %d", w.Code, tt.wantStatus)
+			}
+			pending, _ := spool.Pending()
+			if len(pending) != tt.wantPending {
+				t.Errorf("pendientes = %d, se esperaban %d", len(pending), tt.wantPending)
+			}
+			if tt.wantStatus != http.StatusAccepted {
+				return
+			}
+			if tt.fail {
+				if errs != 1 || len(pub.published) != 0 {
+					t.Errorf("errores = %d, publicados = %d", errs, len(pub.published))
+				}
+				return
+			}
+			if len(pub.published) != 1 {
+				t.Fatalf("publicados = %d, se esperaba 1", len(pub.published))
+			}
+			e := pub.published[0]
+			if e.ID != w.Header().Get("X-Event-Id") || string(e.Payload) != tt.body || e.Headers["Content-Type"] != "application/json" {
+				t.Errorf("evento = %+v, X-Event-Id = %q", e, w.Header().Get("X-Event-Id"))
+			}
+		})
+	}
+}
+
+func TestHandlerIdempotencyKey(t *testing.T) {
+	b := NewBridge(&broker{}, nil)
+	id := func(topic, key string) string {
+		return post(b.Handler(topic), "{}", key).Header().Get("X-Event-Id")
+	}
+	first := id("orders", "k-1")
+	if first == "k-1" || first == "" {
+		t.Errorf("el ID = %q no debe ser la clave del cliente", first)
+	}
+	if again := id("orders", "k-1"); again != first {
+		t.Errorf("reintento con la misma clave: ID = %q, se esperaba %q", again, first)
+	}
+	if other := id("payments", "k-1"); other == first {
+		t.Error("la misma clave en otro topic no debe producir el mismo ID")
+	}
+	if random := id("orders", ""); random == first || random == id("orders", "") {
+		t.Error("sin Idempotency-Key cada evento debe tener un ID nuevo")
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("POST /a", b.Handler("orders"))
+	mux.Handle("POST /b", b.Handler("orders"))
+	routeID := func(path string) string {
+		r := httptest.NewRequest(http.MethodPost, path, strings.NewReader("{}"))
+		r.Header.Set("Idempotency-Key", "k-1")
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, r)
+		return w.Header().Get("X-Event-Id")
+	}
+	if routeID("/a") == routeID("/b") {
+		t.Error("la misma clave en otra ruta no debe producir el mismo ID")
+	}
+}
+
+func TestRedeliver(t *testing.T) {
+	pub := &broker{fail: map[string]bool{"a": true, "c": true}}
+	spool := NewMemorySpool()
+	b := NewBridge(pub, spool)
+	ctx := context.Background()
+	for _, topic := range []string{"a", "b", "c", "d"} {
+		if _, err := b.Publish(ctx, topic, nil); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Un evento rechazado no impide publicar los siguientes.
+	pub.mu.Lock()
+	pub.fail = map[string]bool{"c": true}
+	pub.mu.Unlock()
+	if err := b.Redeliver(ctx); err == nil {
+		t.Error("Redeliver debe fallar mientras quede un pendiente")
+	}
+	if got := strings.Join(pub.topics(), ","); got != "b,d,a" {
+		t.Errorf("publicados = %s, se esperaba b,d,a", got)
+	}
+
+	pub.mu.Lock()
+	pub.fail = nil
+	pub.mu.Unlock()
+	if err := b.Redeliver(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if pending, _ := spool.Pending(); len(pending) != 0 {
+		t.Errorf("pendientes = %d, se esperaban 0", len(pending))
+	}
+
+	pub.mu.Lock()
+	pub.fail = map[string]bool{"a": true}
+	pub.mu.Unlock()
+	b.Publish(ctx, "a", nil)
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := b.Redeliver(cancelled); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, se esperaba context.Canceled", err)
+	}
+}
@@ -0,0 +1,62 @@
+// Package events convierte rutas HTTP en puntos de ingesta que publican el
+// payload recibido hacia un broker de mensajes (MQTT, AMQP, ...).
+//
+// La entrega es al-menos-una-vez: cada evento se persiste primero en un
+// Spool local y sólo se elimina de él cuando el Publisher confirma la
+// publicación. Los eventos que no pudieron publicarse se reintentan desde
+// Bridge.Run.
+package events
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+)
+
+// Event es el mensaje que se entrega al broker.
+type Event struct {
+	ID      string            `json:"id"`
+	Topic   string            `json:"topic"`
+	Payload []byte            `json:"payload"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Time    time.Time         `json:"time"`
+}
+
+// Publisher publica eventos en un broker. Publish debe retornar nil sólo
+// cuando el broker confirmó la recepción del mensaje; las implementaciones
+// deben tolerar duplicados, ya que un evento puede reenviarse tras un fallo.
+type Publisher interface {
+	Publish(ctx context.Context, e Event) error
+}
+
+// PublisherFunc adapta una función al interfaz Publisher.
+type PublisherFunc func(ctx context.Context, e Event) error
+
+// Publish implementa Publisher.
+func (f PublisherFunc) Publish(ctx context.Context, e Event) error { return f(ctx, e) }
+
+// Spool almacena los eventos pendientes de confirmación.
+type Spool interface {
+	// Put persiste el evento antes de intentar publicarlo.
+	Put(e Event) error
+	// Pending retorna los eventos aún no confirmados, en orden de llegada.
+	Pending() ([]Event, error)
+	// Ack elimina el evento una vez publicado.
+	Ack(id string) error
+}
+
+func newID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
+
+// idempotentID deriva el ID de un evento a partir de la Idempotency-Key
+// del cliente, acotada a topic y route: la misma clave en otra ruta es
+// otro evento, y el cliente no elige el ID tal cual.
+func idempotentID(topic, route, key string) string {
+	sum := sha256.Sum256([]byte(topic + "\x00" + route + "\x00" + key))
+	return hex.EncodeToString(sum[:16])
+}
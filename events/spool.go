@@ -0,0 +1,137 @@
+package events
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// MemorySpool es un Spool en memoria. No sobrevive a reinicios del proceso,
+// por lo que sólo garantiza reintentos mientras el proceso siga vivo.
+type MemorySpool struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+// NewMemorySpool crea un MemorySpool vacío.
+func NewMemorySpool() *MemorySpool {
+	return &MemorySpool{}
+}
+
+// Put implementa Spool.
+func (s *MemorySpool) Put(e Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, e)
+	return nil
+}
+
+// Pending implementa Spool.
+func (s *MemorySpool) Pending() ([]Event, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.events), nil
+}
+
+// Ack implementa Spool.
+func (s *MemorySpool) Ack(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = slices.DeleteFunc(s.events, func(e Event) bool { return e.ID == id })
+	return nil
+}
+
+// FileSpool persiste cada evento como un archivo JSON dentro de un
+// directorio, de modo que los eventos pendientes sobreviven a reinicios.
+type FileSpool struct {
+	dir string
+}
+
+// NewFileSpool crea el directorio si no existe y retorna un FileSpool sobre él.
+func NewFileSpool(dir string) (*FileSpool, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("events: creando spool: %w", err)
+	}
+	return &FileSpool{dir: dir}, nil
+}
+
+// Put implementa Spool. El archivo se escribe de forma atómica mediante
+// un archivo temporal y rename.
+func (s *FileSpool) Put(e Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("%020d-%s.json", e.Time.UnixNano(), fileKey(e.ID))
+	tmp, err := os.CreateTemp(s.dir, ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(s.dir, name))
+}
+
+// Pending implementa Spool.
+func (s *FileSpool) Pending() ([]Event, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+	var out []Event
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || strings.HasPrefix(name, ".") || !strings.HasSuffix(name, ".json") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(s.dir, name))
+		if err != nil {
+			return nil, err
+		}
+		var e Event
+		if err := json.Unmarshal(data, &e); err != nil {
+			return nil, fmt.Errorf("events: spool corrupto %s: %w", name, err)
+		}
+		out = append(out, e)
+	}
+	return out, nil
+}
+
+// Ack implementa Spool.
+func (s *FileSpool) Ack(id string) error {
+	matches, err := filepath.Glob(filepath.Join(s.dir, "*-"+fileKey(id)+".json"))
+	if err != nil {
+		return err
+	}
+	for _, m := range matches {
+		if err := os.Remove(m); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileKey deriva un nombre de archivo seguro a partir del ID del evento,
+// que puede provenir de un header enviado por el cliente.
+func fileKey(id string) string {
+	sum := sha1.Sum([]byte(id))
+	return hex.EncodeToString(sum[:10])
+}
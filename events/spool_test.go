@@ -0,0 +1,75 @@
+package events
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSpools(t *testing.T) {
+	tests := []struct {
+		name  string
+		spool func(t *testing.T) Spool
+	}{
+		{"memoria", func(*testing.T) Spool { return NewMemorySpool() }},
+		{"archivos", func(t *testing.T) Spool {
+			s, err := NewFileSpool(filepath.Join(t.TempDir(), "spool"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			return s
+		}},
+	}
+	base := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := tt.spool(t)
+			// IDs con caracteres que no sirven como nombre de archivo.
+			ids := []string{"uno", "../dos", "tres/*"}
+			for i, id := range ids {
+				e := Event{ID: id, Topic: "t", Payload: []byte(id), Time: base.Add(time.Duration(i) * time.Second)}
+				if err := s.Put(e); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := s.Ack("../dos"); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.Ack("inexistente"); err != nil {
+				t.Errorf("Ack de un ID inexistente: %v", err)
+			}
+			pending, err := s.Pending()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(pending) != 2 || pending[0].ID != "uno" || pending[1].ID != "tres/*" || string(pending[1].Payload) != "tres/*" {
+				t.Errorf("Pending = %+v", pending)
+			}
+		})
+	}
+}
+
+func TestFileSpoolSurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+	s, _ := NewFileSpool(dir)
+	e := Event{ID: "evt", Topic: "orders", Payload: []byte(`{"a":1}`), Headers: map[string]string{"Content-Type": "application/json"}, Time: time.Now().UTC()}
+	if err := s.Put(e); err != nil {
+		t.Fatal(err)
+	}
+	// Los temporales a medio escribir y los archivos ajenos se ignoran.
+	os.WriteFile(filepath.Join(dir, ".tmp-123"), []byte("{"), 0o600)
+	os.WriteFile(filepath.Join(dir, "LEEME.txt"), []byte("x"), 0o600)
+
+	reopened, err := NewFileSpool(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pending, err := reopened.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].ID != e.ID || string(pending[0].Payload) != `{"a":1}` || pending[0].Headers["Content-Type"] != "application/json" || !pending[0].Time.Equal(e.Time) {
+		t.Errorf("Pending = %+v, se esperaba %+v", pending, e)
+	}
+}
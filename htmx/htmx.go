@@ -0,0 +1,138 @@
+// Package htmx ayuda a servir aplicaciones renderizadas en el servidor con
+// HTMX o Hotwire Turbo: detecta las peticiones parciales, elige entre la
+// plantilla completa y el fragmento, y escribe los headers de respuesta
+// HX-* que controlan al cliente.
+package htmx
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/profe-ajedrez/transwarp/middleware"
+	"github.com/profe-ajedrez/transwarp/render"
+)
+
+// IsHTMX reporta si la petición la hizo HTMX.
+func IsHTMX(r *http.Request) bool {
+	return r.Header.Get("HX-Request") == "true"
+}
+
+// IsBoosted reporta si la petición proviene de un elemento con hx-boost,
+// que espera la página completa.
+func IsBoosted(r *http.Request) bool {
+	return r.Header.Get("HX-Boosted") == "true"
+}
+
+// Target retorna el id del elemento destino de HTMX, si lo hay.
+func Target(r *http.Request) string {
+	return r.Header.Get("HX-Target")
+}
+
+// TurboFrame retorna el id del turbo-frame que hizo la petición, si lo hay.
+func TurboFrame(r *http.Request) string {
+	return r.Header.Get("Turbo-Frame")
+}
+
+// IsPartial reporta si la petición espera sólo un fragmento: una petición
+// HTMX no boosted o una navegación dentro de un turbo-frame.
+func IsPartial(r *http.Request) bool {
+	return (IsHTMX(r) && !IsBoosted(r)) || TurboFrame(r) != ""
+}
+
+// Render ejecuta la plantilla partial de rd si la petición es parcial y
+// full en caso contrario, con status 200. Declara Vary para que las cachés
+// no confundan ambas variantes. La plantilla se ejecuta con render.HTML, así
+// que un error no deja escrita una página a medias. Un *template.Template
+// suelto se pasa como render.RendererFunc(t.ExecuteTemplate).
+func Render(w http.ResponseWriter, r *http.Request, rd render.Renderer, full, partial string, data any) error {
+	name := full
+	if IsPartial(r) {
+		name = partial
+	}
+	middleware.AddVary(w.Header(), "HX-Request", "Turbo-Frame")
+	return render.HTML(w, rd, http.StatusOK, name, data)
+}
+
+// Redirect indica al cliente que navegue a url. Para peticiones que no son
+// de HTMX responde una redirección 303 normal.
+func Redirect(w http.ResponseWriter, r *http.Request, url string) {
+	if IsHTMX(r) {
+		w.Header().Set("HX-Redirect", url)
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	http.Redirect(w, r, url, http.StatusSeeOther)
+}
+
+// Refresh indica a HTMX que recargue la página completa.
+func Refresh(w http.ResponseWriter) {
+	w.Header().Set("HX-Refresh", "true")
+}
+
+// PushURL agrega url al historial del navegador.
+func PushURL(w http.ResponseWriter, url string) {
+	w.Header().Set("HX-Push-Url", url)
+}
+
+// Retarget cambia el elemento destino de la respuesta (selector CSS).
+func Retarget(w http.ResponseWriter, selector string) {
+	w.Header().Set("HX-Retarget", selector)
+}
+
+// Reswap cambia la estrategia de intercambio (innerHTML, outerHTML, ...).
+func Reswap(w http.ResponseWriter, swap string) {
+	w.Header().Set("HX-Reswap", swap)
+}
+
+// Trigger dispara los eventos indicados en el cliente al recibir la
+// respuesta.
+func Trigger(w http.ResponseWriter, events ...string) {
+	detail := make(map[string]any, len(events))
+	for _, e := range events {
+		detail[e] = nil
+	}
+	TriggerDetail(w, detail)
+}
+
+// TriggerDetail dispara eventos con datos asociados, que HTMX entrega en
+// event.detail.
+func TriggerDetail(w http.ResponseWriter, events map[string]any) {
+	setTrigger(w, "HX-Trigger", events)
+}
+
+// TriggerAfterSwap es como TriggerDetail pero los eventos se disparan
+// después del intercambio.
+func TriggerAfterSwap(w http.ResponseWriter, events map[string]any) {
+	setTrigger(w, "HX-Trigger-After-Swap", events)
+}
+
+// TriggerAfterSettle es como TriggerDetail pero los eventos se disparan
+// después del asentamiento.
+func TriggerAfterSettle(w http.ResponseWriter, events map[string]any) {
+	setTrigger(w, "HX-Trigger-After-Settle", events)
+}
+
+// setTrigger combina events con los ya definidos en header.
+func setTrigger(w http.ResponseWriter, header string, events map[string]any) {
+	merged := map[string]any{}
+	if prev := w.Header().Get(header); prev != "" {
+		if err := json.Unmarshal([]byte(prev), &merged); err != nil {
+			// El valor previo era una lista de nombres separados por comas.
+			merged = map[string]any{}
+			for _, name := range strings.Split(prev, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					merged[name] = nil
+				}
+			}
+		}
+	}
+	for k, v := range events {
+		merged[k] = v
+	}
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return
+	}
+	w.Header().Set(header, string(data))
+}
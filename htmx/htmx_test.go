@@ -0,0 +1,113 @@
+package htmx
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/profe-ajedrez/transwarp/render"
+)
+
+func TestRender(t *testing.T) {
+	tmpl := template.Must(template.New("").Parse(`{{define "page"}}<html>{{template "list" .}}</html>{{end}}{{define "list"}}<ul>{{.}}</ul>{{end}}{{define "roto"}}a medias{{.Nada}}{{end}}`))
+	rd := render.RendererFunc(tmpl.ExecuteTemplate)
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   string
+	}{
+		{"navegación normal", nil, "<html><ul>x</ul></html>"},
+		{"HTMX", map[string]string{"HX-Request": "true"}, "<ul>x</ul>"},
+		{"HTMX boosted", map[string]string{"HX-Request": "true", "HX-Boosted": "true"}, "<html><ul>x</ul></html>"},
+		{"turbo-frame", map[string]string{"Turbo-Frame": "lista"}, "<ul>x</ul>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.header {
+				r.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			if err := Render(w, r, rd, "page", "list", "x"); err != nil {
+				t.Fatal(err)
+			}
+			if w.Body.String() != tt.want {
+				t.Errorf("cuerpo = %q, se esperaba %q", w.Body.String(), tt.want)
+			}
+			if got := w.Header().Values("Vary"); !reflect.DeepEqual(got, []string{"Hx-Request", "Turbo-Frame"}) {
+				t.Errorf("Vary = %q", got)
+			}
+			if got := w.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+				t.Errorf("Content-Type = %q", got)
+			}
+		})
+	}
+
+	w := httptest.NewRecorder()
+	if err := Render(w, httptest.NewRequest(http.MethodGet, "/", nil), rd, "roto", "list", "x"); err == nil {
+		t.Fatal("se esperaba un error de ejecución")
+	}
+	if w.Body.Len() != 0 || w.Header().Get("Content-Type") != "" {
+		t.Errorf("un error no debe escribir nada: %q", w.Body.String())
+	}
+}
+
+func TestTrigger(t *testing.T) {
+	tests := []struct {
+		name string
+		prev string
+		add  map[string]any
+		want map[string]any
+	}{
+		{"sin valor previo", "", map[string]any{"a": nil}, map[string]any{"a": nil}},
+		{"nombre simple", "a", map[string]any{"b": nil}, map[string]any{"a": nil, "b": nil}},
+		{"lista separada por comas", "a, b,c", map[string]any{"d": nil}, map[string]any{"a": nil, "b": nil, "c": nil, "d": nil}},
+		{"JSON previo", `{"a":{"id":1}}`, map[string]any{"b": "x"}, map[string]any{"a": map[string]any{"id": 1.0}, "b": "x"}},
+		{"reemplaza el detalle", `{"a":1}`, map[string]any{"a": 2}, map[string]any{"a": 2.0}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if tt.prev != "" {
+				w.Header().Set("HX-Trigger", tt.prev)
+			}
+			TriggerDetail(w, tt.add)
+			var got map[string]any
+			if err := json.Unmarshal([]byte(w.Header().Get("HX-Trigger")), &got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HX-Trigger = %v, se esperaba %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedirect(t *testing.T) {
+	tests := []struct {
+		name       string
+		htmx       bool
+		wantStatus int
+		wantHeader string
+	}{
+		{"HTMX", true, http.StatusOK, "HX-Redirect"},
+		{"navegador", false, http.StatusSeeOther, "Location"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/", nil)
+			if tt.htmx {
+				r.Header.Set("HX-Request", "true")
+			}
+			w := httptest.NewRecorder()
+			Redirect(w, r, "/listo")
+			if w.Code != tt.wantStatus || w.Header().Get(tt.wantHeader) != "/listo" {
+				t.Errorf("Redirect = %d, %s: %q", w.Code, tt.wantHeader, w.Header().Get(tt.wantHeader))
+			}
+		})
+	}
+}
@@ -0,0 +1,163 @@
+// Package workerpool provee un pool acotado de goroutines con recuperación
+// de panics, métricas y drenado ordenado, compartido por los subsistemas
+// asíncronos en lugar de lanzar goroutines sueltas.
+package workerpool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// Errores de Submit y TrySubmit.
+var (
+	ErrClosed = errors.New("workerpool: pool cerrado")
+	ErrFull   = errors.New("workerpool: cola llena")
+)
+
+// Options configura un Pool.
+type Options struct {
+	// Workers es la cantidad de goroutines; por defecto 8.
+	Workers int
+	// Queue es la capacidad de la cola de tareas pendientes; por defecto
+	// igual a Workers.
+	Queue int
+	// OnPanic recibe el valor y el stack de una tarea que entró en panic.
+	// El worker sigue atendiendo la cola.
+	OnPanic func(v any, stack []byte)
+}
+
+// Stats son los contadores de un Pool.
+type Stats struct {
+	Submitted uint64
+	Completed uint64
+	Panicked  uint64
+	Rejected  uint64
+	Running   int64
+	Queued    int
+}
+
+// Pool ejecuta tareas con una cantidad fija de workers.
+type Pool struct {
+	tasks   chan func()
+	onPanic func(any, []byte)
+	wg      sync.WaitGroup
+
+	mu     sync.RWMutex
+	closed bool
+
+	submitted, completed, panicked, rejected atomic.Uint64
+	running                                  atomic.Int64
+}
+
+// New crea un Pool y arranca sus workers.
+func New(opts Options) *Pool {
+	if opts.Workers <= 0 {
+		opts.Workers = 8
+	}
+	if opts.Queue <= 0 {
+		opts.Queue = opts.Workers
+	}
+	p := &Pool{tasks: make(chan func(), opts.Queue), onPanic: opts.OnPanic}
+	for range opts.Workers {
+		p.wg.Go(p.work)
+	}
+	return p
+}
+
+func (p *Pool) work() {
+	for task := range p.tasks {
+		p.run(task)
+	}
+}
+
+func (p *Pool) run(task func()) {
+	p.running.Add(1)
+	defer func() {
+		p.running.Add(-1)
+		if v := recover(); v != nil {
+			p.panicked.Add(1)
+			if p.onPanic != nil {
+				p.onPanic(v, debug.Stack())
+			}
+			return
+		}
+		p.completed.Add(1)
+	}()
+	task()
+}
+
+// Submit encola task, esperando lugar en la cola hasta que ctx se cancele.
+func (p *Pool) Submit(ctx context.Context, task func()) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		p.rejected.Add(1)
+		return ErrClosed
+	}
+	select {
+	case p.tasks <- task:
+		p.submitted.Add(1)
+		return nil
+	case <-ctx.Done():
+		p.rejected.Add(1)
+		return ctx.Err()
+	}
+}
+
+// TrySubmit encola task sin esperar; retorna ErrFull si la cola está
+// llena.
+func (p *Pool) TrySubmit(task func()) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		p.rejected.Add(1)
+		return ErrClosed
+	}
+	select {
+	case p.tasks <- task:
+		p.submitted.Add(1)
+		return nil
+	default:
+		p.rejected.Add(1)
+		return ErrFull
+	}
+}
+
+// Close deja de aceptar tareas y espera a que se completen las encoladas
+// y en curso, o a que ctx se cancele.
+func (p *Pool) Close(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.tasks)
+	}
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("workerpool: drenado incompleto: %w", ctx.Err())
+	}
+}
+
+// Stats retorna una instantánea de los contadores.
+func (p *Pool) Stats() Stats {
+	return Stats{
+		Submitted: p.submitted.Load(),
+		Completed: p.completed.Load(),
+		Panicked:  p.panicked.Load(),
+		Rejected:  p.rejected.Load(),
+		Running:   p.running.Load(),
+		Queued:    len(p.tasks),
+	}
+}
@@ -0,0 +1,176 @@
+// Package lifecycle registra los subsistemas de larga vida (brokers SSE,
+// hubs, schedulers, outbox, ...) y los cierra en orden de dependencias al
+// apagar la aplicación, evitando goroutines huérfanas.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout es el tiempo máximo de cierre de un componente que no
+// define el suyo.
+const DefaultTimeout = 5 * time.Second
+
+// Component es un subsistema que debe cerrarse al apagar.
+type Component struct {
+	Name string
+	// Close libera el componente. Debe respetar la cancelación de ctx.
+	Close func(ctx context.Context) error
+	// DependsOn nombra los componentes que este usa: se cierran después
+	// de él.
+	DependsOn []string
+	// Timeout limita Close; por defecto DefaultTimeout.
+	Timeout time.Duration
+}
+
+// Registry guarda los componentes registrados.
+type Registry struct {
+	mu    sync.Mutex
+	comps map[string]Component
+	order []string
+}
+
+// New crea un Registry vacío.
+func New() *Registry {
+	return &Registry{comps: make(map[string]Component)}
+}
+
+// Register agrega c. Falla si el nombre ya está registrado.
+func (r *Registry) Register(c Component) error {
+	if c.Name == "" || c.Close == nil {
+		return errors.New("lifecycle: el componente requiere Name y Close")
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, dup := r.comps[c.Name]; dup {
+		return fmt.Errorf("lifecycle: componente %q ya registrado", c.Name)
+	}
+	r.comps[c.Name] = c
+	r.order = append(r.order, c.Name)
+	return nil
+}
+
+// Shutdown cierra todos los componentes: primero los que nadie usa y al
+// final sus dependencias. Los componentes de un mismo nivel se cierran en
+// paralelo. Retorna los errores de cierre combinados; un componente que
+// excede su timeout cuenta como error pero no bloquea a los demás.
+func (r *Registry) Shutdown(ctx context.Context) error {
+	r.mu.Lock()
+	comps := make(map[string]Component, len(r.comps))
+	for k, v := range r.comps {
+		comps[k] = v
+	}
+	order := append([]string(nil), r.order...)
+	r.mu.Unlock()
+
+	levels, err := plan(comps, order)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, level := range levels {
+		var (
+			wg sync.WaitGroup
+			mu sync.Mutex
+		)
+		for _, name := range level {
+			c := comps[name]
+			wg.Go(func() {
+				if err := closeOne(ctx, c); err != nil {
+					mu.Lock()
+					errs = append(errs, fmt.Errorf("lifecycle: %s: %w", c.Name, err))
+					mu.Unlock()
+				}
+			})
+		}
+		wg.Wait()
+	}
+	return errors.Join(errs...)
+}
+
+func closeOne(ctx context.Context, c Component) error {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() { done <- c.Close(ctx) }()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// plan agrupa los componentes en niveles de cierre: un componente va en un
+// nivel posterior a todos los que dependen de él. Las dependencias no
+// registradas se ignoran y los ciclos son un error.
+func plan(comps map[string]Component, order []string) ([][]string, error) {
+	dependents := make(map[string]int, len(comps))
+	for _, c := range comps {
+		for _, d := range c.DependsOn {
+			if _, ok := comps[d]; ok {
+				dependents[d]++
+			}
+		}
+	}
+	var levels [][]string
+	done := make(map[string]bool, len(comps))
+	for len(done) < len(comps) {
+		var level []string
+		for _, name := range order {
+			if !done[name] && dependents[name] == 0 {
+				level = append(level, name)
+			}
+		}
+		if len(level) == 0 {
+			return nil, errors.New("lifecycle: dependencias cíclicas entre componentes")
+		}
+		for _, name := range level {
+			done[name] = true
+			for _, d := range comps[name].DependsOn {
+				if _, ok := comps[d]; ok {
+					dependents[d]--
+				}
+			}
+		}
+		levels = append(levels, level)
+	}
+	return levels, nil
+}
+
+// Default es el Registry de la aplicación.
+var Default = New()
+
+// Register agrega c a Default.
+func Register(c Component) error {
+	return Default.Register(c)
+}
+
+// Shutdown cierra los componentes de Default.
+func Shutdown(ctx context.Context) error {
+	return Default.Shutdown(ctx)
+}
+
+// Cancel adapta un subsistema que corre con Run(ctx) hasta que se cancela
+// su contexto (como scheduler.Run o events.Bridge.Run): Close cancela y
+// espera a que done se cierre.
+func Cancel(cancel context.CancelFunc, done <-chan struct{}) func(context.Context) error {
+	return func(ctx context.Context) error {
+		cancel()
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
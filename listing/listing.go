@@ -0,0 +1,252 @@
+// Package listing interpreta los parámetros de paginación, orden y filtro
+// de los endpoints que listan colecciones, y emite los headers Link y
+// X-Total-Count.
+//
+//	GET /users?page=2&per_page=50&sort=-created_at,name&filter[status]=active&filter[age][gte]=18
+//
+// Los errores son *params.Error, de modo que problem.FromError los
+// responde como 400.
+package listing
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/profe-ajedrez/transwarp/params"
+)
+
+// Operadores de filtro reconocidos en filter[campo][op].
+const (
+	OpEq   = "eq"
+	OpNe   = "ne"
+	OpGt   = "gt"
+	OpGte  = "gte"
+	OpLt   = "lt"
+	OpLte  = "lte"
+	OpLike = "like"
+	OpIn   = "in"
+)
+
+var ops = []string{OpEq, OpNe, OpGt, OpGte, OpLt, OpLte, OpLike, OpIn}
+
+// Options define los límites y los campos permitidos de un endpoint.
+type Options struct {
+	// DefaultPerPage por defecto es 20.
+	DefaultPerPage int
+	// MaxPerPage por defecto es 100; valores mayores se rechazan.
+	MaxPerPage int
+	// Sortable son los campos por los que se puede ordenar.
+	Sortable []string
+	// DefaultSort se usa si la petición no trae sort, p. ej. "-id".
+	DefaultSort string
+	// Filterable son los campos por los que se puede filtrar.
+	Filterable []string
+}
+
+// Sort es un criterio de orden.
+type Sort struct {
+	Field string
+	Desc  bool
+}
+
+// Filter es una condición sobre un campo. Con OpIn, Values tiene los
+// elementos separados por coma.
+type Filter struct {
+	Field  string
+	Op     string
+	Value  string
+	Values []string
+}
+
+// Query es el resultado de Parse.
+type Query struct {
+	// Page empieza en 1. Si la petición usó limit/offset, Page es la
+	// página que contiene Offset; Offset no tiene por qué ser múltiplo de
+	// PerPage.
+	Page    int
+	PerPage int
+	Offset  int
+	Sort    []Sort
+	Filters []Filter
+
+	// byOffset indica que la petición usó offset; SetHeaders genera
+	// entonces los enlaces con limit/offset.
+	byOffset bool
+}
+
+// Limit es un alias de PerPage para consultas SQL.
+func (q Query) Limit() int { return q.PerPage }
+
+// Parse interpreta la query de r. Acepta page/per_page o limit/offset.
+func Parse(r *http.Request, opts Options) (Query, error) {
+	if opts.DefaultPerPage <= 0 {
+		opts.DefaultPerPage = 20
+	}
+	if opts.MaxPerPage <= 0 {
+		opts.MaxPerPage = 100
+	}
+	v := r.URL.Query()
+	q := Query{Page: 1, PerPage: opts.DefaultPerPage}
+
+	var err error
+	if q.PerPage, err = intParam(v, "per_page", q.PerPage, 1, opts.MaxPerPage); err != nil {
+		return q, err
+	}
+	if q.PerPage, err = intParam(v, "limit", q.PerPage, 1, opts.MaxPerPage); err != nil {
+		return q, err
+	}
+	if v.Has("offset") {
+		if q.Offset, err = intParam(v, "offset", 0, 0, -1); err != nil {
+			return q, err
+		}
+		q.Page = q.Offset/q.PerPage + 1
+		q.byOffset = true
+	} else {
+		if q.Page, err = intParam(v, "page", 1, 1, -1); err != nil {
+			return q, err
+		}
+		q.Offset = (q.Page - 1) * q.PerPage
+	}
+
+	spec := v.Get("sort")
+	if spec == "" {
+		spec = opts.DefaultSort
+	}
+	for f := range strings.SplitSeq(spec, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" {
+			continue
+		}
+		s := Sort{Field: strings.TrimPrefix(strings.TrimPrefix(f, "-"), "+"), Desc: strings.HasPrefix(f, "-")}
+		if !slices.Contains(opts.Sortable, s.Field) {
+			return q, &params.Error{Key: "sort", Value: s.Field, Type: "un campo ordenable", Err: params.ErrInvalid}
+		}
+		q.Sort = append(q.Sort, s)
+	}
+
+	for key, vals := range v {
+		rest, ok := strings.CutPrefix(key, "filter[")
+		if !ok {
+			continue
+		}
+		field, rest, ok := strings.Cut(rest, "]")
+		if !ok {
+			return q, &params.Error{Key: key, Value: key, Type: "filter[campo] o filter[campo][op]", Err: params.ErrInvalid}
+		}
+		op := OpEq
+		if rest != "" {
+			o, ok := strings.CutPrefix(rest, "[")
+			if !ok || !strings.HasSuffix(o, "]") {
+				return q, &params.Error{Key: key, Value: key, Type: "filter[campo][op]", Err: params.ErrInvalid}
+			}
+			op = strings.TrimSuffix(o, "]")
+		}
+		if !slices.Contains(opts.Filterable, field) {
+			return q, &params.Error{Key: key, Value: field, Type: "un campo filtrable", Err: params.ErrInvalid}
+		}
+		if !slices.Contains(ops, op) {
+			return q, &params.Error{Key: key, Value: op, Type: "un operador (" + strings.Join(ops, ", ") + ")", Err: params.ErrInvalid}
+		}
+		f := Filter{Field: field, Op: op, Value: vals[0]}
+		if op == OpIn {
+			f.Values = strings.Split(f.Value, ",")
+		}
+		q.Filters = append(q.Filters, f)
+	}
+	// El orden de los filtros no debe depender de la iteración del mapa.
+	slices.SortFunc(q.Filters, func(a, b Filter) int {
+		return strings.Compare(a.Field+"\x00"+a.Op, b.Field+"\x00"+b.Op)
+	})
+	return q, nil
+}
+
+// intParam lee key como entero en [lo, hi]; hi < 0 no limita.
+func intParam(v url.Values, key string, def, lo, hi int) (int, error) {
+	s := v.Get(key)
+	if s == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil || n < lo || (hi >= 0 && n > hi) {
+		typ := "un entero >= " + strconv.Itoa(lo)
+		if hi >= 0 {
+			typ = "un entero entre " + strconv.Itoa(lo) + " y " + strconv.Itoa(hi)
+		}
+		return 0, &params.Error{Key: key, Value: s, Type: typ, Err: params.ErrInvalid}
+	}
+	return n, nil
+}
+
+// SetHeaders escribe X-Total-Count y el header Link (RFC 8288) con las
+// relaciones first, prev, next y last, conservando el resto de la query.
+// total negativo indica que se desconoce: se omiten X-Total-Count y last,
+// y next se emite siempre. Si la petición usó limit/offset, los enlaces
+// también los usan, desplazados desde el Offset pedido.
+func SetHeaders(w http.ResponseWriter, r *http.Request, q Query, total int) {
+	h := w.Header()
+	if total >= 0 {
+		h.Set("X-Total-Count", strconv.Itoa(total))
+	}
+	if q.byOffset {
+		setOffsetLinks(h, r, q, total)
+		return
+	}
+	last := -1
+	if total >= 0 {
+		last = max((total+q.PerPage-1)/q.PerPage, 1)
+	}
+
+	link := func(page int, rel string) {
+		addLink(h, r, rel, "page", page, "per_page", q.PerPage)
+	}
+	link(1, "first")
+	if q.Page > 1 {
+		link(q.Page-1, "prev")
+	}
+	if last < 0 || q.Page < last {
+		link(q.Page+1, "next")
+	}
+	if last > 0 {
+		link(last, "last")
+	}
+}
+
+// setOffsetLinks es SetHeaders para peticiones con limit/offset.
+func setOffsetLinks(h http.Header, r *http.Request, q Query, total int) {
+	link := func(offset int, rel string) {
+		addLink(h, r, rel, "limit", q.PerPage, "offset", offset)
+	}
+	link(0, "first")
+	if q.Offset > 0 {
+		link(max(q.Offset-q.PerPage, 0), "prev")
+	}
+	if total < 0 || q.Offset+q.PerPage < total {
+		link(q.Offset+q.PerPage, "next")
+	}
+	if total >= 0 {
+		// last conserva el desfase de Offset respecto de PerPage, para
+		// que siguiendo next se llegue exactamente a él.
+		last := 0
+		if phase := q.Offset % q.PerPage; total > phase {
+			last = phase + (total-1-phase)/q.PerPage*q.PerPage
+		}
+		link(last, "last")
+	}
+}
+
+// addLink agrega a h un enlace rel a la URL de r con los parámetros de
+// paginación reemplazados por k1=v1 y k2=v2.
+func addLink(h http.Header, r *http.Request, rel, k1 string, v1 int, k2 string, v2 int) {
+	u := *r.URL
+	v := u.Query()
+	for _, k := range []string{"page", "per_page", "limit", "offset"} {
+		v.Del(k)
+	}
+	v.Set(k1, strconv.Itoa(v1))
+	v.Set(k2, strconv.Itoa(v2))
+	u.RawQuery = v.Encode()
+	h.Add("Link", "<"+u.RequestURI()+`>; rel="`+rel+`"`)
+}
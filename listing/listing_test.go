@@ -0,0 +1,195 @@
+package listing
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/profe-ajedrez/transwarp/params"
+)
+
+var testOpts = Options{
+	Sortable:    []string{"id", "name", "created_at"},
+	DefaultSort: "-id",
+	Filterable:  []string{"status", "age"},
+}
+
+func TestParsePagination(t *testing.T) {
+	tests := []struct {
+		name                  string
+		query                 string
+		page, perPage, offset int
+	}{
+		{"por defecto", "", 1, 20, 0},
+		{"page y per_page", "page=3&per_page=10", 3, 10, 20},
+		{"limit y offset", "limit=10&offset=30", 4, 10, 30},
+		{"offset no alineado", "limit=10&offset=25", 3, 10, 25},
+		{"limit prevalece sobre per_page", "per_page=50&limit=5", 1, 5, 0},
+		{"offset prevalece sobre page", "page=9&offset=0", 1, 20, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := Parse(httptest.NewRequest(http.MethodGet, "/users?"+tt.query, nil), testOpts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if q.Page != tt.page || q.PerPage != tt.perPage || q.Offset != tt.offset || q.Limit() != tt.perPage {
+				t.Errorf("Parse = page %d, per_page %d, offset %d; se esperaba %d, %d, %d", q.Page, q.PerPage, q.Offset, tt.page, tt.perPage, tt.offset)
+			}
+		})
+	}
+}
+
+func TestParseSortAndFilters(t *testing.T) {
+	q, err := Parse(httptest.NewRequest(http.MethodGet, "/users?sort=-created_at,+name&filter[status]=active&filter[age][gte]=18&filter[status][in]=a,b", nil), testOpts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantSort := []Sort{{"created_at", true}, {"name", false}}
+	if !reflect.DeepEqual(q.Sort, wantSort) {
+		t.Errorf("Sort = %+v, se esperaba %+v", q.Sort, wantSort)
+	}
+	wantFilters := []Filter{
+		{Field: "age", Op: OpGte, Value: "18"},
+		{Field: "status", Op: OpEq, Value: "active"},
+		{Field: "status", Op: OpIn, Value: "a,b", Values: []string{"a", "b"}},
+	}
+	if !reflect.DeepEqual(q.Filters, wantFilters) {
+		t.Errorf("Filters = %+v, se esperaba %+v", q.Filters, wantFilters)
+	}
+
+	q, _ = Parse(httptest.NewRequest(http.MethodGet, "/users", nil), testOpts)
+	if !reflect.DeepEqual(q.Sort, []Sort{{"id", true}}) {
+		t.Errorf("Sort por defecto = %+v", q.Sort)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		key   string
+	}{
+		{"page cero", "page=0", "page"},
+		{"per_page sobre el máximo", "per_page=101", "per_page"},
+		{"limit no numérico", "limit=x", "limit"},
+		{"offset negativo", "offset=-1", "offset"},
+		{"campo no ordenable", "sort=password", "sort"},
+		{"campo no filtrable", "filter[password]=x", "filter[password]"},
+		{"operador desconocido", "filter[age][between]=1", "filter[age][between]"},
+		{"filtro sin cerrar", "filter[age=1", "filter[age"},
+		{"operador mal formado", "filter[age]gte=1", "filter[age]gte"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(httptest.NewRequest(http.MethodGet, "/users?"+tt.query, nil), testOpts)
+			var pe *params.Error
+			if !errors.As(err, &pe) || pe.Key != tt.key || !errors.Is(err, params.ErrInvalid) {
+				t.Errorf("err = %v, se esperaba un *params.Error con Key %q", err, tt.key)
+			}
+		})
+	}
+}
+
+func TestSetHeaders(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		total     int
+		wantLinks []string
+		wantCount string
+	}{
+		{
+			"página intermedia", "page=2&per_page=10&sort=name", 35,
+			[]string{
+				`</users?page=1&per_page=10&sort=name>; rel="first"`,
+				`</users?page=1&per_page=10&sort=name>; rel="prev"`,
+				`</users?page=3&per_page=10&sort=name>; rel="next"`,
+				`</users?page=4&per_page=10&sort=name>; rel="last"`,
+			},
+			"35",
+		},
+		{
+			"última página", "page=4&per_page=10", 35,
+			[]string{
+				`</users?page=1&per_page=10>; rel="first"`,
+				`</users?page=3&per_page=10>; rel="prev"`,
+				`</users?page=4&per_page=10>; rel="last"`,
+			},
+			"35",
+		},
+		{
+			"total desconocido", "page=1&per_page=10", -1,
+			[]string{
+				`</users?page=1&per_page=10>; rel="first"`,
+				`</users?page=2&per_page=10>; rel="next"`,
+			},
+			"",
+		},
+		{
+			"colección vacía", "", 0,
+			[]string{
+				`</users?page=1&per_page=20>; rel="first"`,
+				`</users?page=1&per_page=20>; rel="last"`,
+			},
+			"0",
+		},
+		{
+			"offset no alineado", "limit=10&offset=25&sort=name", 40,
+			[]string{
+				`</users?limit=10&offset=0&sort=name>; rel="first"`,
+				`</users?limit=10&offset=15&sort=name>; rel="prev"`,
+				`</users?limit=10&offset=35&sort=name>; rel="next"`,
+				`</users?limit=10&offset=35&sort=name>; rel="last"`,
+			},
+			"40",
+		},
+		{
+			"offset menor que limit", "limit=10&offset=5", 12,
+			[]string{
+				`</users?limit=10&offset=0>; rel="first"`,
+				`</users?limit=10&offset=0>; rel="prev"`,
+				`</users?limit=10&offset=5>; rel="last"`,
+			},
+			"12",
+		},
+		{
+			"offset al final", "limit=10&offset=30", 35,
+			[]string{
+				`</users?limit=10&offset=0>; rel="first"`,
+				`</users?limit=10&offset=20>; rel="prev"`,
+				`</users?limit=10&offset=30>; rel="last"`,
+			},
+			"35",
+		},
+		{
+			"offset con total desconocido", "offset=7", -1,
+			[]string{
+				`</users?limit=20&offset=0>; rel="first"`,
+				`</users?limit=20&offset=0>; rel="prev"`,
+				`</users?limit=20&offset=27>; rel="next"`,
+			},
+			"",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/users?"+tt.query, nil)
+			q, err := Parse(r, testOpts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			w := httptest.NewRecorder()
+			SetHeaders(w, r, q, tt.total)
+			if got := w.Header().Values("Link"); !reflect.DeepEqual(got, tt.wantLinks) {
+				t.Errorf("Link =\n%s\nse esperaba\n%s", strings.Join(got, "\n"), strings.Join(tt.wantLinks, "\n"))
+			}
+			if got := w.Header().Get("X-Total-Count"); got != tt.wantCount {
+				t.Errorf("X-Total-Count = %q, se esperaba %q", got, tt.wantCount)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+// Package loadtest ejecuta mezclas de peticiones ponderadas contra una
+// aplicación transwarp, en proceso o por HTTP, y reporta percentiles de
+// latencia por patrón de ruta. Complementa a los benchmarks con cargas
+// parecidas al tráfico real.
+//
+// Con la misma Seed y la misma cantidad de workers, la secuencia de
+// peticiones que emite cada worker es siempre la misma.
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// Target es una petición de la mezcla.
+type Target struct {
+	// Name agrupa las métricas; normalmente el patrón de la ruta, p. ej.
+	// "GET /users/:id". Si está vacío se usa Method y Path.
+	Name   string
+	Weight int
+	Method string
+	Path   string
+	Body   []byte
+	Header http.Header
+}
+
+func (t Target) name() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return t.Method + " " + t.Path
+}
+
+// Scenario describe la carga a generar.
+type Scenario struct {
+	Targets []Target
+	// Concurrency es la cantidad de workers una vez terminada la rampa.
+	Concurrency int
+	// RampUp reparte el arranque de los workers a lo largo de este período.
+	RampUp time.Duration
+	// Duration es la duración total de la prueba, incluida la rampa.
+	Duration time.Duration
+	// Requests, si es mayor que cero, limita la cantidad total de peticiones.
+	Requests int
+	// Seed fija la secuencia de peticiones.
+	Seed uint64
+}
+
+// Executor ejecuta una petición y retorna el status obtenido.
+type Executor func(req *http.Request) (status int, err error)
+
+// InProcess ejecuta las peticiones sobre h sin pasar por la red. Si el
+// contexto de la petición terminó mientras h la atendía, retorna su error,
+// igual que un cliente HTTP, para que Run no la cuente.
+func InProcess(h http.Handler) Executor {
+	return func(req *http.Request) (int, error) {
+		resp := router.DispatchRequest(h, req)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return resp.StatusCode, req.Context().Err()
+	}
+}
+
+// HTTP ejecuta las peticiones contra baseURL usando client (o
+// http.DefaultClient si es nil).
+func HTTP(client *http.Client, baseURL string) Executor {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	baseURL = strings.TrimRight(baseURL, "/")
+	return func(req *http.Request) (int, error) {
+		u, err := req.URL.Parse(baseURL + req.URL.RequestURI())
+		if err != nil {
+			return 0, err
+		}
+		req.URL = u
+		req.Host = u.Host
+		resp, err := client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		return resp.StatusCode, nil
+	}
+}
+
+type sample struct {
+	target  int
+	status  int
+	err     bool
+	latency time.Duration
+}
+
+// Run ejecuta sc usando exec y retorna el reporte. Se detiene al cumplirse
+// Duration, al alcanzar Requests o al cancelarse ctx.
+func Run(ctx context.Context, sc Scenario, exec Executor) (*Report, error) {
+	if len(sc.Targets) == 0 {
+		return nil, errors.New("loadtest: el escenario no tiene targets")
+	}
+	if sc.Duration <= 0 && sc.Requests <= 0 {
+		return nil, errors.New("loadtest: se requiere Duration o Requests")
+	}
+	workers := max(sc.Concurrency, 1)
+
+	cumulative := make([]int, len(sc.Targets))
+	total := 0
+	for i, t := range sc.Targets {
+		total += max(t.Weight, 0)
+		cumulative[i] = total
+	}
+	if total == 0 {
+		return nil, errors.New("loadtest: la suma de pesos es cero")
+	}
+
+	if sc.Duration > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, sc.Duration)
+		defer cancel()
+	}
+
+	var (
+		mu       sync.Mutex
+		samples  []sample
+		budget   = int64(sc.Requests)
+		budgetMu sync.Mutex
+		wg       sync.WaitGroup
+	)
+	take := func() bool {
+		if sc.Requests <= 0 {
+			return true
+		}
+		budgetMu.Lock()
+		defer budgetMu.Unlock()
+		if budget <= 0 {
+			return false
+		}
+		budget--
+		return true
+	}
+
+	start := time.Now()
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if sc.RampUp > 0 {
+				delay := sc.RampUp * time.Duration(w) / time.Duration(workers)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(delay):
+				}
+			}
+
+			rng := rand.New(rand.NewPCG(sc.Seed, uint64(w)))
+			var local []sample
+			for ctx.Err() == nil && take() {
+				idx := pick(rng, cumulative, total)
+				s, err := fire(ctx, sc.Targets[idx], idx, exec)
+				// Las peticiones cortadas porque terminó la ejecución no
+				// son errores del servicio ni latencias válidas.
+				if err != nil && ctx.Err() != nil && (errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled)) {
+					break
+				}
+				local = append(local, s)
+			}
+
+			mu.Lock()
+			samples = append(samples, local...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	return newReport(sc.Targets, samples, time.Since(start)), nil
+}
+
+func pick(rng *rand.Rand, cumulative []int, total int) int {
+	n := rng.IntN(total)
+	for i, c := range cumulative {
+		if n < c {
+			return i
+		}
+	}
+	return len(cumulative) - 1
+}
+
+// fire ejecuta una petición a t y retorna su muestra junto con el error
+// del Executor, si lo hubo.
+func fire(ctx context.Context, t Target, idx int, exec Executor) (sample, error) {
+	method := t.Method
+	if method == "" {
+		method = http.MethodGet
+	}
+	var body io.Reader
+	if len(t.Body) > 0 {
+		body = bytes.NewReader(t.Body)
+	}
+
+	s := sample{target: idx}
+	req, err := http.NewRequestWithContext(ctx, method, t.Path, body)
+	if err != nil {
+		s.err = true
+		return s, err
+	}
+	for k, v := range t.Header {
+		req.Header[k] = v
+	}
+
+	begin := time.Now()
+	status, err := exec(req)
+	s.latency = time.Since(begin)
+	s.status = status
+	s.err = err != nil
+	return s, err
+}
@@ -0,0 +1,165 @@
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestRunWeights(t *testing.T) {
+	sc := Scenario{
+		Targets: []Target{
+			{Name: "lectura", Weight: 3, Path: "/r"},
+			{Name: "escritura", Weight: 1, Method: http.MethodPost, Path: "/w", Body: []byte("x")},
+			{Name: "nunca", Weight: 0, Path: "/n"},
+		},
+		Concurrency: 2,
+		Requests:    4000,
+		Seed:        7,
+	}
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			w.WriteHeader(http.StatusCreated)
+		}
+	})
+	counts := func() map[string]int {
+		rep, err := Run(context.Background(), sc, InProcess(h))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rep.Total != sc.Requests || rep.Errors != 0 {
+			t.Fatalf("Total = %d, Errors = %d; se esperaban %d y 0", rep.Total, rep.Errors, sc.Requests)
+		}
+		out := map[string]int{}
+		for _, st := range rep.Routes {
+			out[st.Name] = st.Requests
+			for code, n := range st.Status {
+				if want := map[string]int{"lectura": 200, "escritura": 201}[st.Name]; code != want || n != st.Requests {
+					t.Errorf("%s: Status = %v", st.Name, st.Status)
+				}
+			}
+		}
+		return out
+	}
+
+	first := counts()
+	if _, ok := first["nunca"]; ok {
+		t.Error("un target de peso cero no debe ejecutarse")
+	}
+	if r := float64(first["lectura"]) / float64(sc.Requests); r < 0.70 || r > 0.80 {
+		t.Errorf("proporción de lecturas = %.3f, se esperaba cerca de 0,75", r)
+	}
+	// Con la misma Seed cada worker emite la misma secuencia; el reparto
+	// del presupuesto entre workers puede variar, así que se compara con
+	// un solo worker.
+	sc.Concurrency = 1
+	a, b := counts(), counts()
+	if a["lectura"] != b["lectura"] || a["escritura"] != b["escritura"] {
+		t.Errorf("misma Seed, distinto reparto: %v y %v", a, b)
+	}
+}
+
+func TestRunCutOff(t *testing.T) {
+	// El handler solo responde cuando termina la ejecución: todas las
+	// peticiones en curso quedan cortadas y no deben contarse.
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+	rep, err := Run(context.Background(), Scenario{
+		Targets:     []Target{{Weight: 1, Path: "/lento"}},
+		Concurrency: 3,
+		Duration:    50 * time.Millisecond,
+	}, InProcess(h))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Total != 0 || rep.Errors != 0 || len(rep.Routes) != 0 {
+		t.Errorf("Total = %d, Errors = %d, Routes = %v; se esperaba un reporte vacío", rep.Total, rep.Errors, rep.Routes)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	boom := errors.New("conexión rechazada")
+	calls := 0
+	exec := func(req *http.Request) (int, error) {
+		if calls++; calls%2 == 0 {
+			return 0, boom
+		}
+		return http.StatusOK, nil
+	}
+	rep, err := Run(context.Background(), Scenario{Targets: []Target{{Weight: 1, Path: "/"}}, Requests: 10}, exec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rep.Total != 10 || rep.Errors != 5 || rep.Routes[0].Errors != 5 || rep.Routes[0].Status[200] != 5 {
+		t.Errorf("Total = %d, Errors = %d, ruta = %+v", rep.Total, rep.Errors, rep.Routes[0])
+	}
+
+	invalid := []struct {
+		name string
+		sc   Scenario
+	}{
+		{"sin targets", Scenario{Requests: 1}},
+		{"sin límite", Scenario{Targets: []Target{{Weight: 1, Path: "/"}}}},
+		{"pesos en cero", Scenario{Targets: []Target{{Path: "/"}}, Requests: 1}},
+	}
+	for _, tt := range invalid {
+		if _, err := Run(context.Background(), tt.sc, exec); err == nil {
+			t.Errorf("%s: se esperaba un error", tt.name)
+		}
+	}
+}
+
+func TestReportPercentiles(t *testing.T) {
+	targets := []Target{{Name: "a"}, {Name: "b"}}
+	var samples []sample
+	// 1ms..100ms en orden inverso, más dos errores que no cuentan como
+	// latencias.
+	for i := 100; i >= 1; i-- {
+		samples = append(samples, sample{target: 0, status: 200, latency: time.Duration(i) * time.Millisecond})
+	}
+	samples = append(samples, sample{target: 0, err: true, latency: time.Hour}, sample{target: 1, err: true})
+
+	rep := newReport(targets, samples, 2*time.Second)
+	if rep.Total != 102 || rep.Errors != 2 || rep.RPS() != 51 {
+		t.Errorf("Total = %d, Errors = %d, RPS = %v", rep.Total, rep.Errors, rep.RPS())
+	}
+	a := rep.Routes[0]
+	tests := []struct {
+		name      string
+		got, want time.Duration
+	}{
+		{"Min", a.Min, time.Millisecond},
+		{"Max", a.Max, 100 * time.Millisecond},
+		{"Mean", a.Mean, 50500 * time.Microsecond},
+		{"P50", a.P50, 50 * time.Millisecond},
+		{"P90", a.P90, 90 * time.Millisecond},
+		{"P99", a.P99, 99 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s = %v, se esperaba %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if a.Requests != 101 || a.Errors != 1 {
+		t.Errorf("ruta a: Requests = %d, Errors = %d", a.Requests, a.Errors)
+	}
+	if b := rep.Routes[1]; b.Requests != 1 || b.Errors != 1 || b.P50 != 0 {
+		t.Errorf("ruta b = %+v, se esperaba solo un error", b)
+	}
+
+	for _, tt := range []struct {
+		n, p int
+		want time.Duration
+	}{{1, 99, 1}, {3, 50, 2}, {10, 90, 9}, {10, 99, 10}} {
+		sorted := make([]time.Duration, tt.n)
+		for i := range sorted {
+			sorted[i] = time.Duration(i + 1)
+		}
+		if got := percentile(sorted, tt.p); got != tt.want {
+			t.Errorf("percentile(%d, p%d) = %v, se esperaba %v", tt.n, tt.p, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package loadtest
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Report resume una ejecución de Run.
+type Report struct {
+	Elapsed time.Duration
+	Total   int
+	Errors  int
+	Routes  []RouteStats
+}
+
+// RouteStats agrupa las métricas de un Target.
+type RouteStats struct {
+	Name     string
+	Requests int
+	Errors   int
+	Status   map[int]int
+	Min      time.Duration
+	Mean     time.Duration
+	P50      time.Duration
+	P90      time.Duration
+	P99      time.Duration
+	Max      time.Duration
+}
+
+// RPS retorna las peticiones por segundo de toda la ejecución.
+func (r *Report) RPS() float64 {
+	if r.Elapsed <= 0 {
+		return 0
+	}
+	return float64(r.Total) / r.Elapsed.Seconds()
+}
+
+func newReport(targets []Target, samples []sample, elapsed time.Duration) *Report {
+	rep := &Report{Elapsed: elapsed, Total: len(samples)}
+
+	byName := map[string][]sample{}
+	var order []string
+	for _, s := range samples {
+		name := targets[s.target].name()
+		if _, ok := byName[name]; !ok {
+			order = append(order, name)
+		}
+		byName[name] = append(byName[name], s)
+		if s.err {
+			rep.Errors++
+		}
+	}
+	slices.Sort(order)
+
+	for _, name := range order {
+		group := byName[name]
+		st := RouteStats{Name: name, Requests: len(group), Status: map[int]int{}}
+
+		latencies := make([]time.Duration, 0, len(group))
+		var sum time.Duration
+		for _, s := range group {
+			if s.err {
+				st.Errors++
+				continue
+			}
+			st.Status[s.status]++
+			latencies = append(latencies, s.latency)
+			sum += s.latency
+		}
+		if len(latencies) > 0 {
+			slices.Sort(latencies)
+			st.Min = latencies[0]
+			st.Max = latencies[len(latencies)-1]
+			st.Mean = sum / time.Duration(len(latencies))
+			st.P50 = percentile(latencies, 50)
+			st.P90 = percentile(latencies, 90)
+			st.P99 = percentile(latencies, 99)
+		}
+		rep.Routes = append(rep.Routes, st)
+	}
+	return rep
+}
+
+// percentile usa el método nearest-rank sobre latencias ya ordenadas.
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	return sorted[max(rank-1, 0)]
+}
+
+// String formatea el reporte como una tabla.
+func (r *Report) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%d peticiones en %s (%.1f req/s), %d errores\n", r.Total, r.Elapsed.Round(time.Millisecond), r.RPS(), r.Errors)
+
+	tw := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ruta\treqs\terr\tp50\tp90\tp99\tmax\tstatus")
+	for _, st := range r.Routes {
+		codes := make([]int, 0, len(st.Status))
+		for code := range st.Status {
+			codes = append(codes, code)
+		}
+		slices.Sort(codes)
+		parts := make([]string, len(codes))
+		for i, code := range codes {
+			parts[i] = fmt.Sprintf("%d:%d", code, st.Status[code])
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%s\t%s\t%s\t%s\n", st.Name, st.Requests, st.Errors,
+			st.P50, st.P90, st.P99, st.Max, strings.Join(parts, " "))
+	}
+	tw.Flush()
+	return sb.String()
+}
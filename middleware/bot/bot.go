@@ -0,0 +1,151 @@
+// Package bot clasifica cada petición como humana, sospechosa o automatizada
+// mediante clasificadores intercambiables. El veredicto queda en el contexto
+// para que el rate limiting o un desafío decidan qué hacer con él.
+package bot
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// Class es la categoría asignada a una petición.
+type Class int
+
+const (
+	// Human es el valor por defecto cuando ningún clasificador objeta.
+	Human Class = iota
+	// Suspicious indica señales débiles de automatización.
+	Suspicious
+	// Bot indica un cliente automatizado no identificado.
+	Bot
+	// Crawler indica un bot que se identifica como indexador conocido.
+	Crawler
+)
+
+func (c Class) String() string {
+	switch c {
+	case Human:
+		return "human"
+	case Suspicious:
+		return "suspicious"
+	case Bot:
+		return "bot"
+	case Crawler:
+		return "crawler"
+	}
+	return "unknown"
+}
+
+// Verdict es el resultado de clasificar una petición. Score va de 0
+// (con certeza humano) a 1 (con certeza automatizado).
+type Verdict struct {
+	Class   Class
+	Score   float64
+	Reasons []string
+}
+
+// Classifier evalúa una petición.
+type Classifier interface {
+	Classify(r *http.Request) Verdict
+}
+
+// ClassifierFunc adapta una función al interfaz Classifier.
+type ClassifierFunc func(r *http.Request) Verdict
+
+// Classify implementa Classifier.
+func (f ClassifierFunc) Classify(r *http.Request) Verdict { return f(r) }
+
+type ctxKey struct{}
+
+// FromContext retorna el veredicto guardado por Middleware.
+func FromContext(ctx context.Context) (Verdict, bool) {
+	v, ok := ctx.Value(ctxKey{}).(Verdict)
+	return v, ok
+}
+
+// Middleware ejecuta classifiers sobre cada petición y guarda en el
+// contexto el veredicto combinado: prevalece el de mayor Score y se
+// acumulan los motivos de todos.
+func Middleware(classifiers ...Classifier) router.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var final Verdict
+			for _, c := range classifiers {
+				v := c.Classify(r)
+				final.Reasons = append(final.Reasons, v.Reasons...)
+				if v.Score > final.Score || (v.Score == final.Score && v.Class > final.Class) {
+					final.Class, final.Score = v.Class, v.Score
+				}
+			}
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), ctxKey{}, final)))
+		})
+	}
+}
+
+var crawlerTokens = []string{
+	"googlebot", "bingbot", "duckduckbot", "baiduspider", "yandexbot",
+	"applebot", "slurp", "facebookexternalhit", "linkedinbot", "twitterbot",
+}
+
+var toolTokens = []string{
+	"curl/", "wget/", "python-requests", "python-urllib", "aiohttp", "go-http-client",
+	"java/", "okhttp", "libwww-perl", "scrapy", "httpclient", "node-fetch", "axios/",
+	"headlesschrome", "phantomjs", "puppeteer", "playwright",
+}
+
+// UserAgent clasifica según el header User-Agent: vacío, herramientas de
+// línea de comandos o navegadores headless cuentan como Bot, y los
+// indexadores conocidos como Crawler. No verifica la identidad declarada.
+func UserAgent() Classifier {
+	return ClassifierFunc(func(r *http.Request) Verdict {
+		ua := strings.ToLower(r.UserAgent())
+		if ua == "" {
+			return Verdict{Class: Bot, Score: 0.8, Reasons: []string{"user-agent vacío"}}
+		}
+		for _, t := range crawlerTokens {
+			if strings.Contains(ua, t) {
+				return Verdict{Class: Crawler, Score: 0.9, Reasons: []string{"crawler declarado: " + t}}
+			}
+		}
+		for _, t := range toolTokens {
+			if strings.Contains(ua, t) {
+				return Verdict{Class: Bot, Score: 0.9, Reasons: []string{"herramienta automatizada: " + strings.TrimSuffix(t, "/")}}
+			}
+		}
+		if strings.Contains(ua, "bot") || strings.Contains(ua, "spider") || strings.Contains(ua, "crawl") {
+			return Verdict{Class: Bot, Score: 0.7, Reasons: []string{"user-agent de bot"}}
+		}
+		return Verdict{}
+	})
+}
+
+// HeaderAnomalies detecta peticiones que dicen venir de un navegador pero
+// carecen de headers que todo navegador envía.
+func HeaderAnomalies() Classifier {
+	return ClassifierFunc(func(r *http.Request) Verdict {
+		if !strings.Contains(r.UserAgent(), "Mozilla/") {
+			return Verdict{}
+		}
+		var reasons []string
+		if r.Header.Get("Accept") == "" {
+			reasons = append(reasons, "navegador sin Accept")
+		}
+		if r.Header.Get("Accept-Language") == "" {
+			reasons = append(reasons, "navegador sin Accept-Language")
+		}
+		if r.Header.Get("Accept-Encoding") == "" {
+			reasons = append(reasons, "navegador sin Accept-Encoding")
+		}
+		switch len(reasons) {
+		case 0:
+			return Verdict{}
+		case 1:
+			return Verdict{Class: Suspicious, Score: 0.4, Reasons: reasons}
+		default:
+			return Verdict{Class: Bot, Score: 0.7, Reasons: reasons}
+		}
+	})
+}
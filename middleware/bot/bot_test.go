@@ -0,0 +1,115 @@
+package bot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestUserAgent(t *testing.T) {
+	tests := []struct {
+		name      string
+		ua        string
+		wantClass Class
+		wantScore float64
+	}{
+		{"navegador", "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 Chrome/126.0 Safari/537.36", Human, 0},
+		{"vacío", "", Bot, 0.8},
+		{"crawler", "Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)", Crawler, 0.9},
+		{"curl", "curl/8.5.0", Bot, 0.9},
+		{"headless", "Mozilla/5.0 HeadlessChrome/126.0", Bot, 0.9},
+		{"cliente de Go", "Go-http-client/2.0", Bot, 0.9},
+		{"bot genérico", "MiMonitorBot/1.0", Bot, 0.7},
+		{"spider", "alguna-spider", Bot, 0.7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("User-Agent", tt.ua)
+			v := UserAgent().Classify(r)
+			if v.Class != tt.wantClass || v.Score != tt.wantScore {
+				t.Errorf("veredicto = %v %.1f, se esperaba %v %.1f", v.Class, v.Score, tt.wantClass, tt.wantScore)
+			}
+			if (v.Class == Human) != (len(v.Reasons) == 0) {
+				t.Errorf("motivos = %q", v.Reasons)
+			}
+		})
+	}
+}
+
+func TestHeaderAnomalies(t *testing.T) {
+	const browser = "Mozilla/5.0 (Windows NT 10.0) Firefox/128.0"
+	tests := []struct {
+		name      string
+		ua        string
+		headers   []string
+		wantClass Class
+		wantScore float64
+	}{
+		{"navegador completo", browser, []string{"Accept", "Accept-Language", "Accept-Encoding"}, Human, 0},
+		{"falta uno", browser, []string{"Accept", "Accept-Encoding"}, Suspicious, 0.4},
+		{"faltan varios", browser, []string{"Accept"}, Bot, 0.7},
+		{"no dice ser navegador", "curl/8.5.0", nil, Human, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("User-Agent", tt.ua)
+			for _, h := range tt.headers {
+				r.Header.Set(h, "x")
+			}
+			v := HeaderAnomalies().Classify(r)
+			if v.Class != tt.wantClass || v.Score != tt.wantScore {
+				t.Errorf("veredicto = %v %.1f (%q), se esperaba %v %.1f", v.Class, v.Score, v.Reasons, tt.wantClass, tt.wantScore)
+			}
+		})
+	}
+}
+
+func fixed(c Class, score float64, reason string) Classifier {
+	return ClassifierFunc(func(*http.Request) Verdict {
+		return Verdict{Class: c, Score: score, Reasons: []string{reason}}
+	})
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name        string
+		classifiers []Classifier
+		want        Verdict
+	}{
+		{"sin clasificadores", nil, Verdict{}},
+		{
+			"prevalece el mayor score",
+			[]Classifier{fixed(Suspicious, 0.4, "a"), fixed(Bot, 0.9, "b"), fixed(Crawler, 0.5, "c")},
+			Verdict{Class: Bot, Score: 0.9, Reasons: []string{"a", "b", "c"}},
+		},
+		{
+			"empate: la clase mayor",
+			[]Classifier{fixed(Bot, 0.9, "a"), fixed(Crawler, 0.9, "b")},
+			Verdict{Class: Crawler, Score: 0.9, Reasons: []string{"a", "b"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Verdict
+			var ok bool
+			h := Middleware(tt.classifiers...)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, ok = FromContext(r.Context())
+			}))
+			h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+			if !ok || !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("veredicto = %+v, se esperaba %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassString(t *testing.T) {
+	for c, want := range map[Class]string{Human: "human", Suspicious: "suspicious", Bot: "bot", Crawler: "crawler", Class(9): "unknown"} {
+		if got := c.String(); got != want {
+			t.Errorf("Class(%d).String() = %q, se esperaba %q", int(c), got, want)
+		}
+	}
+}
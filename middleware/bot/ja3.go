@@ -0,0 +1,28 @@
+package bot
+
+import (
+	"net/http"
+
+	"github.com/profe-ajedrez/transwarp/tlsinfo"
+)
+
+// JA3 clasifica según la huella TLS capturada por tlsinfo: las huellas de
+// known (MD5 en hexadecimal) reciben la clase asociada. Sin huella no
+// opina, por lo que es seguro en drivers que no sirven TLS directamente.
+func JA3(known map[string]Class) Classifier {
+	return ClassifierFunc(func(r *http.Request) Verdict {
+		info, ok := tlsinfo.FromContext(r.Context())
+		if !ok || info.JA3Hash == "" {
+			return Verdict{}
+		}
+		class, ok := known[info.JA3Hash]
+		if !ok || class == Human {
+			return Verdict{}
+		}
+		score := 0.9
+		if class == Suspicious {
+			score = 0.5
+		}
+		return Verdict{Class: class, Score: score, Reasons: []string{"huella TLS conocida: " + info.JA3Hash}}
+	})
+}
@@ -0,0 +1,43 @@
+package bot
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/profe-ajedrez/transwarp/tlsinfo"
+)
+
+func TestJA3(t *testing.T) {
+	known := map[string]Class{
+		"aaa": Bot,
+		"bbb": Suspicious,
+		"ccc": Human,
+	}
+	tests := []struct {
+		name      string
+		hash      string
+		withInfo  bool
+		wantClass Class
+		wantScore float64
+	}{
+		{"huella de bot", "aaa", true, Bot, 0.9},
+		{"huella sospechosa", "bbb", true, Suspicious, 0.5},
+		{"huella humana", "ccc", true, Human, 0},
+		{"huella desconocida", "zzz", true, Human, 0},
+		{"sin huella", "", true, Human, 0},
+		{"sin TLS", "", false, Human, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.withInfo {
+				r = r.WithContext(tlsinfo.WithInfo(r.Context(), tlsinfo.Info{JA3Hash: tt.hash}))
+			}
+			v := JA3(known).Classify(r)
+			if v.Class != tt.wantClass || v.Score != tt.wantScore {
+				t.Errorf("veredicto = %v %.1f, se esperaba %v %.1f", v.Class, v.Score, tt.wantClass, tt.wantScore)
+			}
+		})
+	}
+}
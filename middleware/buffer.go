@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"sync"
+)
+
+// DefaultMaxBufferBytes es el límite de BufferOptions.MaxBytes cuando es cero.
+const DefaultMaxBufferBytes = 1 << 20
+
+// maxPooledBuffer evita que buffers que crecieron demasiado queden
+// retenidos en el pool.
+const maxPooledBuffer = 4 << 20
+
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// BufferOptions configura un BufferedWriter.
+type BufferOptions struct {
+	// MaxBytes es el tamaño máximo a retener en memoria.
+	MaxBytes int
+	// OnSpill se invoca una única vez, antes de enviar nada al cliente,
+	// cuando el cuerpo supera MaxBytes o el handler pide Flush. A partir de
+	// ahí las escrituras pasan directo al cliente, por lo que el middleware
+	// debe quitar aquí los headers que dependían del cuerpo completo
+	// (ETag, Content-Length, Content-Encoding, ...).
+	OnSpill func(h http.Header, status int)
+}
+
+// BufferedWriter retiene la respuesta en un buffer tomado de un pool
+// compartido, de modo que middlewares como ETag, caché o compresión puedan
+// inspeccionar el cuerpo completo sin asignar un buffer propio cada uno.
+// Cada BufferedWriter debe liberarse con Release.
+type BufferedWriter struct {
+	http.ResponseWriter
+	opts    BufferOptions
+	buf     *bytes.Buffer
+	status  int
+	spilled bool
+}
+
+// AcquireBuffer envuelve w en un BufferedWriter.
+func AcquireBuffer(w http.ResponseWriter, opts BufferOptions) *BufferedWriter {
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = DefaultMaxBufferBytes
+	}
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return &BufferedWriter{ResponseWriter: w, opts: opts, buf: buf}
+}
+
+// WriteHeader registra el status sin enviarlo todavía.
+func (b *BufferedWriter) WriteHeader(status int) {
+	if b.spilled {
+		return
+	}
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+// Write acumula p en el buffer o, si se superó el límite, lo envía directo.
+func (b *BufferedWriter) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	if b.spilled {
+		return b.ResponseWriter.Write(p)
+	}
+	if b.buf.Len()+len(p) > b.opts.MaxBytes {
+		if err := b.spill(); err != nil {
+			return 0, err
+		}
+		return b.ResponseWriter.Write(p)
+	}
+	return b.buf.Write(p)
+}
+
+// Flush indica que el handler quiere hacer streaming: el buffer se vuelca
+// y el writer pasa a modo directo.
+func (b *BufferedWriter) Flush() {
+	if !b.spilled {
+		if b.status == 0 {
+			b.status = http.StatusOK
+		}
+		if b.spill() != nil {
+			return
+		}
+	}
+	_ = http.NewResponseController(b.ResponseWriter).Flush()
+}
+
+// Unwrap expone el writer original a http.ResponseController.
+func (b *BufferedWriter) Unwrap() http.ResponseWriter {
+	return b.ResponseWriter
+}
+
+func (b *BufferedWriter) spill() error {
+	b.spilled = true
+	if b.opts.OnSpill != nil {
+		b.opts.OnSpill(b.ResponseWriter.Header(), b.status)
+	}
+	b.ResponseWriter.WriteHeader(b.status)
+	_, err := b.ResponseWriter.Write(b.buf.Bytes())
+	b.buf.Reset()
+	return err
+}
+
+// Status retorna el status registrado (200 si el handler escribió sin
+// llamar a WriteHeader, 0 si no escribió nada).
+func (b *BufferedWriter) Status() int { return b.status }
+
+// Bytes retorna el cuerpo retenido. Sólo es válido hasta Release.
+func (b *BufferedWriter) Bytes() []byte { return b.buf.Bytes() }
+
+// Spilled indica si la respuesta ya se está enviando directamente.
+func (b *BufferedWriter) Spilled() bool { return b.spilled }
+
+// SetBody reemplaza el cuerpo retenido, p. ej. por su versión comprimida.
+func (b *BufferedWriter) SetBody(p []byte) {
+	b.buf.Reset()
+	b.buf.Write(p)
+}
+
+// Commit envía el status y el cuerpo retenido al cliente. No hace nada si
+// la respuesta ya se desbordó.
+func (b *BufferedWriter) Commit() error {
+	if b.spilled {
+		return nil
+	}
+	status := b.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	b.ResponseWriter.WriteHeader(status)
+	if b.buf.Len() == 0 {
+		return nil
+	}
+	_, err := b.ResponseWriter.Write(b.buf.Bytes())
+	return err
+}
+
+// Release devuelve el buffer al pool. El BufferedWriter no debe usarse
+// después.
+func (b *BufferedWriter) Release() {
+	if b.buf == nil {
+		return
+	}
+	if b.buf.Cap() <= maxPooledBuffer {
+		bufferPool.Put(b.buf)
+	}
+	b.buf = nil
+}
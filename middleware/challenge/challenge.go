@@ -0,0 +1,127 @@
+// Package challenge interpone un desafío liviano (prueba de trabajo, captcha,
+// ...) ante clientes sospechosos. Quien lo resuelve recibe una cookie
+// firmada que le permite pasar sin nuevos desafíos durante una ventana.
+package challenge
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/profe-ajedrez/transwarp/middleware/bot"
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// Backend implementa un tipo de desafío.
+type Backend interface {
+	// Issue responde al cliente con el desafío a resolver.
+	Issue(w http.ResponseWriter, r *http.Request)
+	// Verify reporta si la petición trae una solución válida. Retorna
+	// false si no trae ninguna.
+	Verify(r *http.Request) bool
+}
+
+// Config configura Middleware.
+type Config struct {
+	// Secret firma la cookie de paso. Es obligatorio.
+	Secret []byte
+	// Backend es el desafío a presentar; por defecto ProofOfWork.
+	Backend Backend
+	// Window es la validez de la cookie de paso; por defecto una hora.
+	Window time.Duration
+	// CookieName es el nombre de la cookie de paso; por defecto "tw_pass".
+	CookieName string
+	// Suspicious decide qué peticiones se desafían. Por defecto se usan
+	// los veredictos Suspicious y Bot de bot.Middleware y, si no hay
+	// veredicto, se desafía a todos.
+	Suspicious func(r *http.Request) bool
+}
+
+func (c *Config) defaults() {
+	if len(c.Secret) == 0 {
+		panic("challenge: Config.Secret es obligatorio")
+	}
+	if c.Backend == nil {
+		c.Backend = NewProofOfWork(c.Secret, 18)
+	}
+	if c.Window <= 0 {
+		c.Window = time.Hour
+	}
+	if c.CookieName == "" {
+		c.CookieName = "tw_pass"
+	}
+	if c.Suspicious == nil {
+		c.Suspicious = defaultSuspicious
+	}
+}
+
+func defaultSuspicious(r *http.Request) bool {
+	v, ok := bot.FromContext(r.Context())
+	if !ok {
+		return true
+	}
+	return v.Class == bot.Suspicious || v.Class == bot.Bot
+}
+
+// Middleware protege las rutas siguientes con el desafío configurado.
+func Middleware(cfg Config) router.Middleware {
+	cfg.defaults()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if !cfg.Suspicious(r) || cfg.hasPass(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if cfg.Backend.Verify(r) {
+				cfg.grantPass(w, r)
+				next.ServeHTTP(w, r)
+				return
+			}
+			w.Header().Set("Cache-Control", "no-store")
+			cfg.Backend.Issue(w, r)
+		})
+	}
+}
+
+// La cookie de paso tiene la forma <expiración>.<firma>, donde la firma
+// cubre la expiración y el User-Agent para dificultar su reutilización.
+func (c *Config) sign(exp int64, r *http.Request) string {
+	mac := hmac.New(sha256.New, c.Secret)
+	mac.Write([]byte(strconv.FormatInt(exp, 10)))
+	mac.Write([]byte{0})
+	mac.Write([]byte(r.UserAgent()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (c *Config) hasPass(r *http.Request) bool {
+	cookie, err := r.Cookie(c.CookieName)
+	if err != nil {
+		return false
+	}
+	expStr, sig, ok := strings.Cut(cookie.Value, ".")
+	if !ok {
+		return false
+	}
+	exp, err := strconv.ParseInt(expStr, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return false
+	}
+	return hmac.Equal([]byte(sig), []byte(c.sign(exp, r)))
+}
+
+func (c *Config) grantPass(w http.ResponseWriter, r *http.Request) {
+	exp := time.Now().Add(c.Window).Unix()
+	http.SetCookie(w, &http.Cookie{
+		Name:     c.CookieName,
+		Value:    strconv.FormatInt(exp, 10) + "." + c.sign(exp, r),
+		Path:     "/",
+		MaxAge:   int(c.Window / time.Second),
+		HttpOnly: true,
+		Secure:   r.TLS != nil,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
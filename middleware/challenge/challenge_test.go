@@ -0,0 +1,141 @@
+package challenge
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/profe-ajedrez/transwarp/middleware"
+)
+
+var secret = []byte("secreto-de-prueba")
+
+// issue pide un desafío JSON y retorna su token.
+func issue(t *testing.T, p *ProofOfWork, ua string) string {
+	t.Helper()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("User-Agent", ua)
+	w := httptest.NewRecorder()
+	p.Issue(w, r)
+	var body struct{ Challenge string }
+	if err := json.NewDecoder(w.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	return body.Challenge
+}
+
+func solve(token string, difficulty int) string {
+	for i := 0; ; i++ {
+		c := strconv.Itoa(i)
+		sum := sha256.Sum256([]byte(token + ":" + c))
+		if leadingZeroBits(sum[:]) >= difficulty {
+			return token + "/" + c
+		}
+	}
+}
+
+func solution(ua, sol string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("User-Agent", ua)
+	r.Header.Set(SolutionHeader, sol)
+	return r
+}
+
+func TestProofOfWorkVerify(t *testing.T) {
+	p := NewProofOfWork(secret, 8)
+	token := issue(t, p, "ua-1")
+	sol := solve(token, 8)
+
+	expired := NewProofOfWork(secret, 8)
+	expired.TTL = -time.Minute
+	expiredSol := solve(issue(t, expired, "ua-1"), 8)
+
+	payload, sig, _ := strings.Cut(token, ".")
+	tampered := solve(strings.Replace(payload, ":8:", ":1:", 1)+"."+sig, 1)
+
+	tests := []struct {
+		name string
+		p    *ProofOfWork
+		req  *http.Request
+		want bool
+	}{
+		{"sin solución", p, solution("ua-1", ""), false},
+		{"otro User-Agent", p, solution("ua-2", sol), false},
+		{"solución válida", p, solution("ua-1", sol), true},
+		{"solución repetida", p, solution("ua-1", sol), false},
+		{"contador incorrecto", p, solution("ua-1", token+"/x"), false},
+		{"payload alterado", p, solution("ua-1", tampered), false},
+		{"desafío expirado", expired, solution("ua-1", expiredSol), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.p.Verify(tt.req); got != tt.want {
+				t.Errorf("Verify = %v, se esperaba %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProofOfWorkPageNonce(t *testing.T) {
+	p := NewProofOfWork(secret, 8)
+	var body string
+	h := middleware.CSP("default-src 'self'")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rec := httptest.NewRecorder()
+		p.Issue(rec, r)
+		body = rec.Body.String()
+		if !strings.Contains(body, `<script nonce="`+middleware.CSPNonce(r)+`">`) {
+			t.Errorf("el script no lleva el nonce de la petición:\n%s", body)
+		}
+	}))
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	r.Header.Set("Accept", "text/html")
+	h.ServeHTTP(httptest.NewRecorder(), r)
+
+	// Sin CSP el script se emite sin atributo nonce.
+	w := httptest.NewRecorder()
+	p.Issue(w, r)
+	if !strings.Contains(w.Body.String(), "<script>") {
+		t.Error("sin CSP el script no debe llevar nonce")
+	}
+}
+
+func TestMiddlewarePassCookie(t *testing.T) {
+	p := NewProofOfWork(secret, 8)
+	h := Middleware(Config{Secret: secret, Backend: p})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, solution("ua-1", ""))
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("sin solución: status = %d, se esperaba 403", w.Code)
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, solution("ua-1", solve(issue(t, p, "ua-1"), 8)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("con solución: status = %d, se esperaba 200", w.Code)
+	}
+	cookies := w.Result().Cookies()
+	if len(cookies) != 1 {
+		t.Fatalf("se esperaba una cookie de paso, hay %d", len(cookies))
+	}
+
+	for _, tt := range []struct {
+		ua   string
+		want int
+	}{{"ua-1", http.StatusOK}, {"ua-2", http.StatusForbidden}} {
+		r := solution(tt.ua, "")
+		r.AddCookie(cookies[0])
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != tt.want {
+			t.Errorf("cookie con User-Agent %s: status = %d, se esperaba %d", tt.ua, w.Code, tt.want)
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package challenge
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"html"
+	"math/bits"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/profe-ajedrez/transwarp/middleware"
+	"github.com/profe-ajedrez/transwarp/store"
+)
+
+// SolutionHeader es el header con el que el cliente envía la solución.
+const SolutionHeader = "X-Challenge-Solution"
+
+// ProofOfWork es un Backend que pide encontrar un contador tal que
+// sha256(token + ":" + contador) tenga al menos Difficulty bits iniciales
+// en cero. Los navegadores lo resuelven con el JavaScript incluido en la
+// página de desafío; otros clientes reciben el desafío en JSON. Cada
+// desafío se acepta una sola vez y solo del User-Agent al que se emitió.
+type ProofOfWork struct {
+	secret     []byte
+	Difficulty int
+	// TTL es la validez de cada desafío emitido.
+	TTL time.Duration
+	// Used registra los desafíos ya resueltos para rechazar su
+	// reutilización; por defecto un store.NewMemory propio. Con varias
+	// réplicas debe ser compartido.
+	Used store.Store
+}
+
+// NewProofOfWork crea un desafío de prueba de trabajo firmado con secret.
+func NewProofOfWork(secret []byte, difficulty int) *ProofOfWork {
+	return &ProofOfWork{secret: secret, Difficulty: difficulty, TTL: 5 * time.Minute, Used: store.NewMemory()}
+}
+
+// token produce <payload>.<firma>, con payload = exp:dificultad:nonce. La
+// firma cubre además el User-Agent de r, de modo que la solución no sirve
+// a otro cliente.
+func (p *ProofOfWork) token(r *http.Request) string {
+	var nonce [12]byte
+	_, _ = rand.Read(nonce[:])
+	payload := fmt.Sprintf("%d:%d:%s", time.Now().Add(p.TTL).Unix(), p.Difficulty,
+		base64.RawURLEncoding.EncodeToString(nonce[:]))
+	return payload + "." + p.mac(payload, r)
+}
+
+func (p *ProofOfWork) mac(payload string, r *http.Request) string {
+	m := hmac.New(sha256.New, p.secret)
+	m.Write([]byte(payload))
+	m.Write([]byte{0})
+	m.Write([]byte(r.UserAgent()))
+	return base64.RawURLEncoding.EncodeToString(m.Sum(nil))
+}
+
+// Issue implementa Backend.
+func (p *ProofOfWork) Issue(w http.ResponseWriter, r *http.Request) {
+	token := p.token(r)
+	if strings.Contains(r.Header.Get("Accept"), "text/html") {
+		// Con middleware.CSP activo el script necesita el nonce de la
+		// petición para ejecutarse.
+		var nonce string
+		if n := middleware.CSPNonce(r); n != "" {
+			nonce = ` nonce="` + html.EscapeString(n) + `"`
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusForbidden)
+		fmt.Fprintf(w, powPage, nonce, strconv.Quote(token), p.Difficulty, strconv.Quote(SolutionHeader))
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	_ = json.NewEncoder(w).Encode(map[string]any{
+		"challenge":  token,
+		"difficulty": p.Difficulty,
+		"algorithm":  "sha256",
+		"header":     SolutionHeader,
+	})
+}
+
+// Verify implementa Backend. La solución tiene la forma <token>/<contador>
+// y se consume al verificarla: una solución repetida se rechaza.
+func (p *ProofOfWork) Verify(r *http.Request) bool {
+	solution := r.Header.Get(SolutionHeader)
+	token, counter, ok := strings.Cut(solution, "/")
+	if !ok {
+		return false
+	}
+	payload, sig, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(sig), []byte(p.mac(payload, r))) {
+		return false
+	}
+	parts := strings.SplitN(payload, ":", 3)
+	if len(parts) != 3 {
+		return false
+	}
+	exp, err1 := strconv.ParseInt(parts[0], 10, 64)
+	difficulty, err2 := strconv.Atoi(parts[1])
+	if err1 != nil || err2 != nil || time.Now().Unix() > exp {
+		return false
+	}
+	sum := sha256.Sum256([]byte(token + ":" + counter))
+	if leadingZeroBits(sum[:]) < difficulty {
+		return false
+	}
+	// Se registra la firma, que identifica al desafío, hasta que expire.
+	// Si el Store falla se rechaza: el cliente recibe un desafío nuevo.
+	ttl := time.Until(time.Unix(exp, 0)) + time.Second
+	fresh, err := p.Used.SetNX(r.Context(), "pow:"+sig, []byte{1}, ttl)
+	return err == nil && fresh
+}
+
+func leadingZeroBits(b []byte) int {
+	n := 0
+	for _, v := range b {
+		if v != 0 {
+			return n + bits.LeadingZeros8(v)
+		}
+		n += 8
+	}
+	return n
+}
+
+const powPage = `<!doctype html>
+<html><head><meta charset="utf-8"><title>Verificando…</title></head>
+<body><p>Verificando tu navegador…</p>
+<script%s>
+(async () => {
+  const token = %s, difficulty = %d, header = %s;
+  const enc = new TextEncoder();
+  const zeros = (b) => { let n = 0; for (const v of b) { if (v === 0) { n += 8; continue; } return n + Math.clz32(v) - 24; } return n; };
+  for (let i = 0; ; i++) {
+    const h = new Uint8Array(await crypto.subtle.digest("SHA-256", enc.encode(token + ":" + i)));
+    if (zeros(h) >= difficulty) {
+      await fetch(location.href, { headers: { [header]: token + "/" + i }, credentials: "same-origin" });
+      location.reload();
+      return;
+    }
+  }
+})();
+</script></body></html>
+`
@@ -0,0 +1,163 @@
+// Package contenttype verifica que el Content-Type declarado de una subida
+// coincida con el detectado a partir de sus primeros bytes, y rechaza los
+// tipos peligrosos según la política de la ruta, antes de que el handler
+// toque el contenido.
+package contenttype
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/profe-ajedrez/transwarp/problem"
+	"github.com/profe-ajedrez/transwarp/router"
+	"github.com/profe-ajedrez/transwarp/upload"
+)
+
+// sniffLen es cuántos bytes usa http.DetectContentType.
+const sniffLen = 512
+
+// DangerousTypes son los tipos que Policy rechaza por defecto: contenido
+// que un navegador podría ejecutar si luego se sirve desde el mismo
+// origen.
+var DangerousTypes = []string{
+	"text/html",
+	"application/xhtml+xml",
+	"image/svg+xml",
+	"text/xml",
+	"application/xml",
+	"text/javascript",
+	"application/javascript",
+	"application/x-msdownload",
+}
+
+// Errores de Check; se comparan con errors.Is.
+var (
+	ErrMismatch  = errors.New("contenttype: el contenido no coincide con el tipo declarado")
+	ErrForbidden = errors.New("contenttype: tipo no permitido")
+)
+
+// Policy define qué contenido acepta una ruta.
+type Policy struct {
+	// Allow restringe los tipos aceptados; admite comodines como
+	// "image/*". Vacío acepta cualquiera que no esté en Deny.
+	Allow []string
+	// Deny reemplaza DangerousTypes. Se evalúa contra el tipo declarado y
+	// el detectado.
+	Deny []string
+	// AllowUnknown acepta binarios que no se pueden identificar
+	// (application/octet-stream) con cualquier tipo declarado.
+	AllowUnknown bool
+}
+
+func (p Policy) deny() []string {
+	if p.Deny == nil {
+		return DangerousTypes
+	}
+	return p.Deny
+}
+
+// Check valida el tipo declarado contra los primeros bytes del contenido
+// y retorna el tipo detectado.
+func (p Policy) Check(declared string, head []byte) (string, error) {
+	sniffed, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	decl, _, err := mime.ParseMediaType(declared)
+	if err != nil || decl == "" {
+		decl = sniffed
+	}
+	for _, t := range []string{decl, sniffed} {
+		if matchAny(p.deny(), t) {
+			return sniffed, fmt.Errorf("%w: %s", ErrForbidden, t)
+		}
+	}
+	if len(p.Allow) > 0 && !matchAny(p.Allow, decl) {
+		return sniffed, fmt.Errorf("%w: %s", ErrForbidden, decl)
+	}
+	if !p.compatible(decl, sniffed) {
+		return sniffed, fmt.Errorf("%w: declarado %s, detectado %s", ErrMismatch, decl, sniffed)
+	}
+	return sniffed, nil
+}
+
+// compatible decide si decl es una descripción aceptable de sniffed. La
+// detección solo distingue texto genérico para formatos textuales, así que
+// text/plain es compatible con cualquier tipo textual declarado.
+func (p Policy) compatible(decl, sniffed string) bool {
+	switch {
+	case decl == sniffed:
+		return true
+	case sniffed == "text/plain":
+		return strings.HasPrefix(decl, "text/") || strings.HasSuffix(decl, "+json") ||
+			slices.Contains([]string{"application/json", "application/x-ndjson", "application/yaml"}, decl)
+	case sniffed == "application/octet-stream":
+		return p.AllowUnknown
+	case sniffed == "application/zip":
+		// Los formatos de Office, jar, apk y epub son zip.
+		return strings.HasPrefix(decl, "application/vnd.") || slices.Contains([]string{"application/java-archive", "application/epub+zip", "application/x-zip-compressed"}, decl)
+	}
+	return false
+}
+
+func matchAny(patterns []string, t string) bool {
+	return slices.ContainsFunc(patterns, func(p string) bool {
+		if prefix, ok := strings.CutSuffix(p, "/*"); ok {
+			return strings.HasPrefix(t, prefix+"/")
+		}
+		return p == t
+	})
+}
+
+// Upload adapta la política a upload.Options.Check, para validar cada
+// archivo de un multipart.
+func (p Policy) Upload() func(info upload.FileInfo, head []byte) error {
+	return func(info upload.FileInfo, head []byte) error {
+		_, err := p.Check(info.DeclaredType, head)
+		return err
+	}
+}
+
+// Middleware valida el cuerpo de las peticiones con policy antes de
+// invocar al handler, leyendo solo los primeros bytes; el handler recibe
+// el cuerpo completo. Un tipo prohibido responde 415 y un contenido que no
+// coincide con lo declarado, 400, ambos como problem+json. Los cuerpos multipart/form-data pasan
+// sin cambios: cada archivo se valida en upload.Files con Policy.Upload.
+func Middleware(policy Policy) router.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			declared := r.Header.Get("Content-Type")
+			if r.Body == nil || r.Body == http.NoBody || r.ContentLength == 0 || strings.HasPrefix(declared, "multipart/form-data") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			br := bufio.NewReaderSize(r.Body, sniffLen)
+			head, err := br.Peek(sniffLen)
+			if err != nil && err != io.EOF && !errors.Is(err, bufio.ErrBufferFull) {
+				_ = problem.Write(w, problem.New(http.StatusBadRequest, "No se pudo leer el cuerpo.").ForRequest(r))
+				return
+			}
+			if sniffed, err := policy.Check(declared, head); err != nil {
+				status := http.StatusBadRequest
+				if errors.Is(err, ErrForbidden) {
+					status = http.StatusUnsupportedMediaType
+				}
+				p := problem.New(status, err.Error()).
+					With("declared_type", declared).
+					With("detected_type", sniffed).
+					ForRequest(r)
+				_ = problem.Write(w, p)
+				return
+			}
+			r.Body = struct {
+				io.Reader
+				io.Closer
+			}{br, r.Body}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
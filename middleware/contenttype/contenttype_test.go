@@ -0,0 +1,100 @@
+package contenttype
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/profe-ajedrez/transwarp/upload"
+)
+
+var (
+	png  = "\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"
+	zip  = "PK\x03\x04\x14\x00\x00\x00"
+	html = "<!DOCTYPE html><html><script>alert(1)</script>"
+)
+
+func TestPolicyCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		policy   Policy
+		declared string
+		body     string
+		want     error
+	}{
+		{"coincide", Policy{}, "image/png", png, nil},
+		{"sin tipo declarado", Policy{}, "", png, nil},
+		{"declarado con parámetros", Policy{}, "text/plain; charset=utf-8", "hola", nil},
+		{"texto como JSON", Policy{}, "application/json", `{"a":1}`, nil},
+		{"texto como CSV", Policy{}, "text/csv", "a,b\n1,2", nil},
+		{"docx es zip", Policy{}, "application/vnd.openxmlformats-officedocument.wordprocessingml.document", zip, nil},
+		{"no coincide", Policy{}, "image/png", "GIF89a", ErrMismatch},
+		{"HTML declarado como imagen", Policy{}, "image/png", html, ErrForbidden},
+		{"HTML declarado", Policy{}, "text/html", "hola", ErrForbidden},
+		{"SVG declarado", Policy{}, "image/svg+xml", "<svg/>", ErrForbidden},
+		{"comodín permitido", Policy{Allow: []string{"image/*"}}, "image/png", png, nil},
+		{"fuera de Allow", Policy{Allow: []string{"image/*"}}, "application/pdf", "%PDF-1.7", ErrForbidden},
+		{"binario desconocido", Policy{}, "application/x-foo", "\x00\x01\x02\x03", ErrMismatch},
+		{"binario desconocido permitido", Policy{AllowUnknown: true}, "application/x-foo", "\x00\x01\x02\x03", nil},
+		{"Deny reemplaza la lista", Policy{Deny: []string{"image/png"}}, "text/html", html, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.policy.Check(tt.declared, []byte(tt.body))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Check = %v, se esperaba %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name       string
+		declared   string
+		body       string
+		wantStatus int
+	}{
+		{"coincide", "image/png", png + strings.Repeat("x", 2*sniffLen), http.StatusOK},
+		{"cuerpo vacío", "image/png", "", http.StatusOK},
+		{"multipart pasa", "multipart/form-data; boundary=x", html, http.StatusOK},
+		{"no coincide", "image/png", "GIF89a", http.StatusBadRequest},
+		{"tipo prohibido", "image/png", html, http.StatusUnsupportedMediaType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			h := Middleware(Policy{})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				b, _ := io.ReadAll(r.Body)
+				got = string(b)
+			}))
+			r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			r.Header.Set("Content-Type", tt.declared)
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, se esperaba %d", w.Code, tt.wantStatus)
+			}
+			if w.Code == http.StatusOK && got != tt.body {
+				t.Errorf("el handler recibió %d bytes, se esperaban %d", len(got), len(tt.body))
+			}
+			if w.Code != http.StatusOK && !strings.HasPrefix(w.Header().Get("Content-Type"), "application/problem+json") {
+				t.Errorf("Content-Type = %q, se esperaba problem+json", w.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestUpload(t *testing.T) {
+	check := Policy{Allow: []string{"image/*"}}.Upload()
+	if err := check(upload.FileInfo{DeclaredType: "image/png"}, []byte(png)); err != nil {
+		t.Errorf("PNG: %v", err)
+	}
+	if err := check(upload.FileInfo{DeclaredType: "image/png"}, []byte(html)); !errors.Is(err, ErrForbidden) {
+		t.Errorf("HTML: err = %v, se esperaba ErrForbidden", err)
+	}
+}
@@ -0,0 +1,154 @@
+package middleware
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// CORSOptions configura CORS.
+type CORSOptions struct {
+	// AllowOrigins admite orígenes exactos ("https://app.example.com"),
+	// "*" para cualquiera y comodines de subdominio
+	// ("https://*.example.com").
+	AllowOrigins []string
+	// AllowOriginPatterns se evalúan contra el origen completo.
+	AllowOriginPatterns []*regexp.Regexp
+	// AllowOriginFunc decide orígenes dinámicamente; se consulta si
+	// ninguna de las reglas anteriores coincide.
+	AllowOriginFunc func(r *http.Request, origin string) bool
+	// AllowMethods por defecto son GET, HEAD, POST, PUT, PATCH y DELETE.
+	AllowMethods []string
+	// AllowHeaders lista los headers que el cliente puede enviar. Vacío
+	// refleja los pedidos en Access-Control-Request-Headers.
+	AllowHeaders []string
+	// ExposeHeaders lista los headers de respuesta visibles al script.
+	ExposeHeaders []string
+	// AllowCredentials permite cookies y Authorization. No puede
+	// combinarse con "*": requiere orígenes explícitos, comodines de
+	// subdominio, patrones o AllowOriginFunc.
+	AllowCredentials bool
+	// MaxAge es cuánto puede cachear el navegador el preflight.
+	MaxAge time.Duration
+}
+
+type originWildcard struct{ prefix, suffix string }
+
+// CORS responde los preflight (OPTIONS con Access-Control-Request-Method)
+// con 204 sin invocar al handler, y agrega los headers CORS a las
+// respuestas de orígenes permitidos. Las peticiones de orígenes no
+// permitidos pasan sin headers CORS, de modo que el navegador las
+// bloquea; los preflight de esos orígenes responden 403.
+func CORS(opts CORSOptions) router.Middleware {
+	methods := opts.AllowMethods
+	if len(methods) == 0 {
+		methods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+	}
+	allowMethods := strings.Join(methods, ", ")
+	allowHeaders := strings.Join(opts.AllowHeaders, ", ")
+	expose := strings.Join(opts.ExposeHeaders, ", ")
+	maxAge := ""
+	if opts.MaxAge > 0 {
+		maxAge = strconv.Itoa(int(opts.MaxAge.Seconds()))
+	}
+
+	var (
+		anyOrigin bool
+		exact     = map[string]bool{}
+		wildcards []originWildcard
+	)
+	for _, o := range opts.AllowOrigins {
+		switch {
+		case o == "*":
+			if opts.AllowCredentials {
+				panic(`middleware: CORSOptions no admite AllowOrigins "*" con AllowCredentials`)
+			}
+			anyOrigin = true
+		case strings.Contains(o, "*"):
+			prefix, suffix, _ := strings.Cut(strings.ToLower(o), "*")
+			wildcards = append(wildcards, originWildcard{prefix, suffix})
+		default:
+			exact[strings.ToLower(o)] = true
+		}
+	}
+	allowed := func(r *http.Request, origin string) bool {
+		lo := strings.ToLower(origin)
+		if anyOrigin || exact[lo] {
+			return true
+		}
+		for _, w := range wildcards {
+			if len(lo) > len(w.prefix)+len(w.suffix) && strings.HasPrefix(lo, w.prefix) && strings.HasSuffix(lo, w.suffix) {
+				return true
+			}
+		}
+		for _, re := range opts.AllowOriginPatterns {
+			if re.MatchString(origin) {
+				return true
+			}
+		}
+		return opts.AllowOriginFunc != nil && opts.AllowOriginFunc(r, origin)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			preflight := r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != ""
+			h := w.Header()
+			if preflight {
+				AddVary(h, "Origin", "Access-Control-Request-Method", "Access-Control-Request-Headers")
+			} else {
+				AddVary(h, "Origin")
+			}
+			if origin == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !allowed(r, origin) {
+				if preflight {
+					w.WriteHeader(http.StatusForbidden)
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if anyOrigin {
+				h.Set("Access-Control-Allow-Origin", "*")
+			} else {
+				h.Set("Access-Control-Allow-Origin", origin)
+			}
+			if opts.AllowCredentials {
+				h.Set("Access-Control-Allow-Credentials", "true")
+			}
+
+			if !preflight {
+				if expose != "" {
+					h.Set("Access-Control-Expose-Headers", expose)
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			reqMethod := r.Header.Get("Access-Control-Request-Method")
+			if !slices.Contains(methods, reqMethod) {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			h.Set("Access-Control-Allow-Methods", allowMethods)
+			if allowHeaders != "" {
+				h.Set("Access-Control-Allow-Headers", allowHeaders)
+			} else if req := r.Header.Get("Access-Control-Request-Headers"); req != "" {
+				h.Set("Access-Control-Allow-Headers", req)
+			}
+			if maxAge != "" {
+				h.Set("Access-Control-Max-Age", maxAge)
+			}
+			w.WriteHeader(http.StatusNoContent)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestCORS(t *testing.T) {
+	opts := CORSOptions{
+		AllowOrigins:        []string{"https://app.example.com", "https://*.example.org"},
+		AllowOriginPatterns: []*regexp.Regexp{regexp.MustCompile(`^https://pr-\d+\.preview\.dev$`)},
+		AllowMethods:        []string{http.MethodGet, http.MethodPost},
+		ExposeHeaders:       []string{"X-Total"},
+		AllowCredentials:    true,
+		MaxAge:              10 * time.Minute,
+	}
+
+	tests := []struct {
+		name       string
+		method     string
+		origin     string
+		reqMethod  string
+		wantStatus int
+		wantOrigin string
+		wantNext   bool
+	}{
+		{"sin origen", http.MethodGet, "", "", http.StatusOK, "", true},
+		{"origen exacto", http.MethodGet, "https://app.example.com", "", http.StatusOK, "https://app.example.com", true},
+		{"exacto sin distinguir mayúsculas", http.MethodGet, "https://APP.example.com", "", http.StatusOK, "https://APP.example.com", true},
+		{"comodín de subdominio", http.MethodGet, "https://a.example.org", "", http.StatusOK, "https://a.example.org", true},
+		{"comodín no cubre el dominio base", http.MethodGet, "https://.example.org", "", http.StatusOK, "", true},
+		{"patrón", http.MethodGet, "https://pr-12.preview.dev", "", http.StatusOK, "https://pr-12.preview.dev", true},
+		{"origen no permitido", http.MethodGet, "https://evil.com", "", http.StatusOK, "", true},
+		{"sufijo engañoso", http.MethodGet, "https://app.example.com.evil.com", "", http.StatusOK, "", true},
+		{"preflight permitido", http.MethodOptions, "https://app.example.com", http.MethodPost, http.StatusNoContent, "https://app.example.com", false},
+		{"preflight de origen no permitido", http.MethodOptions, "https://evil.com", http.MethodPost, http.StatusForbidden, "", false},
+		{"preflight de método no permitido", http.MethodOptions, "https://app.example.com", http.MethodDelete, http.StatusForbidden, "https://app.example.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var called bool
+			h := CORS(opts)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true }))
+			r := httptest.NewRequest(tt.method, "/", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if tt.reqMethod != "" {
+				r.Header.Set("Access-Control-Request-Method", tt.reqMethod)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, se esperaba %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Allow-Origin = %q, se esperaba %q", got, tt.wantOrigin)
+			}
+			if called != tt.wantNext {
+				t.Errorf("handler invocado = %v, se esperaba %v", called, tt.wantNext)
+			}
+			if tt.wantOrigin != "" && w.Header().Get("Access-Control-Allow-Credentials") != "true" {
+				t.Error("falta Access-Control-Allow-Credentials")
+			}
+			if w.Header().Get("Vary") == "" {
+				t.Error("falta Vary: Origin")
+			}
+		})
+	}
+}
+
+func TestCORSPreflightHeaders(t *testing.T) {
+	h := CORS(CORSOptions{AllowOrigins: []string{"*"}, MaxAge: time.Hour})(http.NotFoundHandler())
+	r := httptest.NewRequest(http.MethodOptions, "/", nil)
+	r.Header.Set("Origin", "https://a.com")
+	r.Header.Set("Access-Control-Request-Method", http.MethodPut)
+	r.Header.Set("Access-Control-Request-Headers", "X-Token")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	for k, want := range map[string]string{
+		"Access-Control-Allow-Origin":      "*",
+		"Access-Control-Allow-Headers":     "X-Token",
+		"Access-Control-Max-Age":           "3600",
+		"Access-Control-Allow-Credentials": "",
+	} {
+		if got := w.Header().Get(k); got != want {
+			t.Errorf("%s = %q, se esperaba %q", k, got, want)
+		}
+	}
+}
+
+func TestCORSWildcardWithCredentialsPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal(`se esperaba panic con "*" y AllowCredentials`)
+		}
+	}()
+	CORS(CORSOptions{AllowOrigins: []string{"*"}, AllowCredentials: true})
+}
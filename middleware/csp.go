@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// NoncePlaceholder se reemplaza en la política de CSP por la fuente
+// 'nonce-...' de cada petición.
+const NoncePlaceholder = "{nonce}"
+
+type cspNonceKey struct{}
+
+// CSPNonce retorna el nonce generado por CSP para esta petición, o "" si
+// el middleware no está activo. Las plantillas lo usan en el atributo
+// nonce de sus <script> y <style>.
+func CSPNonce(r *http.Request) string {
+	nonce, _ := r.Context().Value(cspNonceKey{}).(string)
+	return nonce
+}
+
+// CSP genera un nonce aleatorio por petición y escribe el header
+// Content-Security-Policy con policy. Cada aparición de NoncePlaceholder
+// se reemplaza por 'nonce-<valor>'; si policy no lo contiene, la fuente se
+// agrega a script-src y style-src. Si no existen se crean con las fuentes
+// de default-src, para no perder, p. ej., 'self'.
+func CSP(policy string) router.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			nonce := newNonce()
+			w.Header().Set("Content-Security-Policy", withNonce(policy, nonce))
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), cspNonceKey{}, nonce)))
+		})
+	}
+}
+
+func newNonce() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	return base64.StdEncoding.EncodeToString(b[:])
+}
+
+func withNonce(policy, nonce string) string {
+	source := "'nonce-" + nonce + "'"
+	if strings.Contains(policy, NoncePlaceholder) {
+		return strings.ReplaceAll(policy, NoncePlaceholder, source)
+	}
+
+	var directives [][]string
+	for d := range strings.SplitSeq(policy, ";") {
+		if fields := strings.Fields(d); len(fields) > 0 {
+			directives = append(directives, fields)
+		}
+	}
+	find := func(name string) int {
+		return slices.IndexFunc(directives, func(d []string) bool { return strings.EqualFold(d[0], name) })
+	}
+
+	// Una directiva nueva reemplaza a default-src para su tipo, así que
+	// hereda sus fuentes; 'none' no puede combinarse con otras.
+	var inherited []string
+	if i := find("default-src"); i >= 0 {
+		inherited = slices.DeleteFunc(slices.Clone(directives[i][1:]), func(s string) bool {
+			return strings.EqualFold(s, "'none'")
+		})
+	}
+	for _, name := range []string{"script-src", "style-src"} {
+		if i := find(name); i >= 0 {
+			directives[i] = append(directives[i], source)
+			continue
+		}
+		// Sin default-src los estilos no están restringidos y no se crea
+		// style-src; script-src se crea siempre para exigir el nonce.
+		if name == "style-src" && inherited == nil {
+			continue
+		}
+		directives = append(directives, append(append([]string{name}, inherited...), source))
+	}
+
+	out := make([]string, len(directives))
+	for i, d := range directives {
+		out[i] = strings.Join(d, " ")
+	}
+	return strings.Join(out, "; ")
+}
@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/profe-ajedrez/transwarp/router"
+	"github.com/profe-ajedrez/transwarp/store"
+)
+
+// DedupHeaders son las cabeceras con el ID de entrega que Dedup busca por
+// defecto: Standard Webhooks, GitHub y una genérica.
+var DedupHeaders = []string{"Webhook-Id", "X-GitHub-Delivery", "X-Event-Id"}
+
+// DedupOptions configura Dedup.
+type DedupOptions struct {
+	// Store guarda los IDs ya vistos. Es obligatorio; con varias réplicas
+	// debe ser compartido.
+	Store store.Store
+	// Window es cuánto se recuerda un ID ya procesado; por defecto 24
+	// horas.
+	Window time.Duration
+	// Lease es cuánto se considera en proceso una entrega cuyo handler no
+	// terminó, p. ej. porque la réplica cayó; por defecto 5 minutos.
+	Lease time.Duration
+	// Headers reemplaza DedupHeaders.
+	Headers []string
+	// Prefix separa las claves de distintos receptores en el mismo Store;
+	// por defecto "dedup:".
+	Prefix string
+	// OnDuplicate se invoca por cada entrega descartada; puede ser nil.
+	OnDuplicate func(r *http.Request, id string)
+	// Processed decide, según el status del handler, si la entrega queda
+	// procesada; si no, el ID se libera y un reintento vuelve a invocar al
+	// handler. Por defecto solo los 2xx.
+	Processed func(status int) bool
+}
+
+// Valores que Dedup guarda por ID: la entrega está en proceso o ya se
+// procesó con éxito.
+var (
+	dedupPending = []byte("pending")
+	dedupDone    = []byte("done")
+)
+
+// Dedup descarta las entregas de webhook repetidas dentro de la ventana,
+// respondiendo 200 sin invocar al handler para que el emisor no reintente.
+// Un ID se da por procesado solo cuando el handler termina con 2xx (ver
+// DedupOptions.Processed) y sin panic; mientras tanto las entregas repetidas reciben 503 con
+// Retry-After, para que el emisor reintente si la primera falla. Las
+// peticiones sin ID pasan sin cambios y, si el Store falla, se procesan
+// antes que perder la entrega.
+func Dedup(opts DedupOptions) router.Middleware {
+	if opts.Store == nil {
+		panic("middleware: DedupOptions.Store es obligatorio")
+	}
+	if opts.Window <= 0 {
+		opts.Window = 24 * time.Hour
+	}
+	if opts.Lease <= 0 {
+		opts.Lease = 5 * time.Minute
+	}
+	if opts.Headers == nil {
+		opts.Headers = DedupHeaders
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = "dedup:"
+	}
+	if opts.Processed == nil {
+		opts.Processed = func(status int) bool { return status >= 200 && status < 300 }
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var id string
+			for _, h := range opts.Headers {
+				if id = r.Header.Get(h); id != "" {
+					break
+				}
+			}
+			if id == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			// Las escrituras posteriores al handler no deben fallar porque
+			// el cliente cortó la conexión.
+			ctx := context.WithoutCancel(r.Context())
+			key := opts.Prefix + id
+			fresh, err := opts.Store.SetNX(ctx, key, dedupPending, opts.Lease)
+			if err == nil && !fresh {
+				if state, err := opts.Store.Get(ctx, key); err != nil || !bytes.Equal(state, dedupDone) {
+					w.Header().Set("Retry-After", strconv.Itoa(max(ceilSeconds(opts.Lease)/10, 1)))
+					http.Error(w, "entrega en proceso", http.StatusServiceUnavailable)
+					return
+				}
+				if opts.OnDuplicate != nil {
+					opts.OnDuplicate(r, id)
+				}
+				w.Header().Set("X-Duplicate-Delivery", "true")
+				w.WriteHeader(http.StatusOK)
+				return
+			}
+			if !fresh {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			status := http.StatusOK
+			hw := &headerHookWriter{ResponseWriter: w, beforeHeader: func(s int) { status = s }}
+			defer func() {
+				if v := recover(); v != nil {
+					_ = opts.Store.Delete(ctx, key)
+					panic(v)
+				}
+				if !opts.Processed(status) {
+					_ = opts.Store.Delete(ctx, key)
+					return
+				}
+				_ = opts.Store.Set(ctx, key, dedupDone, opts.Window)
+			}()
+			next.ServeHTTP(hw, r)
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/profe-ajedrez/transwarp/store"
+)
+
+func deliver(h http.Handler, id string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPost, "/hook", nil)
+	if id != "" {
+		r.Header.Set("Webhook-Id", id)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestDedup(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []int // status que responde el handler en cada entrega
+		want     []int // status que recibe el emisor
+		calls    int
+		// processed reemplaza el criterio por defecto si no es nil.
+		processed func(status int) bool
+	}{
+		{"entrega única", []int{200}, []int{200}, 1, nil},
+		{"duplicado tras éxito", []int{200, 200}, []int{200, 200}, 1, nil},
+		{"reintento tras 5xx", []int{500, 200, 200}, []int{500, 200, 200}, 2, nil},
+		{"reintento tras 4xx", []int{400, 200}, []int{400, 200}, 2, nil},
+		{"4xx procesada si se configura", []int{422, 200}, []int{422, 200}, 1, func(status int) bool { return status < 500 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			h := Dedup(DedupOptions{Store: store.NewMemory(), Processed: tt.processed})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statuses[calls])
+				calls++
+			}))
+			for i, want := range tt.want {
+				if got := deliver(h, "evt-1").Code; got != want {
+					t.Errorf("entrega %d: status = %d, se esperaba %d", i, got, want)
+				}
+			}
+			if calls != tt.calls {
+				t.Errorf("handler invocado %d veces, se esperaban %d", calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestDedupConcurrentRetry(t *testing.T) {
+	s := store.NewMemory()
+	var second *httptest.ResponseRecorder
+	var h http.Handler
+	h = Dedup(DedupOptions{Store: s})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// El reintento llega mientras la primera entrega sigue en curso.
+		if second == nil {
+			second = deliver(h, "evt-1")
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+
+	if got := deliver(h, "evt-1").Code; got != http.StatusInternalServerError {
+		t.Fatalf("primera entrega: status = %d", got)
+	}
+	if second.Code != http.StatusServiceUnavailable || second.Header().Get("Retry-After") == "" {
+		t.Errorf("reintento concurrente: status = %d, Retry-After = %q; se esperaba 503 con Retry-After",
+			second.Code, second.Header().Get("Retry-After"))
+	}
+	if second.Header().Get("X-Duplicate-Delivery") != "" {
+		t.Error("un reintento concurrente no debe marcarse como duplicado")
+	}
+}
+
+func TestDedupPanicForgetsID(t *testing.T) {
+	fail := true
+	h := Dedup(DedupOptions{Store: store.NewMemory()})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			panic("boom")
+		}
+	}))
+	func() {
+		defer func() { _ = recover() }()
+		deliver(h, "evt-1")
+	}()
+	fail = false
+	if w := deliver(h, "evt-1"); w.Header().Get("X-Duplicate-Delivery") != "" {
+		t.Error("tras un panic el ID debe olvidarse")
+	}
+}
+
+func TestDedupWithoutID(t *testing.T) {
+	calls := 0
+	h := Dedup(DedupOptions{Store: store.NewMemory()})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { calls++ }))
+	deliver(h, "")
+	deliver(h, "")
+	if calls != 2 {
+		t.Errorf("handler invocado %d veces, se esperaban 2", calls)
+	}
+}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// HeaderPolicy define cómo normalizar los headers de respuesta de un grupo
+// de rutas. Se evalúa después del handler, justo antes de enviar el status.
+type HeaderPolicy struct {
+	// Set fija estos headers siempre, reemplazando lo que haya definido el handler.
+	Set map[string]string
+	// Default fija estos headers sólo si el handler no los definió.
+	Default map[string]string
+	// Remove elimina estos headers de la respuesta.
+	Remove []string
+	// OnError se aplica además de la política base cuando el status es >= 400,
+	// por ejemplo para quitar headers de caché o de depuración en errores.
+	OnError *HeaderPolicy
+}
+
+func (p *HeaderPolicy) apply(h http.Header, status int) {
+	for name, value := range p.Default {
+		if h.Get(name) == "" {
+			h.Set(name, value)
+		}
+	}
+	for name, value := range p.Set {
+		h.Set(name, value)
+	}
+	for _, name := range p.Remove {
+		h.Del(name)
+	}
+	if p.OnError != nil && status >= http.StatusBadRequest {
+		p.OnError.apply(h, status)
+	}
+}
+
+// ResponseHeaders retorna un middleware que aplica policy a cada respuesta,
+// centralizando por ejemplo los Cache-Control por defecto o el borrado de
+// headers sensibles en respuestas de error.
+func ResponseHeaders(policy HeaderPolicy) router.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			hw := &headerHookWriter{ResponseWriter: w}
+			hw.beforeHeader = func(status int) { policy.apply(w.Header(), status) }
+			next.ServeHTTP(hw, r)
+			if !hw.wroteHeader {
+				// El handler no escribió nada: net/http enviará 200 al terminar.
+				hw.WriteHeader(http.StatusOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+// Package impersonate permite que un usuario privilegiado actúe como otra
+// identidad mediante una cabecera firmada, típico de herramientas internas
+// de soporte. La identidad efectiva se reemplaza en el contexto y ambas
+// identidades quedan registradas en la auditoría.
+package impersonate
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// DefaultHeader es la cabecera que transporta el token de suplantación.
+const DefaultHeader = "X-Impersonate"
+
+// Event describe una petición atendida bajo suplantación.
+type Event struct {
+	// Actor es la identidad real, quien suplanta.
+	Actor string
+	// Subject es la identidad suplantada.
+	Subject string
+	Method  string
+	Path    string
+	// Denied indica que la suplantación fue rechazada.
+	Denied bool
+	Reason string
+	Time   time.Time
+}
+
+// Config configura Middleware.
+type Config struct {
+	// Secret firma los tokens. Es obligatorio.
+	Secret []byte
+	// Identity retorna la identidad autenticada de la petición. Es
+	// obligatorio; el middleware debe ir después de la autenticación.
+	Identity func(r *http.Request) (string, bool)
+	// Allowed decide si actor puede suplantar a subject. Es obligatorio.
+	Allowed func(r *http.Request, actor, subject string) bool
+	// Audit recibe cada petición suplantada o rechazada.
+	Audit func(r *http.Request, e Event)
+	// Header es la cabecera del token; por defecto DefaultHeader.
+	Header string
+	// MaxAge limita la antigüedad del token; por defecto 15 minutos.
+	MaxAge time.Duration
+}
+
+func (c *Config) defaults() {
+	if len(c.Secret) == 0 {
+		panic("impersonate: Config.Secret es obligatorio")
+	}
+	if c.Identity == nil || c.Allowed == nil {
+		panic("impersonate: Config.Identity y Config.Allowed son obligatorios")
+	}
+	if c.Header == "" {
+		c.Header = DefaultHeader
+	}
+	if c.MaxAge <= 0 {
+		c.MaxAge = 15 * time.Minute
+	}
+}
+
+type ctxKey struct{}
+
+type identities struct {
+	actor, subject string
+}
+
+// Subject retorna la identidad efectiva de la petición y si hay una
+// suplantación activa.
+func Subject(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(ctxKey{}).(identities)
+	return id.subject, ok
+}
+
+// Actor retorna la identidad real de quien suplanta, si la hay.
+func Actor(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(ctxKey{}).(identities)
+	return id.actor, ok
+}
+
+// Sign genera el token con el que actor suplanta a subject, válido desde
+// at. Lo emite la herramienta interna que inicia la suplantación.
+func Sign(secret []byte, actor, subject string, at time.Time) string {
+	ts := strconv.FormatInt(at.Unix(), 10)
+	enc := base64.RawURLEncoding
+	return enc.EncodeToString([]byte(subject)) + "." + ts + "." + enc.EncodeToString(mac(secret, actor, subject, ts))
+}
+
+func mac(secret []byte, actor, subject, ts string) []byte {
+	m := hmac.New(sha256.New, secret)
+	m.Write([]byte(actor + "\x00" + subject + "\x00" + ts))
+	return m.Sum(nil)
+}
+
+// verify valida token para actor y retorna la identidad suplantada.
+func (c *Config) verify(token, actor string, now time.Time) (string, string) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "", "token malformado"
+	}
+	enc := base64.RawURLEncoding
+	subject, err := enc.DecodeString(parts[0])
+	if err != nil || len(subject) == 0 {
+		return "", "token malformado"
+	}
+	sig, err := enc.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(sig, mac(c.Secret, actor, string(subject), parts[1])) {
+		return "", "firma inválida"
+	}
+	ts, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return "", "token malformado"
+	}
+	if age := now.Sub(time.Unix(ts, 0)); age > c.MaxAge || age < -time.Minute {
+		return "", "token expirado"
+	}
+	return string(subject), ""
+}
+
+// Middleware aplica la suplantación cuando la petición trae la cabecera.
+// El token está ligado al actor que lo firmó, de modo que filtrarlo no
+// sirve a otra identidad. Un token inválido o no autorizado responde 403;
+// las peticiones sin cabecera pasan sin cambios.
+func Middleware(cfg Config) router.Middleware {
+	cfg.defaults()
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := r.Header.Get(cfg.Header)
+			if token == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			actor, ok := cfg.Identity(r)
+			if !ok {
+				http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+				return
+			}
+
+			now := time.Now()
+			e := Event{Actor: actor, Method: r.Method, Path: r.URL.Path, Time: now}
+			subject, reason := cfg.verify(token, actor, now)
+			if reason == "" && !cfg.Allowed(r, actor, subject) {
+				reason = "no autorizado"
+			}
+			e.Subject = subject
+			if reason != "" {
+				e.Denied, e.Reason = true, reason
+				if cfg.Audit != nil {
+					cfg.Audit(r, e)
+				}
+				http.Error(w, "suplantación rechazada: "+reason, http.StatusForbidden)
+				return
+			}
+			if cfg.Audit != nil {
+				cfg.Audit(r, e)
+			}
+
+			// La cabecera no debe llegar a servicios aguas abajo.
+			r.Header.Del(cfg.Header)
+			w.Header().Set("X-Impersonated-By", actor)
+			ctx := context.WithValue(r.Context(), ctxKey{}, identities{actor: actor, subject: subject})
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
@@ -0,0 +1,101 @@
+package impersonate
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var secret = []byte("secreto")
+
+func TestMiddleware(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		user        string
+		token       string
+		wantStatus  int
+		wantSubject string
+		wantDenied  bool
+	}{
+		{"sin cabecera", "soporte", "", http.StatusOK, "", false},
+		{"sin autenticar", "", Sign(secret, "soporte", "cliente", now), http.StatusUnauthorized, "", false},
+		{"suplantación válida", "soporte", Sign(secret, "soporte", "cliente", now), http.StatusOK, "cliente", false},
+		{"token de otro actor", "otro", Sign(secret, "soporte", "cliente", now), http.StatusForbidden, "", true},
+		{"otra clave", "soporte", Sign([]byte("otra"), "soporte", "cliente", now), http.StatusForbidden, "", true},
+		{"token expirado", "soporte", Sign(secret, "soporte", "cliente", now.Add(-time.Hour)), http.StatusForbidden, "", true},
+		{"token del futuro", "soporte", Sign(secret, "soporte", "cliente", now.Add(time.Hour)), http.StatusForbidden, "", true},
+		{"no autorizado", "soporte", Sign(secret, "soporte", "admin", now), http.StatusForbidden, "", true},
+		{"token malformado", "soporte", "abc", http.StatusForbidden, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var events []Event
+			var subject, header string
+			h := Middleware(Config{
+				Secret: secret,
+				Identity: func(r *http.Request) (string, bool) {
+					return tt.user, tt.user != ""
+				},
+				Allowed: func(r *http.Request, actor, subject string) bool { return subject != "admin" },
+				Audit:   func(r *http.Request, e Event) { events = append(events, e) },
+			})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				subject, _ = Subject(r.Context())
+				header = r.Header.Get(DefaultHeader)
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.token != "" {
+				r.Header.Set(DefaultHeader, tt.token)
+			}
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, se esperaba %d", w.Code, tt.wantStatus)
+			}
+			if subject != tt.wantSubject {
+				t.Errorf("Subject = %q, se esperaba %q", subject, tt.wantSubject)
+			}
+			if header != "" {
+				t.Error("la cabecera de suplantación no debe llegar al handler")
+			}
+			if tt.wantSubject != "" && w.Header().Get("X-Impersonated-By") != tt.user {
+				t.Errorf("X-Impersonated-By = %q", w.Header().Get("X-Impersonated-By"))
+			}
+			audited := tt.wantSubject != "" || tt.wantDenied
+			if audited != (len(events) == 1) {
+				t.Fatalf("eventos de auditoría = %d", len(events))
+			}
+			if audited && (events[0].Denied != tt.wantDenied || events[0].Actor != tt.user) {
+				t.Errorf("evento = %+v", events[0])
+			}
+		})
+	}
+}
+
+func TestActor(t *testing.T) {
+	h := Middleware(Config{
+		Secret:   secret,
+		Identity: func(r *http.Request) (string, bool) { return "soporte", true },
+		Allowed:  func(r *http.Request, actor, subject string) bool { return true },
+		MaxAge:   time.Minute,
+	})(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if actor, ok := Actor(r.Context()); !ok || actor != "soporte" {
+			t.Errorf("Actor = %q, %v", actor, ok)
+		}
+	}))
+	for at, want := range map[time.Time]int{
+		time.Now():                       http.StatusOK,
+		time.Now().Add(-2 * time.Minute): http.StatusForbidden,
+	} {
+		r := httptest.NewRequest(http.MethodGet, "/", nil)
+		r.Header.Set(DefaultHeader, Sign(secret, "soporte", "cliente", at))
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != want {
+			t.Errorf("token de %v: status = %d, se esperaba %d", at, w.Code, want)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// LegacyRoute asocia un esquema de URL antiguo con su reemplazo. Ambos
+// patrones admiten segmentos :name y un comodín final *name; los
+// parámetros capturados en From se sustituyen en To.
+type LegacyRoute struct {
+	From string
+	To   string
+	// Deprecated es la fecha desde la que el esquema está obsoleto.
+	Deprecated time.Time
+	// Sunset es la fecha en que el esquema dejará de responder.
+	Sunset time.Time
+}
+
+// LegacyOptions configura Legacy.
+type LegacyOptions struct {
+	// OnLegacy se invoca por cada petición que usó un esquema antiguo, para
+	// alimentar métricas o logs; puede ser nil.
+	OnLegacy func(r *http.Request, route LegacyRoute)
+}
+
+// Legacy reescribe internamente las peticiones que usan rutas antiguas
+// hacia las nuevas y agrega a la respuesta los headers Deprecation, Sunset
+// y Link (rel="successor-version"), facilitando migraciones graduales de
+// la API sin mantener dos implementaciones.
+func Legacy(routes []LegacyRoute, opts LegacyOptions) router.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, route := range routes {
+				params, ok := matchPattern(route.From, r.URL.Path)
+				if !ok {
+					continue
+				}
+				target := fillPattern(route.To, params)
+
+				h := w.Header()
+				if route.Deprecated.IsZero() {
+					h.Set("Deprecation", "true")
+				} else {
+					h.Set("Deprecation", "@"+strconv.FormatInt(route.Deprecated.Unix(), 10))
+				}
+				if !route.Sunset.IsZero() {
+					h.Set("Sunset", route.Sunset.UTC().Format(http.TimeFormat))
+				}
+				h.Add("Link", "<"+target+`>; rel="successor-version"`)
+
+				if opts.OnLegacy != nil {
+					opts.OnLegacy(r, route)
+				}
+
+				r = r.Clone(r.Context())
+				r.URL.Path = target
+				r.URL.RawPath = ""
+				r.RequestURI = r.URL.RequestURI()
+				break
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// matchPattern compara path contra pattern segmento a segmento y retorna
+// los parámetros capturados.
+func matchPattern(pattern, path string) (map[string]string, bool) {
+	ps := strings.Split(strings.Trim(pattern, "/"), "/")
+	xs := strings.Split(strings.Trim(path, "/"), "/")
+	params := map[string]string{}
+	for i, seg := range ps {
+		if name, ok := strings.CutPrefix(seg, "*"); ok {
+			params[name] = strings.Join(xs[min(i, len(xs)):], "/")
+			return params, true
+		}
+		if i >= len(xs) {
+			return nil, false
+		}
+		if name, ok := strings.CutPrefix(seg, ":"); ok {
+			if xs[i] == "" {
+				return nil, false
+			}
+			params[name] = xs[i]
+			continue
+		}
+		if seg != xs[i] {
+			return nil, false
+		}
+	}
+	return params, len(ps) == len(xs)
+}
+
+func fillPattern(pattern string, params map[string]string) string {
+	segs := strings.Split(pattern, "/")
+	for i, seg := range segs {
+		if name, ok := strings.CutPrefix(seg, ":"); ok {
+			segs[i] = params[name]
+		} else if name, ok := strings.CutPrefix(seg, "*"); ok {
+			segs[i] = params[name]
+		}
+	}
+	return strings.Join(segs, "/")
+}
@@ -0,0 +1,38 @@
+package middleware
+
+import "github.com/profe-ajedrez/transwarp/router"
+
+// DefaultPublicCacheControl es el Cache-Control que Public aplica a las
+// respuestas que no definen uno propio.
+const DefaultPublicCacheControl = "public, max-age=60"
+
+var (
+	publicHeaders = HeaderPolicy{
+		Default: map[string]string{"Cache-Control": DefaultPublicCacheControl},
+		OnError: &HeaderPolicy{Set: map[string]string{"Cache-Control": "no-store"}},
+	}
+	authenticatedHeaders = HeaderPolicy{
+		Set: map[string]string{"Cache-Control": "no-store"},
+	}
+)
+
+// Public crea un grupo de r bajo prefix para contenido anónimo: las
+// respuestas exitosas sin Cache-Control propio se marcan como cacheables
+// con DefaultPublicCacheControl; las de error nunca se cachean.
+func Public(r router.Router, prefix string) router.Router {
+	g := r.Group(prefix)
+	g.Use(ResponseHeaders(publicHeaders))
+	return g
+}
+
+// Authenticated crea un grupo de r bajo prefix protegido por auth. Todas
+// sus respuestas, incluidos los rechazos de auth, llevan Cache-Control:
+// no-store para que ninguna caché compartida guarde datos de un usuario.
+// La política de headers se registra antes que auth para cubrir también
+// sus respuestas.
+func Authenticated(r router.Router, prefix string, auth router.Middleware) router.Router {
+	g := r.Group(prefix)
+	g.Use(ResponseHeaders(authenticatedHeaders))
+	g.Use(auth)
+	return g
+}
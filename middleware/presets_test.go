@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// groupRecorder registra el prefijo y los middlewares de un Group.
+type groupRecorder struct {
+	router.Router
+	prefix string
+	mws    []router.Middleware
+}
+
+func (g *groupRecorder) Group(prefix string) router.Router {
+	g.prefix = prefix
+	return g
+}
+
+func (g *groupRecorder) Use(mw router.Middleware) { g.mws = append(g.mws, mw) }
+
+func (g *groupRecorder) wrap(h http.Handler) http.Handler {
+	for i := len(g.mws) - 1; i >= 0; i-- {
+		h = g.mws[i](h)
+	}
+	return h
+}
+
+func TestPresets(t *testing.T) {
+	deny := func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") == "" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+	tests := []struct {
+		name    string
+		preset  func(router.Router) router.Router
+		handler http.HandlerFunc
+		auth    bool
+		want    string
+	}{
+		{"público sin Cache-Control", func(r router.Router) router.Router { return Public(r, "/pub") },
+			func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("ok")) }, false, DefaultPublicCacheControl},
+		{"público con Cache-Control propio", func(r router.Router) router.Router { return Public(r, "/pub") },
+			func(w http.ResponseWriter, r *http.Request) { w.Header().Set("Cache-Control", "max-age=3600") }, false, "max-age=3600"},
+		{"público con error", func(r router.Router) router.Router { return Public(r, "/pub") },
+			func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Cache-Control", "max-age=3600")
+				w.WriteHeader(http.StatusNotFound)
+			}, false, "no-store"},
+		{"autenticado", func(r router.Router) router.Router { return Authenticated(r, "/app", deny) },
+			func(w http.ResponseWriter, r *http.Request) { w.Header().Set("Cache-Control", "public") }, true, "no-store"},
+		{"rechazo de auth", func(r router.Router) router.Router { return Authenticated(r, "/app", deny) },
+			func(w http.ResponseWriter, r *http.Request) {}, false, "no-store"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := tt.preset(&groupRecorder{}).(*groupRecorder)
+			if g.prefix != "/pub" && g.prefix != "/app" {
+				t.Errorf("prefijo = %q", g.prefix)
+			}
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.auth {
+				r.Header.Set("Authorization", "Bearer x")
+			}
+			w := httptest.NewRecorder()
+			g.wrap(tt.handler).ServeHTTP(w, r)
+			if got := w.Header().Get("Cache-Control"); got != tt.want {
+				t.Errorf("Cache-Control = %q, se esperaba %q", got, tt.want)
+			}
+		})
+	}
+}
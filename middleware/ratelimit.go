@@ -0,0 +1,179 @@
+package middleware
+
+import (
+	"errors"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/profe-ajedrez/transwarp/router"
+	"github.com/profe-ajedrez/transwarp/store"
+)
+
+// RateLimitAlgorithm selecciona cómo RateLimit cuenta las peticiones.
+type RateLimitAlgorithm int
+
+const (
+	// SlidingWindow aproxima una ventana deslizante ponderando el contador
+	// de la ventana anterior. Funciona con cualquier store.Store.
+	SlidingWindow RateLimitAlgorithm = iota
+	// TokenBucket admite ráfagas de hasta Burst peticiones y recupera
+	// Limit tokens por Window. Requiere un Store que implemente
+	// store.TokenBucket.
+	TokenBucket
+)
+
+// RateLimitOptions configura RateLimit.
+type RateLimitOptions struct {
+	// Limit es la cantidad de peticiones permitidas por Window. Es
+	// obligatorio.
+	Limit int
+	// Window es la duración de la ventana; por defecto un minuto.
+	Window time.Duration
+	// Algorithm es SlidingWindow por defecto.
+	Algorithm RateLimitAlgorithm
+	// Burst es la capacidad del bucket con TokenBucket; por defecto Limit.
+	Burst int
+	// Key identifica al cliente; por defecto KeyByIP. Una clave vacía deja
+	// pasar la petición sin limitarla.
+	Key func(r *http.Request) string
+	// Store guarda los contadores; por defecto un store.NewMemory propio.
+	// Con varias réplicas debe ser compartido, p. ej. store.NewRedis.
+	Store store.Store
+	// Prefix separa las claves de distintos límites en el mismo Store; por
+	// defecto "ratelimit:".
+	Prefix string
+
+	now func() time.Time
+}
+
+// KeyByIP identifica al cliente por la IP de r.RemoteAddr. Detrás de un
+// proxy, RemoteAddr debe reescribirse antes con la IP real del cliente.
+func KeyByIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// KeyByHeader identifica al cliente por la cabecera name, p. ej. una API
+// key.
+func KeyByHeader(name string) func(r *http.Request) string {
+	return func(r *http.Request) string {
+		return r.Header.Get(name)
+	}
+}
+
+// RateLimit limita las peticiones de cada cliente según opts. Las
+// respuestas permitidas llevan los headers RateLimit-*; las rechazadas se
+// responden con TooManyRequests. Si el Store falla la petición se procesa,
+// para que una caída del backend no deje fuera a todos los clientes.
+func RateLimit(opts RateLimitOptions) router.Middleware {
+	if opts.Limit <= 0 {
+		panic("middleware: RateLimitOptions.Limit debe ser positivo")
+	}
+	if opts.Window <= 0 {
+		opts.Window = time.Minute
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = opts.Limit
+	}
+	if opts.Key == nil {
+		opts.Key = KeyByIP
+	}
+	if opts.Store == nil {
+		opts.Store = store.NewMemory()
+	}
+	if opts.Prefix == "" {
+		opts.Prefix = "ratelimit:"
+	}
+	if opts.now == nil {
+		opts.now = time.Now
+	}
+
+	take := opts.slidingWindow
+	if opts.Algorithm == TokenBucket {
+		if _, ok := opts.Store.(store.TokenBucket); !ok {
+			panic("middleware: TokenBucket requiere un Store que implemente store.TokenBucket")
+		}
+		take = opts.tokenBucket
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := opts.Key(r)
+			if key == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			info, ok, err := take(r, opts.Prefix+key)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if !ok {
+				TooManyRequests(w, r, info)
+				return
+			}
+			SetRateLimitHeaders(w.Header(), info)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// slidingWindow cuenta la petición en la ventana fija actual y estima la
+// ventana deslizante sumando la fracción de la anterior que aún se solapa.
+// Las peticiones rechazadas no se cuentan.
+func (o *RateLimitOptions) slidingWindow(r *http.Request, key string) (LimitInfo, bool, error) {
+	ctx := r.Context()
+	now := o.now()
+	idx := now.UnixNano() / int64(o.Window)
+	elapsed := time.Duration(now.UnixNano() - idx*int64(o.Window))
+	info := LimitInfo{Limit: o.Limit, Window: o.Window, Reset: o.Window - elapsed}
+
+	cur, err := o.Store.Incr(ctx, key+":"+strconv.FormatInt(idx, 10), 1, 2*o.Window)
+	if err != nil {
+		return info, false, err
+	}
+	var prev int64
+	if b, err := o.Store.Get(ctx, key+":"+strconv.FormatInt(idx-1, 10)); err == nil {
+		prev, _ = strconv.ParseInt(string(b), 10, 64)
+	} else if !errors.Is(err, store.ErrNotFound) {
+		return info, false, err
+	}
+
+	weight := 1 - float64(elapsed)/float64(o.Window)
+	count := float64(prev)*weight + float64(cur)
+	if count <= float64(o.Limit) {
+		info.Remaining = o.Limit - int(math.Ceil(count))
+		return info, true, nil
+	}
+
+	_, _ = o.Store.Incr(ctx, key+":"+strconv.FormatInt(idx, 10), -1, 2*o.Window)
+	// Con cur-1 peticiones ya contadas, la siguiente vuelve a sumar cur y
+	// cabe cuando el aporte de la ventana anterior baja a Limit-cur; si no
+	// alcanza, hay que esperar a la próxima ventana.
+	if room := float64(int64(o.Limit) - cur); prev > 0 && room > 0 {
+		at := time.Duration((1 - room/float64(prev)) * float64(o.Window))
+		info.RetryAfter = max(at-elapsed, time.Second)
+	}
+	return info, false, nil
+}
+
+func (o *RateLimitOptions) tokenBucket(r *http.Request, key string) (LimitInfo, bool, error) {
+	// Con Window < Limit la división trunca a cero; el bucket se recupera
+	// entonces a un token por nanosegundo.
+	interval := max(o.Window/time.Duration(o.Limit), time.Nanosecond)
+	ok, remaining, wait, err := o.Store.(store.TokenBucket).TakeToken(r.Context(), key, o.Burst, interval)
+	info := LimitInfo{
+		Limit:      o.Burst,
+		Remaining:  remaining,
+		Window:     o.Window,
+		Reset:      time.Duration(o.Burst-remaining) * interval,
+		RetryAfter: wait,
+	}
+	return info, ok, err
+}
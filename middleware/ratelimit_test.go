@@ -0,0 +1,150 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/profe-ajedrez/transwarp/store"
+)
+
+func TestSlidingWindow(t *testing.T) {
+	// base cae al inicio de una ventana de 10s.
+	base := time.Unix(1000, 0)
+	var now time.Time
+	s := store.NewMemory()
+	o := &RateLimitOptions{Limit: 4, Window: 10 * time.Second, Store: s, now: func() time.Time { return now }}
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	tests := []struct {
+		name       string
+		at         time.Duration // desde base
+		ok         bool
+		remaining  int
+		reset      time.Duration
+		retryAfter time.Duration
+	}{
+		{"1ª", 0, true, 3, 10 * time.Second, 0},
+		{"2ª", 0, true, 2, 10 * time.Second, 0},
+		{"3ª", 0, true, 1, 10 * time.Second, 0},
+		{"4ª", 0, true, 0, 10 * time.Second, 0},
+		{"excede sin ventana anterior", 0, false, 0, 10 * time.Second, 0},
+		// La ventana anterior aporta 4·0,75 = 3.
+		{"ventana siguiente", 12500 * time.Millisecond, true, 0, 7500 * time.Millisecond, 0},
+		// La próxima cabe cuando el aporte baje a 2: a los 5s de la ventana.
+		{"excede con ventana anterior", 12500 * time.Millisecond, false, 0, 7500 * time.Millisecond, 2500 * time.Millisecond},
+		{"cabe al bajar el aporte", 15 * time.Second, true, 0, 5 * time.Second, 0},
+		{"otra al 25%", 17500 * time.Millisecond, true, 0, 2500 * time.Millisecond, 0},
+		// Con 3 contadas solo cabe otra cuando la ventana anterior deje de
+		// aportar: se espera al Reset.
+		{"excede hasta la próxima ventana", 17500 * time.Millisecond, false, 0, 2500 * time.Millisecond, 0},
+	}
+	for _, tt := range tests {
+		now = base.Add(tt.at)
+		info, ok, err := o.slidingWindow(r, "k")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != tt.ok || info.Remaining != tt.remaining || info.Reset != tt.reset || info.RetryAfter != tt.retryAfter {
+			t.Errorf("%s: ok %v, Remaining %d, Reset %v, RetryAfter %v; se esperaba %v, %d, %v, %v",
+				tt.name, ok, info.Remaining, info.Reset, info.RetryAfter, tt.ok, tt.remaining, tt.reset, tt.retryAfter)
+		}
+	}
+
+	// Las rechazadas no quedan contadas.
+	for idx, want := range map[int64]string{100: "4", 101: "3"} {
+		if b, _ := s.Get(context.Background(), "k:"+strconv.FormatInt(idx, 10)); string(b) != want {
+			t.Errorf("contador de la ventana %d = %q, se esperaba %q", idx, b, want)
+		}
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	now := time.Unix(1000, 0)
+	h := RateLimit(RateLimitOptions{Limit: 2, Window: 10 * time.Second, now: func() time.Time { return now }})(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	tests := []struct {
+		name      string
+		ip        string
+		status    int
+		remaining string
+		retry     string
+	}{
+		{"primera", "10.0.0.1:1", http.StatusOK, "1", ""},
+		{"segunda", "10.0.0.1:2", http.StatusOK, "0", ""},
+		{"excede", "10.0.0.1:3", http.StatusTooManyRequests, "0", "10"},
+		{"otro cliente", "10.0.0.2:1", http.StatusOK, "1", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.ip
+			w := httptest.NewRecorder()
+			h.ServeHTTP(w, r)
+			if w.Code != tt.status {
+				t.Errorf("status = %d, se esperaba %d", w.Code, tt.status)
+			}
+			if got := w.Header().Get("RateLimit-Remaining"); got != tt.remaining {
+				t.Errorf("RateLimit-Remaining = %q, se esperaba %q", got, tt.remaining)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.retry {
+				t.Errorf("Retry-After = %q, se esperaba %q", got, tt.retry)
+			}
+			if got := w.Header().Get("RateLimit-Policy"); got != "2;w=10" {
+				t.Errorf("RateLimit-Policy = %q", got)
+			}
+		})
+	}
+}
+
+// bucket registra el interval con que RateLimit consume tokens.
+type bucket struct {
+	*store.Memory
+	interval time.Duration
+}
+
+func (b *bucket) TakeToken(ctx context.Context, key string, capacity int, interval time.Duration) (bool, int, time.Duration, error) {
+	b.interval = interval
+	return b.Memory.TakeToken(ctx, key, capacity, interval)
+}
+
+func TestRateLimitTokenBucket(t *testing.T) {
+	tests := []struct {
+		name     string
+		limit    int
+		window   time.Duration
+		burst    int
+		statuses []int
+		interval time.Duration
+		retry    string
+	}{
+		{"ráfaga y rechazo", 2, time.Hour, 0, []int{200, 200, 429}, 30 * time.Minute, "1800"},
+		{"burst mayor que limit", 1, time.Hour, 3, []int{200, 200, 200, 429}, time.Hour, "3600"},
+		{"ventana menor que limit", 1000, 100 * time.Nanosecond, 1, []int{200}, time.Nanosecond, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &bucket{Memory: store.NewMemory()}
+			h := RateLimit(RateLimitOptions{Limit: tt.limit, Window: tt.window, Burst: tt.burst, Algorithm: TokenBucket, Store: b})(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+			var w *httptest.ResponseRecorder
+			for i, want := range tt.statuses {
+				w = httptest.NewRecorder()
+				h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+				if w.Code != want {
+					t.Errorf("petición %d: status = %d, se esperaba %d", i, w.Code, want)
+				}
+			}
+			if b.interval != tt.interval {
+				t.Errorf("interval = %v, se esperaba %v", b.interval, tt.interval)
+			}
+			if got := w.Header().Get("Retry-After"); got != tt.retry {
+				t.Errorf("Retry-After = %q, se esperaba %q", got, tt.retry)
+			}
+		})
+	}
+}
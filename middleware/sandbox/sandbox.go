@@ -0,0 +1,131 @@
+// Package sandbox aísla a los consumidores de la API que prueban contra las
+// URLs de producción: las peticiones con un token de sandbox resuelven sus
+// dependencias (almacenamiento, clientes externos, ...) hacia
+// implementaciones de prueba en lugar de las reales.
+package sandbox
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// DefaultHeader es la cabecera que transporta el token de sandbox.
+const DefaultHeader = "X-Sandbox-Token"
+
+// Config configura Middleware.
+type Config struct {
+	// Header es la cabecera del token; por defecto DefaultHeader.
+	Header string
+	// BearerPrefix, si no está vacío, reconoce también tokens Bearer con
+	// ese prefijo (por ejemplo "sk_test_").
+	BearerPrefix string
+	// Resolve valida el token y retorna el tenant de sandbox al que
+	// pertenece. Es obligatorio.
+	Resolve func(ctx context.Context, token string) (tenant string, err error)
+}
+
+type ctxKey struct{}
+
+// Tenant retorna el tenant de sandbox de ctx, si la petición es de
+// sandbox.
+func Tenant(ctx context.Context) (string, bool) {
+	t, ok := ctx.Value(ctxKey{}).(string)
+	return t, ok
+}
+
+// WithTenant marca ctx como sandbox de tenant. Útil en tests y trabajos en
+// segundo plano que deben respetar el aislamiento.
+func WithTenant(ctx context.Context, tenant string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, tenant)
+}
+
+// Middleware marca como sandbox las peticiones que traen un token válido.
+// Un token inválido responde 401; nunca se degrada a producción. Las
+// respuestas de sandbox llevan "X-Sandbox: true" y no se cachean en
+// intermediarios compartidos.
+func Middleware(cfg Config) router.Middleware {
+	if cfg.Resolve == nil {
+		panic("sandbox: Config.Resolve es obligatorio")
+	}
+	if cfg.Header == "" {
+		cfg.Header = DefaultHeader
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token := cfg.token(r)
+			if token == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			tenant, err := cfg.Resolve(r.Context(), token)
+			if err != nil {
+				http.Error(w, "token de sandbox inválido", http.StatusUnauthorized)
+				return
+			}
+			h := w.Header()
+			h.Set("X-Sandbox", "true")
+			h.Set("Cache-Control", "private, no-store")
+			next.ServeHTTP(w, r.WithContext(WithTenant(r.Context(), tenant)))
+		})
+	}
+}
+
+func (c *Config) token(r *http.Request) string {
+	if t := r.Header.Get(c.Header); t != "" {
+		return t
+	}
+	if c.BearerPrefix != "" {
+		if t, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok && strings.HasPrefix(t, c.BearerPrefix) {
+			return t
+		}
+	}
+	return ""
+}
+
+// Provider resuelve una dependencia según el contexto: Live para
+// producción y la implementación de sandbox del tenant en caso contrario.
+type Provider[T any] struct {
+	// Live es la implementación de producción.
+	Live T
+	// Sandbox construye la implementación de prueba de un tenant. Se
+	// invoca una vez por tenant y el resultado se reutiliza.
+	Sandbox func(ctx context.Context, tenant string) (T, error)
+
+	mu    sync.Mutex
+	cache map[string]T
+}
+
+// Get retorna la implementación que corresponde a ctx.
+func (p *Provider[T]) Get(ctx context.Context) (T, error) {
+	tenant, ok := Tenant(ctx)
+	if !ok {
+		return p.Live, nil
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if v, ok := p.cache[tenant]; ok {
+		return v, nil
+	}
+	v, err := p.Sandbox(ctx, tenant)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	if p.cache == nil {
+		p.cache = make(map[string]T)
+	}
+	p.cache[tenant] = v
+	return v, nil
+}
+
+// Forget descarta la implementación cacheada de tenant, por ejemplo al
+// reiniciar sus datos de prueba.
+func (p *Provider[T]) Forget(tenant string) {
+	p.mu.Lock()
+	delete(p.cache, tenant)
+	p.mu.Unlock()
+}
@@ -0,0 +1,86 @@
+// Package scopes exige que el token de la petición (JWT u OAuth) tenga los
+// scopes requeridos por la ruta, respondiendo 403 con los que faltan.
+package scopes
+
+import (
+	"context"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/profe-ajedrez/transwarp/problem"
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// Extractor retorna los scopes concedidos a la petición. ok=false indica
+// que la petición no está autenticada.
+type Extractor func(r *http.Request) (granted []string, ok bool)
+
+type ctxKey struct{}
+
+// WithScopes guarda en ctx los scopes concedidos. El middleware de
+// autenticación lo invoca tras validar el token.
+func WithScopes(ctx context.Context, granted []string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, granted)
+}
+
+// FromContext es el Extractor que lee los scopes guardados con WithScopes.
+func FromContext(r *http.Request) ([]string, bool) {
+	granted, ok := r.Context().Value(ctxKey{}).([]string)
+	return granted, ok
+}
+
+// Parse separa el claim "scope" de OAuth 2.0, delimitado por espacios.
+func Parse(claim string) []string {
+	return strings.Fields(claim)
+}
+
+// Require exige todos los scopes indicados. Sin autenticación responde 401;
+// si faltan scopes responde 403 con un documento problem+json que los
+// enumera en "missing_scopes".
+func Require(extract Extractor, required ...string) router.Middleware {
+	return check(extract, required, false)
+}
+
+// RequireAny exige al menos uno de los scopes indicados.
+func RequireAny(extract Extractor, required ...string) router.Middleware {
+	return check(extract, required, true)
+}
+
+func check(extract Extractor, required []string, anyOf bool) router.Middleware {
+	if extract == nil {
+		extract = FromContext
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			granted, ok := extract(r)
+			if !ok {
+				w.Header().Set("WWW-Authenticate", `Bearer scope="`+strings.Join(required, " ")+`"`)
+				writeProblem(w, r, http.StatusUnauthorized, "Se requiere autenticación.", nil)
+				return
+			}
+
+			var missing []string
+			for _, s := range required {
+				if !slices.Contains(granted, s) {
+					missing = append(missing, s)
+				}
+			}
+			if len(missing) == 0 || (anyOf && len(missing) < len(required)) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Set("WWW-Authenticate", `Bearer error="insufficient_scope", scope="`+strings.Join(required, " ")+`"`)
+			writeProblem(w, r, http.StatusForbidden, "El token no tiene los scopes requeridos.", missing)
+		})
+	}
+}
+
+func writeProblem(w http.ResponseWriter, r *http.Request, status int, detail string, missing []string) {
+	p := problem.New(status, detail).ForRequest(r)
+	if len(missing) > 0 {
+		p.With("missing_scopes", missing)
+	}
+	_ = problem.Write(w, p)
+}
@@ -0,0 +1,84 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/profe-ajedrez/transwarp/problem"
+)
+
+// LimitInfo describe el estado de un límite (rate limit, cuota o descarte
+// de carga) que se comunica al cliente.
+type LimitInfo struct {
+	// Limit es la cantidad de peticiones permitidas por ventana.
+	Limit int
+	// Remaining es cuántas quedan en la ventana actual.
+	Remaining int
+	// Window es la duración de la ventana, usada en RateLimit-Policy.
+	Window time.Duration
+	// Reset es el tiempo hasta que la ventana se renueva.
+	Reset time.Duration
+	// RetryAfter es cuánto debe esperar el cliente; si es cero se usa Reset.
+	RetryAfter time.Duration
+	// Detail es un texto opcional para el cuerpo de la respuesta.
+	Detail string
+}
+
+// SetRateLimitHeaders escribe los headers RateLimit-Limit,
+// RateLimit-Remaining, RateLimit-Reset y, si se conoce la ventana,
+// RateLimit-Policy.
+func SetRateLimitHeaders(h http.Header, info LimitInfo) {
+	if info.Limit <= 0 {
+		return
+	}
+	h.Set("RateLimit-Limit", strconv.Itoa(info.Limit))
+	h.Set("RateLimit-Remaining", strconv.Itoa(max(info.Remaining, 0)))
+	h.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(info.Reset)))
+	if info.Window > 0 {
+		h.Set("RateLimit-Policy", strconv.Itoa(info.Limit)+";w="+strconv.Itoa(ceilSeconds(info.Window)))
+	}
+}
+
+// TooManyRequestsHandler responde las peticiones rechazadas por los
+// middlewares de límite. Puede reemplazarse al iniciar la aplicación para
+// cambiar el formato de todas las respuestas 429 a la vez.
+var TooManyRequestsHandler = DefaultTooManyRequests
+
+// TooManyRequests rechaza r con TooManyRequestsHandler. Es el punto único
+// que usan los middlewares de rate limit, cuota y descarte de carga.
+func TooManyRequests(w http.ResponseWriter, r *http.Request, info LimitInfo) {
+	TooManyRequestsHandler(w, r, info)
+}
+
+// DefaultTooManyRequests responde 429 con los headers RateLimit-*,
+// Retry-After y un cuerpo application/problem+json que incluye el
+// identificador de traza de la petición, si existe.
+func DefaultTooManyRequests(w http.ResponseWriter, r *http.Request, info LimitInfo) {
+	retry := info.RetryAfter
+	if retry <= 0 {
+		retry = info.Reset
+	}
+
+	h := w.Header()
+	SetRateLimitHeaders(h, info)
+	h.Set("Retry-After", strconv.Itoa(max(ceilSeconds(retry), 1)))
+	h.Set("Cache-Control", "no-store")
+
+	detail := info.Detail
+	if detail == "" {
+		detail = "Se excedió el límite de peticiones; reintente más tarde."
+	}
+	p := problem.New(http.StatusTooManyRequests, detail).
+		With("retry_after", max(ceilSeconds(retry), 1)).
+		ForRequest(r)
+	_ = problem.Write(w, p)
+}
+
+func ceilSeconds(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,102 @@
+// Package middleware reúne middlewares listos para usar con cualquier
+// driver de transwarp, construidos sobre router.Middleware.
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// TransformRules describe las reescrituras que Transform aplica a la
+// petición antes de entregarla al siguiente handler. Las reglas se aplican
+// en el orden en que aparecen los campos.
+type TransformRules struct {
+	// StripPrefix elimina el prefijo indicado de la ruta.
+	StripPrefix string
+	// RemoveHeaders elimina headers de la petición.
+	RemoveHeaders []string
+	// RenameHeaders renombra headers (origen -> destino), conservando sus valores.
+	RenameHeaders map[string]string
+	// SetHeaders fija headers, reemplazando valores previos.
+	SetHeaders map[string]string
+	// AddHeaders agrega valores a headers sin reemplazar los existentes.
+	AddHeaders map[string]string
+	// DefaultQuery agrega parámetros de query que la petición no trae.
+	DefaultQuery map[string]string
+}
+
+// Transform retorna un middleware que reescribe headers, query y ruta de la
+// petición según rules. Resulta útil al exponer backends legados mediante
+// el proxy, configurándolo por grupo con Group(...).Use(Transform(...)).
+func Transform(rules TransformRules) router.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			r = r.Clone(r.Context())
+
+			if rules.StripPrefix != "" {
+				if !stripPrefix(r, rules.StripPrefix) {
+					http.NotFound(w, r)
+					return
+				}
+			}
+
+			for _, name := range rules.RemoveHeaders {
+				r.Header.Del(name)
+			}
+			for from, to := range rules.RenameHeaders {
+				if values, ok := r.Header[http.CanonicalHeaderKey(from)]; ok {
+					r.Header.Del(from)
+					r.Header[http.CanonicalHeaderKey(to)] = values
+				}
+			}
+			for name, value := range rules.SetHeaders {
+				r.Header.Set(name, value)
+			}
+			for name, value := range rules.AddHeaders {
+				r.Header.Add(name, value)
+			}
+
+			if len(rules.DefaultQuery) > 0 {
+				q := r.URL.Query()
+				changed := false
+				for key, value := range rules.DefaultQuery {
+					if !q.Has(key) {
+						q.Set(key, value)
+						changed = true
+					}
+				}
+				if changed {
+					r.URL.RawQuery = q.Encode()
+					r.RequestURI = r.URL.RequestURI()
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// stripPrefix replica la semántica de http.StripPrefix sobre r. Retorna
+// false si la ruta no tiene el prefijo.
+func stripPrefix(r *http.Request, prefix string) bool {
+	p, ok := strings.CutPrefix(r.URL.Path, prefix)
+	if !ok {
+		return false
+	}
+	rp, ok := strings.CutPrefix(r.URL.RawPath, prefix)
+	if r.URL.RawPath != "" && !ok {
+		return false
+	}
+	if !strings.HasPrefix(p, "/") {
+		p = "/" + p
+	}
+	if rp != "" && !strings.HasPrefix(rp, "/") {
+		rp = "/" + rp
+	}
+	r.URL.Path = p
+	r.URL.RawPath = rp
+	r.RequestURI = r.URL.RequestURI()
+	return true
+}
@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// AddVary agrega names al header Vary sin duplicar entradas ya presentes
+// (la comparación no distingue mayúsculas). Los middlewares que deciden la
+// respuesta según Accept, Accept-Encoding, Origin u otro header deben
+// invocarla para que cachés y CDNs no mezclen variantes.
+func AddVary(h http.Header, names ...string) {
+	existing := map[string]bool{}
+	for _, v := range h.Values("Vary") {
+		for field := range strings.SplitSeq(v, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				existing[strings.ToLower(field)] = true
+			}
+		}
+	}
+	if existing["*"] {
+		return
+	}
+
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" || existing[strings.ToLower(name)] {
+			continue
+		}
+		if name == "*" {
+			h.Set("Vary", "*")
+			return
+		}
+		existing[strings.ToLower(name)] = true
+		h.Add("Vary", http.CanonicalHeaderKey(name))
+	}
+}
+
+// Vary retorna un middleware que declara names en el header Vary de cada
+// respuesta, para rutas cuyo handler depende de esos headers.
+func Vary(names ...string) router.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			AddVary(w.Header(), names...)
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package middleware
+
+import "net/http"
+
+// headerHookWriter invoca beforeHeader una única vez, justo antes de que
+// se envíen el status y los headers, ya sea por WriteHeader o por el
+// primer Write. Permite a los middlewares ajustar headers de respuesta
+// después de que el handler los haya definido.
+type headerHookWriter struct {
+	http.ResponseWriter
+	beforeHeader func(status int)
+	wroteHeader  bool
+}
+
+func (w *headerHookWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.beforeHeader(status)
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *headerHookWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush permite hacer streaming a través del wrapper.
+func (w *headerHookWriter) Flush() {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap expone el writer original a http.ResponseController.
+func (w *headerHookWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,109 @@
+// Package mock sirve respuestas de ejemplo en lugar de ejecutar los
+// handlers, para que el frontend pueda desarrollar contra la tabla de rutas
+// real antes de que existan los backends.
+package mock
+
+import (
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/profe-ajedrez/transwarp/render"
+)
+
+// Env es la variable de entorno que activa el modo mock para todas las
+// rutas marcadas.
+const Env = "TRANSWARP_MOCK"
+
+// DefaultHeader es la cabecera con la que un cliente pide la respuesta de
+// ejemplo ("1") o la real ("0") de forma puntual.
+const DefaultHeader = "X-Mock"
+
+// Response es una respuesta de ejemplo.
+type Response struct {
+	// Status por defecto es 200.
+	Status int
+	Header http.Header
+	// Body se escribe tal cual si es []byte o string; cualquier otro valor
+	// se serializa como JSON.
+	Body any
+}
+
+// Mode decide cuándo se sirven las respuestas de ejemplo.
+type Mode struct {
+	// Enabled activa el modo mock para todas las peticiones.
+	Enabled bool
+	// AllowHeader permite que cada petición elija con Header; no debería
+	// activarse en producción.
+	AllowHeader bool
+	// Header es la cabecera de control; por defecto DefaultHeader.
+	Header string
+}
+
+// FromEnv crea un Mode activo si Env tiene un valor verdadero, aceptando
+// además la cabecera de control.
+func FromEnv() *Mode {
+	on, _ := strconv.ParseBool(os.Getenv(Env))
+	return &Mode{Enabled: on, AllowHeader: on}
+}
+
+func (m *Mode) active(r *http.Request) bool {
+	if m == nil {
+		return false
+	}
+	if m.AllowHeader {
+		name := m.Header
+		if name == "" {
+			name = DefaultHeader
+		}
+		if v := r.Header.Get(name); v != "" {
+			on, err := strconv.ParseBool(v)
+			return err == nil && on
+		}
+	}
+	return m.Enabled
+}
+
+// Wrap marca un handler con su respuesta de ejemplo. Si h es nil (backend
+// aún no implementado) y el modo no está activo, responde 501.
+func (m *Mode) Wrap(example Response, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !m.active(r) {
+			if h == nil {
+				http.Error(w, http.StatusText(http.StatusNotImplemented), http.StatusNotImplemented)
+				return
+			}
+			h(w, r)
+			return
+		}
+		Serve(w, example)
+	}
+}
+
+// Serve escribe example en w.
+func Serve(w http.ResponseWriter, example Response) {
+	status := example.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	for k, vs := range example.Header {
+		w.Header()[k] = vs
+	}
+	w.Header().Set("X-Mock-Response", "true")
+	ct := w.Header().Get("Content-Type")
+	switch b := example.Body.(type) {
+	case nil:
+		w.WriteHeader(status)
+	case []byte:
+		_ = render.Blob(w, status, ct, b)
+	case string:
+		if ct == "" {
+			ct = "text/plain; charset=utf-8"
+		}
+		_ = render.Blob(w, status, ct, []byte(b))
+	default:
+		if err := render.JSON(w, status, b); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}
+}
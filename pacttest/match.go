@@ -0,0 +1,303 @@
+package pacttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// matcher es una regla de matchingRules. Se admiten type (con min y max
+// para arreglos), regex, equality, include, integer, decimal, number y
+// boolean; cualquier otra hace fallar la interacción en lugar de producir
+// diferencias falsas.
+type matcher struct {
+	Match string `json:"match"`
+	Regex string `json:"regex"`
+	Value string `json:"value"`
+	Min   *int   `json:"min"`
+	Max   *int   `json:"max"`
+
+	re *regexp.Regexp
+}
+
+// ruleSet son los matchers de una ruta; combine es "AND" u "OR".
+type ruleSet struct {
+	path     []string
+	matchers []matcher
+	or       bool
+}
+
+// rules son las matchingRules de una respuesta, normalizadas desde v2
+// ("$.body.x", "$.headers.X") o v3 ({"body": {"$.x": ...}}).
+type rules struct {
+	body   []ruleSet
+	header map[string]ruleSet
+}
+
+func parseRules(raw json.RawMessage) (*rules, error) {
+	rs := &rules{header: map[string]ruleSet{}}
+	if len(raw) == 0 || string(raw) == "null" {
+		return rs, nil
+	}
+	var top map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &top); err != nil {
+		return nil, fmt.Errorf("matchingRules inválidas: %w", err)
+	}
+	for key, v := range top {
+		var err error
+		switch {
+		case key == "body" || key == "header":
+			// v3: categoría → ruta → {matchers, combine}.
+			var paths map[string]struct {
+				Matchers []matcher `json:"matchers"`
+				Combine  string    `json:"combine"`
+			}
+			if err = json.Unmarshal(v, &paths); err != nil {
+				return nil, fmt.Errorf("matchingRules %s inválidas: %w", key, err)
+			}
+			for p, m := range paths {
+				if key == "header" {
+					err = rs.addHeader(p, m.Matchers, m.Combine)
+				} else {
+					err = rs.addBody(strings.TrimPrefix(p, "$"), m.Matchers, m.Combine)
+				}
+				if err != nil {
+					return nil, err
+				}
+			}
+		case strings.HasPrefix(key, "$.body"):
+			var m matcher
+			if err = json.Unmarshal(v, &m); err == nil {
+				err = rs.addBody(strings.TrimPrefix(key, "$.body"), []matcher{m}, "")
+			}
+		case strings.HasPrefix(key, "$.headers.") || strings.HasPrefix(key, "$.header."):
+			var m matcher
+			if err = json.Unmarshal(v, &m); err == nil {
+				_, name, _ := strings.Cut(key[2:], ".")
+				err = rs.addHeader(name, []matcher{m}, "")
+			}
+		}
+		// Las reglas de path, query o status no aplican a la respuesta.
+		if err != nil {
+			return nil, fmt.Errorf("matchingRules %s: %w", key, err)
+		}
+	}
+	return rs, nil
+}
+
+func (rs *rules) addBody(path string, ms []matcher, combine string) error {
+	tokens, err := parsePath(path)
+	if err != nil {
+		return err
+	}
+	if err := compileMatchers(ms); err != nil {
+		return err
+	}
+	rs.body = append(rs.body, ruleSet{path: tokens, matchers: ms, or: strings.EqualFold(combine, "OR")})
+	return nil
+}
+
+func (rs *rules) addHeader(name string, ms []matcher, combine string) error {
+	if err := compileMatchers(ms); err != nil {
+		return err
+	}
+	rs.header[strings.ToLower(name)] = ruleSet{matchers: ms, or: strings.EqualFold(combine, "OR")}
+	return nil
+}
+
+func compileMatchers(ms []matcher) error {
+	for i := range ms {
+		m := &ms[i]
+		if m.Match == "" && m.Regex != "" {
+			m.Match = "regex"
+		}
+		if m.Match == "" && (m.Min != nil || m.Max != nil) {
+			m.Match = "type"
+		}
+		switch m.Match {
+		case "regex":
+			re, err := regexp.Compile(`^(?:` + m.Regex + `)$`)
+			if err != nil {
+				return fmt.Errorf("regex %q inválida: %w", m.Regex, err)
+			}
+			m.re = re
+		case "type", "equality", "include", "integer", "decimal", "number", "boolean":
+		default:
+			return fmt.Errorf("matcher %q no soportado", m.Match)
+		}
+	}
+	return nil
+}
+
+// parsePath separa una ruta JSONPath de Pact (".a.b[0]", "['a b'][*]", ".*")
+// en segmentos; los índices y comodines conservan sus corchetes.
+func parsePath(p string) ([]string, error) {
+	var tokens []string
+	for p != "" {
+		switch {
+		case strings.HasPrefix(p, "['"):
+			end := strings.Index(p, "']")
+			if end < 0 {
+				return nil, fmt.Errorf("ruta inválida %q", p)
+			}
+			tokens = append(tokens, p[2:end])
+			p = p[end+2:]
+		case p[0] == '[':
+			end := strings.IndexByte(p, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("ruta inválida %q", p)
+			}
+			tokens = append(tokens, p[:end+1])
+			p = p[end+1:]
+		case p[0] == '.':
+			end := strings.IndexAny(p[1:], ".[")
+			if end < 0 {
+				end = len(p) - 1
+			}
+			tokens = append(tokens, p[1:end+1])
+			p = p[end+1:]
+		default:
+			return nil, fmt.Errorf("ruta inválida %q", p)
+		}
+	}
+	return tokens, nil
+}
+
+// lookup retorna la regla más específica para path: la de más segmentos
+// exactos entre las que coinciden completas.
+func (rs *rules) lookup(path []string) *ruleSet {
+	var best *ruleSet
+	bestScore := -1
+	for i := range rs.body {
+		r := &rs.body[i]
+		if len(r.path) != len(path) {
+			continue
+		}
+		score, ok := 0, true
+		for j, t := range r.path {
+			if t == path[j] {
+				score++
+				continue
+			}
+			isIndex := strings.HasPrefix(path[j], "[")
+			if (t == "*" && !isIndex) || (t == "[*]" && isIndex) {
+				continue
+			}
+			ok = false
+			break
+		}
+		if ok && score > bestScore {
+			best, bestScore = r, score
+		}
+	}
+	return best
+}
+
+// formatPath arma la ruta legible de los mensajes, p. ej. "$.items[0].id".
+func formatPath(path []string) string {
+	var b strings.Builder
+	b.WriteByte('$')
+	for _, t := range path {
+		if !strings.HasPrefix(t, "[") {
+			b.WriteByte('.')
+		}
+		b.WriteString(t)
+	}
+	return b.String()
+}
+
+// check aplica los matchers de r a got, con expected como ejemplo.
+// Reporta si el valor cumple y si sus hijos deben compararse por tipo.
+func (r *ruleSet) check(expected, got any) (problems []string, byType bool) {
+	for _, m := range r.matchers {
+		p := m.check(expected, got)
+		if m.Match == "type" {
+			byType = true
+		}
+		if r.or && p == "" {
+			return nil, byType
+		}
+		if p != "" {
+			problems = append(problems, p)
+		}
+	}
+	if r.or && len(problems) < len(r.matchers) {
+		return nil, byType
+	}
+	return problems, byType
+}
+
+func (m matcher) check(expected, got any) string {
+	switch m.Match {
+	case "type":
+		if kind(expected) != kind(got) {
+			return fmt.Sprintf("se esperaba un %s, se obtuvo %s", kind(expected), kind(got))
+		}
+		if arr, ok := got.([]any); ok {
+			if m.Min != nil && len(arr) < *m.Min {
+				return fmt.Sprintf("se esperaban al menos %d elementos, se obtuvieron %d", *m.Min, len(arr))
+			}
+			if m.Max != nil && len(arr) > *m.Max {
+				return fmt.Sprintf("se esperaban a lo sumo %d elementos, se obtuvieron %d", *m.Max, len(arr))
+			}
+		}
+	case "regex":
+		s, ok := scalar(got)
+		if !ok || !m.re.MatchString(s) {
+			return fmt.Sprintf("%v no coincide con /%s/", got, m.Regex)
+		}
+	case "equality":
+		if !reflect.DeepEqual(expected, got) {
+			return fmt.Sprintf("se esperaba %v, se obtuvo %v", expected, got)
+		}
+	case "include":
+		if s, ok := scalar(got); !ok || !strings.Contains(s, m.Value) {
+			return fmt.Sprintf("%v no incluye %q", got, m.Value)
+		}
+	case "integer":
+		if f, ok := got.(float64); !ok || f != math.Trunc(f) {
+			return fmt.Sprintf("se esperaba un entero, se obtuvo %v", got)
+		}
+	case "decimal", "number":
+		if _, ok := got.(float64); !ok {
+			return fmt.Sprintf("se esperaba un número, se obtuvo %v", got)
+		}
+	case "boolean":
+		if _, ok := got.(bool); !ok {
+			return fmt.Sprintf("se esperaba un booleano, se obtuvo %v", got)
+		}
+	}
+	return ""
+}
+
+func kind(v any) string {
+	switch v.(type) {
+	case map[string]any:
+		return "objeto"
+	case []any:
+		return "arreglo"
+	case string:
+		return "string"
+	case float64:
+		return "número"
+	case bool:
+		return "booleano"
+	}
+	return "null"
+}
+
+func scalar(v any) (string, bool) {
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
@@ -0,0 +1,135 @@
+// Package pacttest verifica contratos Pact del lado del proveedor contra un
+// http.Handler en proceso, sin levantar servidores. Cada interacción del
+// archivo se reproduce mediante router.DispatchRequest y su respuesta se
+// compara con la esperada.
+//
+// Se admiten archivos de especificación v2 y v3. Sin matchingRules, los
+// objetos JSON esperados se comparan como subconjunto de la respuesta (se
+// toleran campos extra) y los arreglos y escalares por igualdad. Las
+// matchingRules del cuerpo y de los headers de la respuesta se evalúan;
+// un matcher no soportado hace fallar la interacción con un error claro.
+package pacttest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// Pact es el contenido de un archivo Pact.
+type Pact struct {
+	Consumer     Participant   `json:"consumer"`
+	Provider     Participant   `json:"provider"`
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Participant identifica al consumidor o al proveedor.
+type Participant struct {
+	Name string `json:"name"`
+}
+
+// Interaction es un par petición/respuesta esperado.
+type Interaction struct {
+	Description    string          `json:"description"`
+	ProviderState  string          `json:"providerState,omitempty"`
+	ProviderStates []ProviderState `json:"providerStates,omitempty"`
+	Request        Request         `json:"request"`
+	Response       Response        `json:"response"`
+}
+
+// ProviderState es un estado que el proveedor debe preparar antes de la
+// interacción.
+type ProviderState struct {
+	Name   string         `json:"name"`
+	Params map[string]any `json:"params,omitempty"`
+}
+
+// States retorna los estados requeridos, normalizando el formato v2
+// (providerState) al de v3 (providerStates).
+func (i Interaction) States() []ProviderState {
+	if len(i.ProviderStates) > 0 {
+		return i.ProviderStates
+	}
+	if i.ProviderState != "" {
+		return []ProviderState{{Name: i.ProviderState}}
+	}
+	return nil
+}
+
+// Request es la petición de una interacción.
+type Request struct {
+	Method  string          `json:"method"`
+	Path    string          `json:"path"`
+	Query   Query           `json:"query,omitempty"`
+	Headers Headers         `json:"headers,omitempty"`
+	Body    json.RawMessage `json:"body,omitempty"`
+}
+
+// Response es la respuesta esperada de una interacción.
+type Response struct {
+	Status        int             `json:"status"`
+	Headers       Headers         `json:"headers,omitempty"`
+	Body          json.RawMessage `json:"body,omitempty"`
+	MatchingRules json.RawMessage `json:"matchingRules,omitempty"`
+}
+
+// Query acepta tanto el formato v2 (string) como el v3 (objeto de listas).
+type Query struct {
+	url.Values
+}
+
+// UnmarshalJSON implementa json.Unmarshaler.
+func (q *Query) UnmarshalJSON(data []byte) error {
+	var raw string
+	if err := json.Unmarshal(data, &raw); err == nil {
+		v, err := url.ParseQuery(raw)
+		q.Values = v
+		return err
+	}
+	var multi map[string][]string
+	if err := json.Unmarshal(data, &multi); err != nil {
+		return fmt.Errorf("pacttest: query inválida: %w", err)
+	}
+	q.Values = url.Values(multi)
+	return nil
+}
+
+// Headers acepta valores como string o como lista de strings (v4).
+type Headers map[string]string
+
+// UnmarshalJSON implementa json.Unmarshaler.
+func (h *Headers) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*h = make(Headers, len(raw))
+	for k, v := range raw {
+		var s string
+		if err := json.Unmarshal(v, &s); err == nil {
+			(*h)[k] = s
+			continue
+		}
+		var list []string
+		if err := json.Unmarshal(v, &list); err != nil {
+			return fmt.Errorf("pacttest: header %s inválido: %w", k, err)
+		}
+		(*h)[k] = strings.Join(list, ", ")
+	}
+	return nil
+}
+
+// Load lee y decodifica un archivo Pact.
+func Load(path string) (*Pact, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var p Pact
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("pacttest: %s: %w", path, err)
+	}
+	return &p, nil
+}
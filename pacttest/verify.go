@@ -0,0 +1,267 @@
+package pacttest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// StateFunc prepara un estado del proveedor. La función de limpieza que
+// retorna, si no es nil, se ejecuta al terminar la interacción.
+type StateFunc func(ctx context.Context, params map[string]any) (teardown func(), err error)
+
+// Verifier reproduce interacciones Pact contra Handler.
+type Verifier struct {
+	Handler http.Handler
+	// States asocia cada nombre de estado con la función que lo prepara.
+	States map[string]StateFunc
+	// BeforeRequest permite ajustar cada petición, por ejemplo para
+	// agregar credenciales que el consumidor no registra en el contrato.
+	BeforeRequest func(*http.Request)
+}
+
+// Verify es un atajo para verificar el archivo en path contra h.
+func Verify(t *testing.T, h http.Handler, path string, states map[string]StateFunc) {
+	t.Helper()
+	(&Verifier{Handler: h, States: states}).Verify(t, path)
+}
+
+// Verify carga el archivo en path y ejecuta cada interacción como un
+// subtest nombrado con su descripción.
+func (v *Verifier) Verify(t *testing.T, path string) {
+	t.Helper()
+	pact, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, in := range pact.Interactions {
+		t.Run(in.Description, func(t *testing.T) {
+			for _, problem := range v.Check(t.Context(), in) {
+				t.Error(problem)
+			}
+		})
+	}
+}
+
+// Check reproduce una interacción y retorna las diferencias encontradas.
+// Una lista vacía significa que la respuesta cumple el contrato.
+func (v *Verifier) Check(ctx context.Context, in Interaction) []string {
+	for _, st := range in.States() {
+		fn, ok := v.States[st.Name]
+		if !ok {
+			return []string{fmt.Sprintf("estado del proveedor sin handler: %q", st.Name)}
+		}
+		teardown, err := fn(ctx, st.Params)
+		if err != nil {
+			return []string{fmt.Sprintf("estado %q: %v", st.Name, err)}
+		}
+		if teardown != nil {
+			defer teardown()
+		}
+	}
+
+	req, err := buildRequest(ctx, in.Request)
+	if err != nil {
+		return []string{err.Error()}
+	}
+	if v.BeforeRequest != nil {
+		v.BeforeRequest(req)
+	}
+
+	resp := router.DispatchRequest(v.Handler, req)
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	return compare(in.Response, resp, body)
+}
+
+func buildRequest(ctx context.Context, r Request) (*http.Request, error) {
+	target := r.Path
+	if len(r.Query.Values) > 0 {
+		target += "?" + r.Query.Encode()
+	}
+
+	var body io.Reader
+	if len(r.Body) > 0 && string(r.Body) != "null" {
+		body = bytes.NewReader(rawBody(r.Body, r.Headers))
+	}
+
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(r.Method), target, body)
+	if err != nil {
+		return nil, fmt.Errorf("pacttest: petición inválida: %w", err)
+	}
+	for k, v := range r.Headers {
+		req.Header.Set(k, v)
+	}
+	return req, nil
+}
+
+// rawBody retorna el cuerpo tal como debe enviarse: los cuerpos JSON se
+// envían sin cambios y los strings se envían sin comillas cuando el
+// Content-Type no es JSON.
+func rawBody(body json.RawMessage, headers Headers) []byte {
+	if isJSON(headerValue(headers, "Content-Type")) {
+		return body
+	}
+	var s string
+	if err := json.Unmarshal(body, &s); err == nil {
+		return []byte(s)
+	}
+	return body
+}
+
+func compare(want Response, resp *http.Response, body []byte) []string {
+	rs, err := parseRules(want.MatchingRules)
+	if err != nil {
+		return []string{"pacttest: " + err.Error()}
+	}
+
+	var problems []string
+	if want.Status != 0 && want.Status != resp.StatusCode {
+		problems = append(problems, fmt.Sprintf("status: se esperaba %d, se obtuvo %d", want.Status, resp.StatusCode))
+	}
+
+	for name, r := range rs.header {
+		if p, _ := r.check(headerValue(want.Headers, name), resp.Header.Get(name)); len(p) > 0 {
+			problems = append(problems, fmt.Sprintf("header %s: %s", http.CanonicalHeaderKey(name), strings.Join(p, "; ")))
+		}
+	}
+	for k, expected := range want.Headers {
+		got := resp.Header.Get(k)
+		if _, ok := rs.header[strings.ToLower(k)]; ok {
+			continue
+		}
+		if strings.EqualFold(k, "Content-Type") {
+			if mediaType(got) != mediaType(expected) {
+				problems = append(problems, fmt.Sprintf("header %s: se esperaba %q, se obtuvo %q", k, expected, got))
+			}
+			continue
+		}
+		if got != expected {
+			problems = append(problems, fmt.Sprintf("header %s: se esperaba %q, se obtuvo %q", k, expected, got))
+		}
+	}
+
+	if len(want.Body) == 0 || string(want.Body) == "null" {
+		return problems
+	}
+
+	var expected any
+	if err := json.Unmarshal(want.Body, &expected); err != nil {
+		return append(problems, fmt.Sprintf("cuerpo esperado inválido: %v", err))
+	}
+
+	if s, ok := expected.(string); ok && !isJSON(resp.Header.Get("Content-Type")) {
+		if s != string(body) {
+			problems = append(problems, fmt.Sprintf("cuerpo: se esperaba %q, se obtuvo %q", s, body))
+		}
+		return problems
+	}
+
+	var got any
+	if err := json.Unmarshal(body, &got); err != nil {
+		return append(problems, fmt.Sprintf("cuerpo no es JSON: %q", body))
+	}
+	return append(problems, diff(nil, expected, got, rs, false)...)
+}
+
+// diff compara expected contra got: los objetos se comparan como
+// subconjunto y el resto por igualdad, salvo que una matchingRule indique
+// otra cosa. Un matcher type se propaga a los hijos: desde ahí se compara
+// solo el tipo y cada elemento de un arreglo contra el primero esperado.
+func diff(path []string, expected, got any, rs *rules, byType bool) []string {
+	at := formatPath(path)
+	if r := rs.lookup(path); r != nil {
+		problems, t := r.check(expected, got)
+		for i, p := range problems {
+			problems[i] = at + ": " + p
+		}
+		if len(problems) > 0 || !t {
+			return problems
+		}
+		byType = true
+	}
+
+	switch e := expected.(type) {
+	case map[string]any:
+		g, ok := got.(map[string]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: se esperaba un objeto, se obtuvo %T", at, got)}
+		}
+		var problems []string
+		for k, ev := range e {
+			gv, ok := g[k]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("%s.%s: falta el campo", at, k))
+				continue
+			}
+			problems = append(problems, diff(append(slices.Clip(path), k), ev, gv, rs, byType)...)
+		}
+		return problems
+	case []any:
+		g, ok := got.([]any)
+		if !ok {
+			return []string{fmt.Sprintf("%s: se esperaba un arreglo, se obtuvo %T", at, got)}
+		}
+		if byType {
+			if len(e) == 0 {
+				return nil
+			}
+		} else if len(e) != len(g) {
+			return []string{fmt.Sprintf("%s: se esperaban %d elementos, se obtuvieron %d", at, len(e), len(g))}
+		}
+		var problems []string
+		for i := range g {
+			ev := e[0]
+			if !byType {
+				ev = e[i]
+			}
+			problems = append(problems, diff(append(slices.Clip(path), "["+strconv.Itoa(i)+"]"), ev, g[i], rs, byType)...)
+		}
+		return problems
+	default:
+		if byType {
+			if kind(expected) != kind(got) {
+				return []string{fmt.Sprintf("%s: se esperaba un %s, se obtuvo %s", at, kind(expected), kind(got))}
+			}
+			return nil
+		}
+		if !reflect.DeepEqual(expected, got) {
+			return []string{fmt.Sprintf("%s: se esperaba %v, se obtuvo %v", at, expected, got)}
+		}
+		return nil
+	}
+}
+
+func headerValue(h Headers, name string) string {
+	for k, v := range h {
+		if strings.EqualFold(k, name) {
+			return v
+		}
+	}
+	return ""
+}
+
+func mediaType(v string) string {
+	mt, _, err := mime.ParseMediaType(v)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(v))
+	}
+	return mt
+}
+
+func isJSON(contentType string) bool {
+	mt := mediaType(contentType)
+	return mt == "application/json" || strings.HasSuffix(mt, "+json")
+}
@@ -0,0 +1,132 @@
+package pacttest
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// serve responde siempre con body y los headers dados.
+func serve(body string, headers map[string]string) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		for k, v := range headers {
+			w.Header().Set(k, v)
+		}
+		io.WriteString(w, body)
+	})
+}
+
+func check(t *testing.T, h http.Handler, response string) []string {
+	t.Helper()
+	var in Interaction
+	if err := json.Unmarshal([]byte(`{"request":{"method":"GET","path":"/x"},"response":`+response+`}`), &in); err != nil {
+		t.Fatal(err)
+	}
+	return (&Verifier{Handler: h}).Check(context.Background(), in)
+}
+
+func TestCheckBody(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string // respuesta esperada de la interacción
+		body     string // cuerpo que entrega el proveedor
+		want     []string
+	}{
+		{"subconjunto tolera campos extra", `{"body":{"id":1}}`, `{"id":1,"extra":true}`, nil},
+		{"falta un campo", `{"body":{"id":1,"name":"a"}}`, `{"id":1}`, []string{"$.name: falta el campo"}},
+		{"valor distinto", `{"body":{"user":{"id":1}}}`, `{"user":{"id":2}}`, []string{"$.user.id: se esperaba 1, se obtuvo 2"}},
+		{"arreglo por igualdad", `{"body":{"ids":[1,2]}}`, `{"ids":[1,2,3]}`, []string{"$.ids: se esperaban 2 elementos, se obtuvieron 3"}},
+
+		// Regresión: antes se ignoraban las matchingRules y estas
+		// respuestas fallaban por igualdad.
+		{"type v2", `{"body":{"id":1},"matchingRules":{"$.body.id":{"match":"type"}}}`, `{"id":99}`, nil},
+		{"type v3", `{"body":{"id":1},"matchingRules":{"body":{"$.id":{"matchers":[{"match":"type"}]}}}}`, `{"id":99}`, nil},
+		{"type con otro tipo", `{"body":{"id":1},"matchingRules":{"$.body.id":{"match":"type"}}}`, `{"id":"1"}`, []string{"$.id: se esperaba un número, se obtuvo string"}},
+		{"type se propaga a los hijos", `{"body":{"u":{"id":1,"tags":["a"]}},"matchingRules":{"$.body.u":{"match":"type"}}}`, `{"u":{"id":7,"tags":["x","y"]}}`, nil},
+		{"regex", `{"body":{"date":"2024-01-01"},"matchingRules":{"$.body.date":{"match":"regex","regex":"\\d{4}-\\d{2}-\\d{2}"}}}`, `{"date":"2026-10-15"}`, nil},
+		{"regex completa", `{"body":{"date":"2024-01-01"},"matchingRules":{"$.body.date":{"regex":"\\d{4}"}}}`, `{"date":"2026-10-15"}`, []string{`$.date: 2026-10-15 no coincide con /\d{4}/`}},
+
+		// Cada elemento se compara contra el primero esperado.
+		{"cada elemento como el primero", `{"body":{"items":[{"id":1},{"name":"x"}]},"matchingRules":{"$.body.items":{"match":"type"}}}`, `{"items":[{"id":5},{"id":6}]}`, nil},
+		{"elemento distinto del primero", `{"body":{"items":[{"id":1}]},"matchingRules":{"$.body.items":{"match":"type"}}}`, `{"items":[{"id":5},{"name":"x"}]}`, []string{"$.items[1].id: falta el campo"}},
+		{"min", `{"body":{"items":[1]},"matchingRules":{"$.body.items":{"min":2}}}`, `{"items":[1]}`, []string{"$.items: se esperaban al menos 2 elementos, se obtuvieron 1"}},
+		{"max", `{"body":{"items":[1]},"matchingRules":{"$.body.items":{"match":"type","max":2}}}`, `{"items":[1,2,3]}`, []string{"$.items: se esperaban a lo sumo 2 elementos, se obtuvieron 3"}},
+		{"min y max cumplidos", `{"body":{"items":[1]},"matchingRules":{"$.body.items":{"match":"type","min":1,"max":3}}}`, `{"items":[4,5]}`, nil},
+		{"comodín de índice", `{"body":{"items":[{"id":1}]},"matchingRules":{"$.body.items":{"match":"type"},"$.body.items[*].id":{"match":"integer"}}}`, `{"items":[{"id":2},{"id":2.5}]}`, []string{"$.items[1].id: se esperaba un entero, se obtuvo 2.5"}},
+		{"combine OR", `{"body":{"v":"a"},"matchingRules":{"body":{"$.v":{"combine":"OR","matchers":[{"match":"integer"},{"match":"regex","regex":"[a-z]+"}]}}}}`, `{"v":"zz"}`, nil},
+		{"matcher no soportado", `{"body":{"v":1},"matchingRules":{"$.body.v":{"match":"semver"}}}`, `{"v":1}`, []string{`pacttest: matchingRules $.body.v: matcher "semver" no soportado`}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := check(t, serve(tt.body, nil), tt.response)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("diferencias = %q, se esperaba %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckHeaders(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		headers  map[string]string
+		want     []string
+	}{
+		{"igualdad", `{"headers":{"X-Version":"2"}}`, map[string]string{"X-Version": "2"}, nil},
+		{"distinto", `{"headers":{"X-Version":"2"}}`, map[string]string{"X-Version": "3"}, []string{`header X-Version: se esperaba "2", se obtuvo "3"`}},
+		{"Content-Type sin parámetros", `{"headers":{"Content-Type":"application/json"}}`, map[string]string{"Content-Type": "application/json; charset=utf-8"}, nil},
+		{"regex v2", `{"headers":{"ETag":"\"1\""},"matchingRules":{"$.headers.ETag":{"regex":"\"\\w+\""}}}`, map[string]string{"ETag": `"abc"`}, nil},
+		{"regex v3 sin mayúsculas", `{"headers":{"ETag":"\"1\""},"matchingRules":{"header":{"etag":{"matchers":[{"match":"regex","regex":"\"\\d+\""}]}}}}`, map[string]string{"ETag": `"abc"`}, []string{`header Etag: "abc" no coincide con /"\d+"/`}},
+		{"include", `{"matchingRules":{"$.headers.Cache-Control":{"match":"include","value":"no-store"}}}`, map[string]string{"Cache-Control": "private, no-store"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := check(t, serve(`{}`, tt.headers), tt.response)
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("diferencias = %q, se esperaba %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pact.json")
+	os.WriteFile(path, []byte(`{
+		"consumer": {"name": "web"}, "provider": {"name": "api"},
+		"interactions": [{
+			"description": "lista usuarios",
+			"providerState": "hay usuarios",
+			"request": {"method": "get", "path": "/users", "query": "page=2", "headers": {"Content-Type": "text/plain"}, "body": "hola"},
+			"response": {"status": 200, "body": {"page": "2", "body": "hola"}}
+		}]
+	}`), 0o600)
+
+	var prepared, cleaned bool
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{"page": r.URL.Query().Get("page"), "body": string(b)})
+	})
+	Verify(t, h, path, map[string]StateFunc{
+		"hay usuarios": func(ctx context.Context, params map[string]any) (func(), error) {
+			prepared = true
+			return func() { cleaned = true }, nil
+		},
+	})
+	if !prepared || !cleaned {
+		t.Errorf("estado preparado %v, limpiado %v", prepared, cleaned)
+	}
+
+	var in Interaction
+	json.Unmarshal([]byte(`{"providerState":"otro","request":{"method":"GET","path":"/"}}`), &in)
+	if got := (&Verifier{Handler: h}).Check(context.Background(), in); len(got) != 1 || !strings.Contains(got[0], "sin handler") {
+		t.Errorf("Check = %q, se esperaba un estado sin handler", got)
+	}
+}
@@ -0,0 +1,123 @@
+// Package params interpreta los parámetros de ruta con tipos concretos,
+// con errores uniformes para todos los handlers.
+//
+//	id, err := params.Int64(rt, r, "id")
+//	if err != nil {
+//		http.Error(w, err.Error(), http.StatusBadRequest)
+//		return
+//	}
+package params
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Getter es la parte de router.Router que usan estos helpers.
+type Getter interface {
+	Param(r *http.Request, key string) string
+}
+
+// Errores base; se comparan con errors.Is.
+var (
+	ErrMissing = errors.New("parámetro ausente")
+	ErrInvalid = errors.New("parámetro inválido")
+)
+
+// Error describe un parámetro ausente o mal formado.
+type Error struct {
+	Key   string
+	Value string
+	// Type es el tipo esperado: "int", "int64", "uuid" o "bool".
+	Type string
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if errors.Is(e.Err, ErrMissing) {
+		return fmt.Sprintf("params: %s: %v", e.Key, e.Err)
+	}
+	return fmt.Sprintf("params: %s=%q: se esperaba %s", e.Key, e.Value, e.Type)
+}
+
+func (e *Error) Unwrap() error { return e.Err }
+
+func lookup(g Getter, r *http.Request, key, typ string) (string, error) {
+	v := g.Param(r, key)
+	if v == "" {
+		return "", &Error{Key: key, Type: typ, Err: ErrMissing}
+	}
+	return v, nil
+}
+
+// Int retorna el parámetro key como int.
+func Int(g Getter, r *http.Request, key string) (int, error) {
+	v, err := lookup(g, r, key, "int")
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, &Error{Key: key, Value: v, Type: "int", Err: ErrInvalid}
+	}
+	return n, nil
+}
+
+// Int64 retorna el parámetro key como int64.
+func Int64(g Getter, r *http.Request, key string) (int64, error) {
+	v, err := lookup(g, r, key, "int64")
+	if err != nil {
+		return 0, err
+	}
+	n, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, &Error{Key: key, Value: v, Type: "int64", Err: ErrInvalid}
+	}
+	return n, nil
+}
+
+// Bool retorna el parámetro key como bool. Acepta los valores de
+// strconv.ParseBool además de "yes"/"no" y "on"/"off".
+func Bool(g Getter, r *http.Request, key string) (bool, error) {
+	v, err := lookup(g, r, key, "bool")
+	if err != nil {
+		return false, err
+	}
+	switch strings.ToLower(v) {
+	case "yes", "on":
+		return true, nil
+	case "no", "off":
+		return false, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return false, &Error{Key: key, Value: v, Type: "bool", Err: ErrInvalid}
+	}
+	return b, nil
+}
+
+// UUID valida que el parámetro key sea un UUID en su forma canónica de 36
+// caracteres y lo retorna en minúsculas.
+func UUID(g Getter, r *http.Request, key string) (string, error) {
+	v, err := lookup(g, r, key, "uuid")
+	if err != nil {
+		return "", err
+	}
+	if !isUUID(v) {
+		return "", &Error{Key: key, Value: v, Type: "uuid", Err: ErrInvalid}
+	}
+	return strings.ToLower(v), nil
+}
+
+func isUUID(s string) bool {
+	if len(s) != 36 || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return false
+	}
+	var buf [16]byte
+	_, err := hex.Decode(buf[:], []byte(s[0:8]+s[9:13]+s[14:18]+s[19:23]+s[24:36]))
+	return err == nil
+}
@@ -0,0 +1,112 @@
+package params
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// pathGetter lee los parámetros con http.Request.PathValue.
+type pathGetter struct{}
+
+func (pathGetter) Param(r *http.Request, key string) string { return r.PathValue(key) }
+
+func request(key, value string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+	if value != "" {
+		r.SetPathValue(key, value)
+	}
+	return r
+}
+
+func TestInt(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int64
+		wantErr error
+	}{
+		{"positivo", "42", 42, nil},
+		{"negativo", "-7", -7, nil},
+		{"ausente", "", 0, ErrMissing},
+		{"no numérico", "4x", 0, ErrInvalid},
+		{"desborda int64", "9223372036854775808", 0, ErrInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := request("id", tt.value)
+			n, err := Int(pathGetter{}, r, "id")
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) || int64(n) != tt.want {
+				t.Errorf("Int = %d, %v; se esperaba %d, %v", n, err, tt.want, tt.wantErr)
+			}
+			n64, err := Int64(pathGetter{}, r, "id")
+			if !errors.Is(err, tt.wantErr) || (err == nil) != (tt.wantErr == nil) || n64 != tt.want {
+				t.Errorf("Int64 = %d, %v; se esperaba %d, %v", n64, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBool(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    bool
+		wantErr bool
+	}{
+		{"true", true, false},
+		{"1", true, false},
+		{"yes", true, false},
+		{"ON", true, false},
+		{"false", false, false},
+		{"0", false, false},
+		{"No", false, false},
+		{"off", false, false},
+		{"quizás", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := Bool(pathGetter{}, request("activo", tt.value), "activo")
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Bool = %v, %v; se esperaba %v (error: %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUUID(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"minúsculas", "123e4567-e89b-12d3-a456-426614174000", "123e4567-e89b-12d3-a456-426614174000", false},
+		{"mayúsculas", "123E4567-E89B-12D3-A456-426614174000", "123e4567-e89b-12d3-a456-426614174000", false},
+		{"sin guiones", "123e4567e89b12d3a456426614174000", "", true},
+		{"guion desplazado", "123e456-7e89b-12d3-a456-426614174000", "", true},
+		{"no hexadecimal", "123e4567-e89b-12d3-a456-42661417400g", "", true},
+		{"con llaves", "{123e4567-e89b-12d3-a456-426614174000}", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UUID(pathGetter{}, request("id", tt.value), "id")
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("UUID = %q, %v; se esperaba %q (error: %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	_, err := Int(pathGetter{}, request("id", ""), "id")
+	if got := err.Error(); got != "params: id: parámetro ausente" {
+		t.Errorf("Error() = %q", got)
+	}
+	_, err = UUID(pathGetter{}, request("id", "x"), "id")
+	var pe *Error
+	if !errors.As(err, &pe) || pe.Type != "uuid" || pe.Value != "x" || !strings.Contains(err.Error(), `id="x": se esperaba uuid`) {
+		t.Errorf("err = %v", err)
+	}
+}
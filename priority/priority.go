@@ -0,0 +1,147 @@
+// Package priority lleva la prioridad de una petición (RFC 9218 o un header
+// propio) al contexto, para que los subsistemas de encolado y descarte de
+// carga la respeten y el transporte saliente la propague a otros servicios.
+package priority
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// DefaultUrgency es la urgencia que RFC 9218 asume cuando no se indica.
+const DefaultUrgency = 3
+
+// Header es el header definido por RFC 9218.
+const Header = "Priority"
+
+// Priority es la prioridad de una petición. Urgency va de 0 (más urgente)
+// a 7 (menos urgente).
+type Priority struct {
+	Urgency     int
+	Incremental bool
+}
+
+// Default es la prioridad de las peticiones que no declaran ninguna.
+var Default = Priority{Urgency: DefaultUrgency}
+
+// String formatea p como valor del header Priority.
+func (p Priority) String() string {
+	s := "u=" + strconv.Itoa(p.Urgency)
+	if p.Incremental {
+		s += ", i"
+	}
+	return s
+}
+
+// Parse interpreta un valor del header Priority ("u=1, i"). Los parámetros
+// desconocidos o inválidos se ignoran, como exige la RFC.
+func Parse(v string) Priority {
+	p := Default
+	for item := range strings.SplitSeq(v, ",") {
+		key, value, hasValue := strings.Cut(strings.TrimSpace(item), "=")
+		switch strings.TrimSpace(key) {
+		case "u":
+			if u, err := strconv.Atoi(strings.TrimSpace(value)); err == nil && u >= 0 && u <= 7 {
+				p.Urgency = u
+			}
+		case "i":
+			p.Incremental = !hasValue || strings.TrimSpace(value) == "?1"
+		}
+	}
+	return p
+}
+
+type ctxKey struct{}
+
+// WithPriority retorna un contexto que lleva p.
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, ctxKey{}, p)
+}
+
+// FromContext retorna la prioridad del contexto, o Default.
+func FromContext(ctx context.Context) Priority {
+	if p, ok := ctx.Value(ctxKey{}).(Priority); ok {
+		return p
+	}
+	return Default
+}
+
+// Options configura Middleware.
+type Options struct {
+	// CustomHeader es un header alternativo, p. ej. X-Request-Priority,
+	// que tiene precedencia sobre Priority. Acepta una urgencia numérica
+	// (0-7) o los nombres "critical", "high", "normal", "low" y "background".
+	CustomHeader string
+	// Trust decide si se acepta la prioridad declarada por el cliente;
+	// por defecto se acepta siempre. Sirve para ignorar prioridades
+	// altas de clientes externos.
+	Trust func(r *http.Request) bool
+}
+
+var names = map[string]int{
+	"critical":   0,
+	"high":       1,
+	"normal":     3,
+	"low":        5,
+	"background": 7,
+}
+
+// Middleware guarda en el contexto la prioridad declarada por la petición.
+func Middleware(opts Options) router.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if opts.Trust != nil && !opts.Trust(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			p, ok := fromRequest(r, opts.CustomHeader)
+			if ok {
+				r = r.WithContext(WithPriority(r.Context(), p))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func fromRequest(r *http.Request, custom string) (Priority, bool) {
+	if custom != "" {
+		if v := strings.ToLower(strings.TrimSpace(r.Header.Get(custom))); v != "" {
+			if u, ok := names[v]; ok {
+				return Priority{Urgency: u}, true
+			}
+			if u, err := strconv.Atoi(v); err == nil && u >= 0 && u <= 7 {
+				return Priority{Urgency: u}, true
+			}
+		}
+	}
+	if v := r.Header.Get(Header); v != "" {
+		return Parse(v), true
+	}
+	return Priority{}, false
+}
+
+// Transport propaga la prioridad del contexto de cada petición saliente
+// en el header Priority, salvo que la petición ya lo traiga.
+type Transport struct {
+	// Base es el transporte subyacente; por defecto http.DefaultTransport.
+	Base http.RoundTripper
+}
+
+// RoundTrip implementa http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	p, ok := req.Context().Value(ctxKey{}).(Priority)
+	if !ok || req.Header.Get(Header) != "" {
+		return base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set(Header, p.String())
+	return base.RoundTrip(req)
+}
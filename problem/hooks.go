@@ -0,0 +1,103 @@
+package problem
+
+import (
+	"errors"
+	"log/slog"
+	"maps"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/profe-ajedrez/transwarp/bind"
+	"github.com/profe-ajedrez/transwarp/params"
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// Install configura r para que las rutas inexistentes, los métodos no
+// permitidos, los errores retornados por los handlers y los panics
+// respondan con documentos de problema.
+func Install(r router.Router) {
+	r.NotFound(NotFound)
+	r.MethodNotAllowed(MethodNotAllowed)
+	r.ErrorHandler(Error)
+	r.Use(Recover(nil))
+}
+
+// NotFound responde 404.
+func NotFound(w http.ResponseWriter, r *http.Request) {
+	_ = Write(w, New(http.StatusNotFound, "No existe un recurso en esta ruta.").ForRequest(r))
+}
+
+// MethodNotAllowed responde 405. El driver ya debería haber fijado Allow.
+func MethodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	_ = Write(w, New(http.StatusMethodNotAllowed, "El método "+r.Method+" no está permitido en esta ruta.").ForRequest(r))
+}
+
+// Recover responde 500 ante un panic del handler, sin exponer su valor al
+// cliente. onPanic recibe el valor y el stack; si es nil se registra con
+// slog. http.ErrAbortHandler se propaga para no alterar su semántica.
+func Recover(onPanic func(r *http.Request, v any, stack []byte)) router.Middleware {
+	if onPanic == nil {
+		onPanic = func(r *http.Request, v any, stack []byte) {
+			slog.Error("panic en handler", "method", r.Method, "path", r.URL.Path, "panic", v, "stack", string(stack))
+		}
+	}
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				v := recover()
+				if v == nil {
+					return
+				}
+				if v == http.ErrAbortHandler {
+					panic(v)
+				}
+				onPanic(r, v, debug.Stack())
+				_ = Write(w, New(http.StatusInternalServerError, "").ForRequest(r))
+			}()
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// FromError convierte err en un Problem nuevo. Reconoce *Problem (que se
+// copia, de modo que pueden declararse como variables de paquete),
+// *router.HTTPError y los errores de validación de bind y params; el resto
+// se reporta como 500 sin detalle para no filtrar información interna.
+func FromError(err error) *Problem {
+	var (
+		p  *Problem
+		he *router.HTTPError
+		fe *bind.FieldError
+		pe *params.Error
+	)
+	switch {
+	case errors.As(err, &p):
+		cp := *p
+		cp.Extensions = maps.Clone(p.Extensions)
+		return &cp
+	case errors.As(err, &he):
+		return New(he.Code, he.Message)
+	case errors.As(err, &fe):
+		return Validation(map[string]string{fe.Field: fe.Err.Error()})
+	case errors.As(err, &pe):
+		return Validation(map[string]string{pe.Key: pe.Err.Error()})
+	case errors.Is(err, bind.ErrUnsupportedMediaType):
+		return New(http.StatusUnsupportedMediaType, err.Error())
+	case errors.Is(err, bind.ErrTooLarge):
+		return New(http.StatusRequestEntityTooLarge, err.Error())
+	case errors.Is(err, bind.ErrUnknownField):
+		return New(http.StatusBadRequest, err.Error())
+	}
+	return New(http.StatusInternalServerError, "")
+}
+
+// Validation crea un 400 con los errores por campo en el miembro "errors".
+func Validation(fields map[string]string) *Problem {
+	return New(http.StatusBadRequest, "La petición contiene campos inválidos.").With("errors", fields)
+}
+
+// Error responde err como documento de problema usando FromError. Sirve
+// como router.ErrorHandlerFunc.
+func Error(w http.ResponseWriter, r *http.Request, err error) {
+	_ = Write(w, FromError(err).ForRequest(r))
+}
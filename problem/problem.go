@@ -0,0 +1,129 @@
+// Package problem implementa respuestas de error application/problem+json
+// (RFC 9457, antes RFC 7807) y los hooks para que las rutas inexistentes,
+// los panics y los errores de validación respondan con el mismo formato.
+package problem
+
+import (
+	"encoding/json"
+	"errors"
+	"maps"
+	"net/http"
+	"strings"
+)
+
+// ContentType es el media type de los documentos de problema.
+const ContentType = "application/problem+json"
+
+// Problem es un documento de problema. Extensions agrega miembros
+// adicionales al nivel superior del JSON.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]any
+}
+
+// New crea un Problem con type "about:blank" y el título estándar del
+// status.
+func New(status int, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+	}
+}
+
+// Error permite retornar un Problem como error desde un handler.
+func (p *Problem) Error() string {
+	if p.Detail != "" {
+		return p.Title + ": " + p.Detail
+	}
+	return p.Title
+}
+
+// With agrega el miembro de extensión key y retorna p.
+func (p *Problem) With(key string, value any) *Problem {
+	if p.Extensions == nil {
+		p.Extensions = make(map[string]any)
+	}
+	p.Extensions[key] = value
+	return p
+}
+
+// ForRequest completa instance con la ruta de r y agrega trace_id si la
+// petición trae uno y p no lo define. Retorna p.
+func (p *Problem) ForRequest(r *http.Request) *Problem {
+	if p.Instance == "" {
+		p.Instance = r.URL.Path
+	}
+	if _, ok := p.Extensions["trace_id"]; !ok {
+		if id := TraceID(r); id != "" {
+			p.With("trace_id", id)
+		}
+	}
+	return p
+}
+
+// MarshalJSON aplana Extensions junto a los miembros estándar, que tienen
+// prioridad ante colisiones.
+func (p *Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]any, len(p.Extensions)+5)
+	maps.Copy(m, p.Extensions)
+	typ := p.Type
+	if typ == "" {
+		typ = "about:blank"
+	}
+	m["type"] = typ
+	if p.Title != "" {
+		m["title"] = p.Title
+	}
+	if p.Status != 0 {
+		m["status"] = p.Status
+	}
+	if p.Detail != "" {
+		m["detail"] = p.Detail
+	}
+	if p.Instance != "" {
+		m["instance"] = p.Instance
+	}
+	return json.Marshal(m)
+}
+
+// Write responde p con su status (500 si no tiene).
+func Write(w http.ResponseWriter, p *Problem) error {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	status := p.Status
+	if status == 0 {
+		status = http.StatusInternalServerError
+	}
+	h := w.Header()
+	h.Set("Content-Type", ContentType)
+	h.Del("Content-Length")
+	w.WriteHeader(status)
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
+
+// Is reporta si err contiene un *Problem.
+func Is(err error) bool {
+	var p *Problem
+	return errors.As(err, &p)
+}
+
+// TraceID extrae el trace-id de traceparent (W3C) o, en su defecto, el
+// X-Request-Id de la petición.
+func TraceID(r *http.Request) string {
+	if tp := r.Header.Get("Traceparent"); tp != "" {
+		parts := strings.Split(tp, "-")
+		if len(parts) >= 2 && len(parts[1]) == 32 {
+			return parts[1]
+		}
+	}
+	return r.Header.Get("X-Request-Id")
+}
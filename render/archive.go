@@ -0,0 +1,113 @@
+package render
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"time"
+)
+
+// ArchiveFile es un archivo a incluir en ZipStream o TarStream.
+type ArchiveFile struct {
+	// Name es la ruta dentro del archivo, con "/" como separador.
+	Name     string
+	Modified time.Time
+	// Size es obligatorio para TarStream, cuyo formato lo requiere antes
+	// del contenido; ZipStream lo ignora.
+	Size int64
+	// Open se invoca justo antes de copiar el contenido, de modo que solo
+	// un archivo está abierto a la vez.
+	Open func() (io.ReadCloser, error)
+}
+
+// ZipStream escribe un zip con los archivos de files directamente sobre w,
+// sin almacenamiento temporal. Por defecto comprime con deflate; ver
+// WithStoreOnly. Si files entrega un error, o un archivo no puede abrirse
+// o copiarse, la escritura se detiene y se retorna el error; en ese punto
+// los encabezados HTTP ya fueron enviados y el cliente recibe un zip
+// truncado.
+func ZipStream(w http.ResponseWriter, files iter.Seq2[ArchiveFile, error], opts ...ExportOption) error {
+	cfg := newExportConfig(opts)
+	cfg.writeHeaders(w, "application/zip")
+	w.WriteHeader(http.StatusOK)
+
+	zw := zip.NewWriter(w)
+	method := zip.Deflate
+	if cfg.storeOnly {
+		method = zip.Store
+	}
+	n := 0
+	for f, err := range files {
+		n++
+		if err != nil {
+			return archiveError(n, err)
+		}
+		fw, err := zw.CreateHeader(&zip.FileHeader{Name: f.Name, Method: method, Modified: f.Modified})
+		if err != nil {
+			return err
+		}
+		if err := cfg.copyFile(fw, f); err != nil {
+			return err
+		}
+		if err := zw.Flush(); err != nil {
+			return err
+		}
+		flush(w)
+	}
+	return zw.Close()
+}
+
+// TarStream escribe un tar con los archivos de files directamente sobre
+// w. Cada ArchiveFile debe declarar Size. Los errores se tratan como en
+// ZipStream.
+func TarStream(w http.ResponseWriter, files iter.Seq2[ArchiveFile, error], opts ...ExportOption) error {
+	cfg := newExportConfig(opts)
+	cfg.writeHeaders(w, "application/x-tar")
+	w.WriteHeader(http.StatusOK)
+
+	tw := tar.NewWriter(w)
+	n := 0
+	for f, err := range files {
+		n++
+		if err != nil {
+			return archiveError(n, err)
+		}
+		hdr := &tar.Header{Name: f.Name, Size: f.Size, Mode: 0o644, ModTime: f.Modified, Typeflag: tar.TypeReg, Format: tar.FormatPAX}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if err := cfg.copyFile(tw, f); err != nil {
+			return err
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+		flush(w)
+	}
+	return tw.Close()
+}
+
+func (c exportConfig) copyFile(dst io.Writer, f ArchiveFile) error {
+	rc, err := f.Open()
+	var n int64
+	if err == nil {
+		n, err = io.Copy(dst, rc)
+		if cerr := rc.Close(); err == nil {
+			err = cerr
+		}
+	}
+	if c.onFile != nil {
+		c.onFile(f.Name, n, err)
+	}
+	if err != nil {
+		return fmt.Errorf("render: %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+func archiveError(n int, err error) error {
+	return fmt.Errorf("render: archivo %d: %w", n, err)
+}
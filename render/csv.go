@@ -0,0 +1,59 @@
+package render
+
+import (
+	"encoding/csv"
+	"iter"
+	"net/http"
+)
+
+// CSV escribe headers y luego cada fila producida por rows, haciendo flush
+// periódicamente para no materializar el resultado completo en memoria.
+// Si rows entrega un error la escritura se detiene y se retorna ese error;
+// en ese punto los encabezados HTTP ya fueron enviados.
+func CSV(w http.ResponseWriter, headers []string, rows iter.Seq2[[]string, error], opts ...ExportOption) error {
+	cfg := newExportConfig(opts)
+	cfg.writeHeaders(w, "text/csv; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+
+	if cfg.bom {
+		if _, err := w.Write([]byte("\xEF\xBB\xBF")); err != nil {
+			return err
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Comma = cfg.comma
+
+	if len(headers) > 0 {
+		if err := cw.Write(headers); err != nil {
+			return err
+		}
+	}
+
+	n := 0
+	record := make([]string, 0, len(headers))
+	for row, err := range rows {
+		if err != nil {
+			cw.Flush()
+			return iterError(n+1, err)
+		}
+		record = record[:0]
+		for _, v := range row {
+			record = append(record, cfg.cell(v))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+		n++
+		if n%flushEvery == 0 {
+			cw.Flush()
+			if err := cw.Error(); err != nil {
+				return err
+			}
+			flush(w)
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
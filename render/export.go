@@ -0,0 +1,101 @@
+// Package render agrupa helpers para escribir respuestas HTTP de forma
+// consistente, independiente del driver que esté sirviendo la petición.
+package render
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// flushEvery indica cada cuántas filas se fuerza un flush hacia el cliente.
+const flushEvery = 256
+
+// ExportOption configura las exportaciones CSV, XLSX y los archivos Zip y
+// Tar.
+type ExportOption func(*exportConfig)
+
+type exportConfig struct {
+	bom            bool
+	comma          rune
+	filename       string
+	sheet          string
+	escapeFormulas bool
+	storeOnly      bool
+	onFile         func(name string, size int64, err error)
+}
+
+func newExportConfig(opts []ExportOption) exportConfig {
+	cfg := exportConfig{comma: ',', sheet: "Sheet1"}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithBOM antepone el BOM UTF-8 para que Excel detecte la codificación.
+func WithBOM() ExportOption {
+	return func(c *exportConfig) { c.bom = true }
+}
+
+// WithComma cambia el separador de campos del CSV (por defecto ',').
+func WithComma(r rune) ExportOption {
+	return func(c *exportConfig) { c.comma = r }
+}
+
+// WithFilename agrega Content-Disposition: attachment con el nombre indicado.
+func WithFilename(name string) ExportOption {
+	return func(c *exportConfig) { c.filename = name }
+}
+
+// WithSheetName cambia el nombre de la hoja generada por XLSX. Excel
+// admite hasta 31 caracteres, sin []:*?/\ ni comillas en los extremos;
+// XLSX retorna error ante un nombre inválido.
+func WithSheetName(name string) ExportOption {
+	return func(c *exportConfig) { c.sheet = name }
+}
+
+// WithFormulaEscaping neutraliza celdas que comienzan con =, +, - o @
+// anteponiendo una comilla simple, evitando inyección de fórmulas. Solo
+// aplica a CSV: XLSX escribe texto en línea, que nunca se evalúa.
+func WithFormulaEscaping() ExportOption {
+	return func(c *exportConfig) { c.escapeFormulas = true }
+}
+
+// WithStoreOnly guarda los archivos de ZipStream sin comprimir, útil para
+// contenido ya comprimido (imágenes, video, otros zip).
+func WithStoreOnly() ExportOption {
+	return func(c *exportConfig) { c.storeOnly = true }
+}
+
+// WithFileCallback invoca fn tras agregar cada archivo a ZipStream o
+// TarStream, con los bytes escritos o el error al abrirlo o copiarlo.
+func WithFileCallback(fn func(name string, size int64, err error)) ExportOption {
+	return func(c *exportConfig) { c.onFile = fn }
+}
+
+func (c exportConfig) cell(v string) string {
+	if c.escapeFormulas && v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+func (c exportConfig) writeHeaders(w http.ResponseWriter, contentType string) {
+	h := w.Header()
+	h.Set("Content-Type", contentType)
+	h.Set("X-Content-Type-Options", "nosniff")
+	if c.filename != "" {
+		h.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": c.filename}))
+	}
+}
+
+// flush envía al cliente lo escrito hasta ahora si el writer lo soporta.
+func flush(w http.ResponseWriter) {
+	_ = http.NewResponseController(w).Flush()
+}
+
+func iterError(row int, err error) error {
+	return fmt.Errorf("render: fila %d: %w", row, err)
+}
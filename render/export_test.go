@@ -0,0 +1,142 @@
+package render
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"io"
+	"iter"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func rows(data ...[]string) iter.Seq2[[]string, error] {
+	return func(yield func([]string, error) bool) {
+		for _, row := range data {
+			if !yield(row, nil) {
+				return
+			}
+		}
+	}
+}
+
+func TestCSV(t *testing.T) {
+	data := [][]string{{"=SUM(A1)", "+1", "texto, con coma"}, {"-2", "@cmd", "línea\nnueva"}}
+	tests := []struct {
+		name string
+		opts []ExportOption
+		want [][]string
+	}{
+		{"sin opciones", nil, data},
+		{"escape de fórmulas", []ExportOption{WithFormulaEscaping()}, [][]string{{"'=SUM(A1)", "'+1", "texto, con coma"}, {"'-2", "'@cmd", "línea\nnueva"}}},
+		{"punto y coma con BOM", []ExportOption{WithComma(';'), WithBOM(), WithFilename("ventas.csv")}, data},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := CSV(w, []string{"a", "b", "c"}, rows(data...), tt.opts...); err != nil {
+				t.Fatal(err)
+			}
+			cfg := newExportConfig(tt.opts)
+			body := w.Body.Bytes()
+			if hasBOM := bytes.HasPrefix(body, []byte("\xEF\xBB\xBF")); hasBOM != cfg.bom {
+				t.Errorf("BOM presente = %v", hasBOM)
+			}
+			if cfg.filename != "" && !strings.Contains(w.Header().Get("Content-Disposition"), cfg.filename) {
+				t.Errorf("Content-Disposition = %q", w.Header().Get("Content-Disposition"))
+			}
+			cr := csv.NewReader(bytes.NewReader(bytes.TrimPrefix(body, []byte("\xEF\xBB\xBF"))))
+			cr.Comma = cfg.comma
+			got, err := cr.ReadAll()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.EqualFunc(got[1:], tt.want, slices.Equal) {
+				t.Errorf("filas = %q, se esperaba %q", got[1:], tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVRowError(t *testing.T) {
+	boom := errors.New("consulta cancelada")
+	seq := func(yield func([]string, error) bool) {
+		if yield([]string{"1"}, nil) {
+			yield(nil, boom)
+		}
+	}
+	err := CSV(httptest.NewRecorder(), nil, seq)
+	if !errors.Is(err, boom) || !strings.Contains(err.Error(), "fila 2") {
+		t.Errorf("err = %v", err)
+	}
+}
+
+// sheet retorna el XML de la hoja y del libro de una planilla generada.
+func sheet(t *testing.T, body []byte) (sheet, workbook string) {
+	t.Helper()
+	zr, err := zip.NewReader(bytes.NewReader(body), int64(len(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	read := func(name string) string {
+		f, err := zr.Open(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		b, _ := io.ReadAll(f)
+		return string(b)
+	}
+	return read("xl/worksheets/sheet1.xml"), read("xl/workbook.xml")
+}
+
+func TestXLSX(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := XLSX(w, []string{"fórmula"}, rows([]string{"=1+1"}, []string{"a<b & \x01c"}), WithFormulaEscaping(), WithSheetName("Ventas & Co"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, wb := sheet(t, w.Body.Bytes())
+	for _, want := range []string{">fórmula<", ">=1+1<", ">a&lt;b &amp; c<"} {
+		if !strings.Contains(s, want) {
+			t.Errorf("la hoja no contiene %q:\n%s", want, s)
+		}
+	}
+	if strings.Contains(s, "'=1+1") {
+		t.Error("XLSX no debe anteponer la comilla de WithFormulaEscaping")
+	}
+	if !strings.Contains(wb, `name="Ventas &amp; Co"`) {
+		t.Errorf("workbook sin el nombre de hoja escapado:\n%s", wb)
+	}
+}
+
+func TestXLSXSheetName(t *testing.T) {
+	tests := []struct {
+		name    string
+		wantErr bool
+	}{
+		{"Hoja 1", false},
+		{strings.Repeat("ñ", 31), false},
+		{"", true},
+		{strings.Repeat("a", 32), true},
+		{"a/b", true},
+		{"[x]", true},
+		{"'inicio", true},
+		{"fin'", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := XLSX(w, nil, rows(), WithSheetName(tt.name))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, se esperaba error: %v", err, tt.wantErr)
+			}
+			if err != nil && (w.Header().Get("Content-Type") != "" || w.Body.Len() > 0) {
+				t.Error("con un nombre inválido no debe escribirse nada")
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package render
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"time"
+)
+
+// FileOption configura File y FileFS.
+type FileOption func(*fileConfig)
+
+type fileConfig struct {
+	disposition string
+	filename    string
+	etag        string
+}
+
+// Attachment fuerza la descarga con el nombre indicado; si name está vacío
+// se usa el nombre del archivo.
+func Attachment(name string) FileOption {
+	return func(c *fileConfig) { c.disposition, c.filename = "attachment", name }
+}
+
+// Inline pide al navegador mostrar el archivo, sugiriendo name si se
+// guarda.
+func Inline(name string) FileOption {
+	return func(c *fileConfig) { c.disposition, c.filename = "inline", name }
+}
+
+// WithETag reemplaza el ETag derivado de tamaño y fecha de modificación,
+// por ejemplo con un hash del contenido.
+func WithETag(etag string) FileOption {
+	return func(c *fileConfig) { c.etag = etag }
+}
+
+// File sirve el archivo name del disco. Ver FileFS.
+func File(w http.ResponseWriter, r *http.Request, name string, opts ...FileOption) error {
+	return serveFile(w, r, os.DirFS(filepath.Dir(name)), filepath.Base(name), opts)
+}
+
+// FileFS sirve name desde fsys mediante http.ServeContent, que resuelve
+// Range (incluidos rangos múltiples), If-Range, If-None-Match,
+// If-Modified-Since y HEAD. Si no se indica otro, el ETag se deriva del
+// tamaño y la fecha de modificación; es fuerte para que If-Range permita
+// reanudar descargas. Un archivo inexistente responde 404 y
+// un directorio, 403; en ambos casos se retorna el error.
+func FileFS(w http.ResponseWriter, r *http.Request, fsys fs.FS, name string, opts ...FileOption) error {
+	return serveFile(w, r, fsys, name, opts)
+}
+
+func serveFile(w http.ResponseWriter, r *http.Request, fsys fs.FS, name string, opts []FileOption) error {
+	var cfg fileConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	f, err := fsys.Open(name)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, fs.ErrNotExist) {
+			status = http.StatusNotFound
+		} else if errors.Is(err, fs.ErrPermission) {
+			status = http.StatusForbidden
+		}
+		http.Error(w, http.StatusText(status), status)
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return err
+	}
+	if info.IsDir() {
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+		return &fs.PathError{Op: "serve", Path: name, Err: errors.New("es un directorio")}
+	}
+	rs, ok := f.(io.ReadSeeker)
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return &fs.PathError{Op: "serve", Path: name, Err: errors.New("el archivo no admite Seek")}
+	}
+
+	if cfg.etag == "" {
+		cfg.etag = `"` + strconv.FormatInt(info.ModTime().UnixNano(), 36) + "-" + strconv.FormatInt(info.Size(), 36) + `"`
+	}
+	cfg.serve(w, r, info.Name(), info.ModTime(), rs)
+	return nil
+}
+
+// Content sirve contenido generado durante la petición (un zip armado al
+// vuelo, una transcodificación, ...) con el mismo soporte de Range que
+// FileFS, incluidas las respuestas multipart/byteranges. name determina
+// el Content-Type si no está definido y el nombre sugerido con
+// Attachment o Inline. Sin WithETag ni modtime, If-Range nunca coincide y
+// los reintentos reciben el contenido completo. Para un io.ReaderAt de
+// tamaño conocido use io.NewSectionReader.
+func Content(w http.ResponseWriter, r *http.Request, name string, modtime time.Time, content io.ReadSeeker, opts ...FileOption) {
+	var cfg fileConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.serve(w, r, name, modtime, content)
+}
+
+func (cfg fileConfig) serve(w http.ResponseWriter, r *http.Request, name string, modtime time.Time, content io.ReadSeeker) {
+	h := w.Header()
+	if cfg.etag != "" {
+		h.Set("ETag", cfg.etag)
+	}
+	h.Set("X-Content-Type-Options", "nosniff")
+	if cfg.disposition != "" {
+		filename := cfg.filename
+		if filename == "" {
+			filename = path.Base(name)
+		}
+		h.Set("Content-Disposition", mime.FormatMediaType(cfg.disposition, map[string]string{"filename": filename}))
+	}
+	http.ServeContent(w, r, name, modtime, content)
+}
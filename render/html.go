@@ -0,0 +1,166 @@
+package render
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+)
+
+// Renderer ejecuta plantillas con nombre.
+type Renderer interface {
+	Render(w io.Writer, name string, data any) error
+}
+
+// RendererFunc adapta una función a Renderer. Sirve para usar un
+// *template.Template suelto: RendererFunc(t.ExecuteTemplate).
+type RendererFunc func(w io.Writer, name string, data any) error
+
+// Render llama a f(w, name, data).
+func (f RendererFunc) Render(w io.Writer, name string, data any) error {
+	return f(w, name, data)
+}
+
+// ErrNoRenderer indica que HTML se llamó con un Renderer nil.
+var ErrNoRenderer = errors.New("render: no hay Renderer configurado")
+
+// HTML ejecuta la plantilla name de rd con data y la escribe con status.
+// La plantilla se ejecuta completa en un buffer antes de escribir, de modo
+// que un error de ejecución no deja una página a medias.
+func HTML(w http.ResponseWriter, rd Renderer, status int, name string, data any) error {
+	if rd == nil {
+		return ErrNoRenderer
+	}
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := rd.Render(buf, name, data); err != nil {
+		return err
+	}
+	return write(w, status, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// TemplateOptions configura NewTemplates.
+type TemplateOptions struct {
+	// Pages es el directorio de páginas; cada archivo es una plantilla
+	// cuyo nombre es su ruta relativa sin extensión ("users/show").
+	// Por defecto "pages".
+	Pages string
+	// Layouts y Partials son patrones (fs.Glob) de archivos compartidos
+	// por todas las páginas. Por defecto "layouts/*.html" y
+	// "partials/*.html".
+	Layouts  string
+	Partials string
+	// Layout es la plantilla que envuelve las páginas: el nombre de un
+	// archivo de layout o de un {{define}}, que incluye la página con
+	// {{block "content" .}}. Si no existe, se ejecuta la página
+	// directamente. Por defecto "base.html".
+	Layout string
+	// Ext es la extensión de las páginas; por defecto ".html".
+	Ext   string
+	Funcs template.FuncMap
+	// Reload vuelve a parsear en cada Render; útil en desarrollo.
+	Reload bool
+}
+
+// Templates es un Renderer basado en html/template con layouts y
+// partials. Cada página se parsea junto a una copia del conjunto
+// compartido, así dos páginas pueden definir el mismo bloque "content".
+type Templates struct {
+	fsys fs.FS
+	opts TemplateOptions
+
+	mu    sync.RWMutex
+	pages map[string]*template.Template
+}
+
+// NewTemplates parsea las plantillas de fsys.
+func NewTemplates(fsys fs.FS, opts TemplateOptions) (*Templates, error) {
+	if opts.Pages == "" {
+		opts.Pages = "pages"
+	}
+	if opts.Layouts == "" {
+		opts.Layouts = "layouts/*.html"
+	}
+	if opts.Partials == "" {
+		opts.Partials = "partials/*.html"
+	}
+	if opts.Layout == "" {
+		opts.Layout = "base.html"
+	}
+	if opts.Ext == "" {
+		opts.Ext = ".html"
+	}
+	t := &Templates{fsys: fsys, opts: opts}
+	if err := t.parse(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+func (t *Templates) parse() error {
+	shared := template.New("").Funcs(t.opts.Funcs)
+	for _, pattern := range []string{t.opts.Layouts, t.opts.Partials} {
+		files, err := fs.Glob(t.fsys, pattern)
+		if err != nil {
+			return err
+		}
+		if len(files) > 0 {
+			if shared, err = shared.ParseFS(t.fsys, files...); err != nil {
+				return err
+			}
+		}
+	}
+
+	pages := map[string]*template.Template{}
+	err := fs.WalkDir(t.fsys, t.opts.Pages, func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != t.opts.Ext {
+			return err
+		}
+		src, err := fs.ReadFile(t.fsys, p)
+		if err != nil {
+			return err
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(p, t.opts.Pages+"/"), t.opts.Ext)
+		tmpl, err := shared.Clone()
+		if err != nil {
+			return err
+		}
+		if tmpl, err = tmpl.New(name).Parse(string(src)); err != nil {
+			return fmt.Errorf("render: %s: %w", p, err)
+		}
+		pages[name] = tmpl
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	t.pages = pages
+	t.mu.Unlock()
+	return nil
+}
+
+// Render ejecuta la página name dentro del layout.
+func (t *Templates) Render(w io.Writer, name string, data any) error {
+	if t.opts.Reload {
+		if err := t.parse(); err != nil {
+			return err
+		}
+	}
+	t.mu.RLock()
+	tmpl, ok := t.pages[name]
+	t.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("render: plantilla %q inexistente", name)
+	}
+	if tmpl.Lookup(t.opts.Layout) != nil {
+		return tmpl.ExecuteTemplate(w, t.opts.Layout, data)
+	}
+	return tmpl.ExecuteTemplate(w, name, data)
+}
@@ -0,0 +1,83 @@
+package render
+
+import (
+	"errors"
+	"html/template"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"testing/fstest"
+)
+
+func TestHTML(t *testing.T) {
+	tmpl := template.Must(template.New("").Parse(`{{define "ok"}}<p>{{.}}</p>{{end}}{{define "roto"}}a medias{{.Nada}}{{end}}`))
+	rd := RendererFunc(tmpl.ExecuteTemplate)
+
+	tests := []struct {
+		name     string
+		rd       Renderer
+		tmpl     string
+		wantErr  bool
+		wantBody string
+	}{
+		{"renderiza", rd, "ok", false, "<p>&lt;hola&gt;</p>"},
+		{"error de ejecución", rd, "roto", true, ""},
+		{"plantilla inexistente", rd, "nada", true, ""},
+		{"sin Renderer", nil, "ok", true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			err := HTML(w, tt.rd, http.StatusTeapot, tt.tmpl, "<hola>")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, se esperaba error: %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if w.Body.Len() != 0 || w.Header().Get("Content-Type") != "" {
+					t.Errorf("un error no debe escribir nada: %q", w.Body.String())
+				}
+				return
+			}
+			if w.Code != http.StatusTeapot || w.Body.String() != tt.wantBody {
+				t.Errorf("HTML = %d %q, se esperaba %d %q", w.Code, w.Body.String(), http.StatusTeapot, tt.wantBody)
+			}
+			if got := w.Header().Get("Content-Type"); got != "text/html; charset=utf-8" {
+				t.Errorf("Content-Type = %q", got)
+			}
+		})
+	}
+	if err := HTML(httptest.NewRecorder(), nil, http.StatusOK, "ok", nil); !errors.Is(err, ErrNoRenderer) {
+		t.Errorf("err = %v, se esperaba ErrNoRenderer", err)
+	}
+}
+
+func TestTemplatesLayout(t *testing.T) {
+	fsys := fstest.MapFS{
+		"layouts/base.html":     {Data: []byte(`<main>{{block "content" .}}{{end}}</main>`)},
+		"partials/saludo.html":  {Data: []byte(`{{define "saludo"}}hola {{.}}{{end}}`)},
+		"pages/inicio.html":     {Data: []byte(`{{define "content"}}{{template "saludo" .}}{{end}}`)},
+		"pages/users/show.html": {Data: []byte(`{{define "content"}}usuario {{.}}{{end}}`)},
+	}
+	rd, err := NewTemplates(fsys, TemplateOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		page string
+		want string
+	}{
+		{"inicio", "<main>hola ana</main>"},
+		{"users/show", "<main>usuario ana</main>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.page, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if err := HTML(w, rd, http.StatusOK, tt.page, "ana"); err != nil {
+				t.Fatal(err)
+			}
+			if w.Body.String() != tt.want {
+				t.Errorf("cuerpo = %q, se esperaba %q", w.Body.String(), tt.want)
+			}
+		})
+	}
+}
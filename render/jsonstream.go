@@ -0,0 +1,118 @@
+package render
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"iter"
+	"net/http"
+)
+
+// JSONArrayWriter escribe un arreglo JSON elemento a elemento. La escritura
+// es síncrona sobre la conexión, por lo que un cliente lento frena al
+// productor en lugar de acumular datos en memoria.
+type JSONArrayWriter struct {
+	w      http.ResponseWriter
+	n      int
+	opened bool
+	closed bool
+	err    error
+}
+
+// JSONArrayStream prepara w para escribir un arreglo JSON incremental.
+// Los headers se envían con el primer elemento o al cerrar; Close debe
+// llamarse siempre para que el documento resultante sea válido.
+func JSONArrayStream(w http.ResponseWriter) *JSONArrayWriter {
+	return &JSONArrayWriter{w: w}
+}
+
+func (a *JSONArrayWriter) open() {
+	if a.opened {
+		return
+	}
+	a.opened = true
+	a.w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	a.w.WriteHeader(http.StatusOK)
+	_, a.err = a.w.Write([]byte{'['})
+}
+
+// Write agrega v al arreglo.
+func (a *JSONArrayWriter) Write(v any) error {
+	if a.closed {
+		return errors.New("render: JSONArrayWriter cerrado")
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	a.open()
+	if a.err != nil {
+		return a.err
+	}
+	if a.n > 0 {
+		if _, a.err = a.w.Write([]byte{','}); a.err != nil {
+			return a.err
+		}
+	}
+	if _, a.err = a.w.Write(data); a.err != nil {
+		return a.err
+	}
+	a.n++
+	if a.n%flushEvery == 0 {
+		flush(a.w)
+	}
+	return nil
+}
+
+// Close cierra el arreglo. Es seguro llamarlo más de una vez.
+func (a *JSONArrayWriter) Close() error {
+	if a.closed {
+		return a.err
+	}
+	a.open()
+	a.closed = true
+	if a.err != nil {
+		return a.err
+	}
+	_, a.err = a.w.Write([]byte{']'})
+	return a.err
+}
+
+// JSONArray escribe como arreglo JSON los elementos producidos por seq. Si
+// seq entrega un error se cierra el arreglo y se retorna el error; la
+// respuesta ya enviada es un documento válido pero incompleto.
+func JSONArray[T any](w http.ResponseWriter, seq iter.Seq2[T, error]) error {
+	a := JSONArrayStream(w)
+	for v, err := range seq {
+		if err != nil {
+			a.Close()
+			return err
+		}
+		if err := a.Write(v); err != nil {
+			a.Close()
+			return err
+		}
+	}
+	return a.Close()
+}
+
+// JSONArrayChan escribe como arreglo JSON los elementos recibidos por ch
+// hasta que se cierre o ctx se cancele.
+func JSONArrayChan[T any](ctx context.Context, w http.ResponseWriter, ch <-chan T) error {
+	a := JSONArrayStream(w)
+	for {
+		select {
+		case <-ctx.Done():
+			a.Close()
+			return ctx.Err()
+		case v, ok := <-ch:
+			if !ok {
+				return a.Close()
+			}
+			if err := a.Write(v); err != nil {
+				a.Close()
+				return err
+			}
+		}
+	}
+}
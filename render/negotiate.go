@@ -0,0 +1,24 @@
+package render
+
+import (
+	"net/http"
+
+	"github.com/profe-ajedrez/transwarp/codec"
+)
+
+// Negotiate serializa v con el codec que prefiere el cliente según Accept
+// (ver codec.Register), usando JSON si no expresa preferencia. Si ningún
+// formato registrado es aceptable responde 406.
+func Negotiate(w http.ResponseWriter, r *http.Request, status int, v any) error {
+	w.Header().Add("Vary", "Accept")
+	mediaType, c, ok := codec.Negotiate(r.Header.Get("Accept"), "application/json")
+	if !ok {
+		http.Error(w, http.StatusText(http.StatusNotAcceptable), http.StatusNotAcceptable)
+		return nil
+	}
+	data, err := c.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return write(w, status, mediaType, data)
+}
@@ -0,0 +1,29 @@
+package render
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// IsRedirect reporta si code es un status de redirección con Location:
+// 301, 302, 303, 307 o 308.
+func IsRedirect(code int) bool {
+	switch code {
+	case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+		http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		return true
+	}
+	return false
+}
+
+// Redirect responde una redirección a url con code, que debe cumplir
+// IsRedirect. Las URLs relativas se resuelven respecto de la ruta de r,
+// igual que http.Redirect, para que todos los drivers emitan el mismo
+// Location.
+func Redirect(w http.ResponseWriter, r *http.Request, url string, code int) error {
+	if !IsRedirect(code) {
+		return fmt.Errorf("render: código de redirección inválido %d", code)
+	}
+	http.Redirect(w, r, url, code)
+	return nil
+}
@@ -0,0 +1,96 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"strconv"
+	"sync"
+)
+
+// maxPooledBuffer evita devolver al pool buffers que crecieron demasiado.
+const maxPooledBuffer = 64 << 10
+
+var bufPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+func getBuffer() *bytes.Buffer {
+	return bufPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() > maxPooledBuffer {
+		return
+	}
+	buf.Reset()
+	bufPool.Put(buf)
+}
+
+// JSON serializa v como JSON y lo escribe con status. La serialización
+// ocurre antes de escribir cabeceras, así un error de codificación no deja
+// una respuesta 200 a medias y puede manejarse con normalidad.
+func JSON(w http.ResponseWriter, status int, v any) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		return err
+	}
+	return write(w, status, "application/json; charset=utf-8", buf.Bytes())
+}
+
+// XML serializa v como XML, con la declaración estándar, y lo escribe con
+// status.
+func XML(w http.ResponseWriter, status int, v any) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(buf).Encode(v); err != nil {
+		return err
+	}
+	return write(w, status, "application/xml; charset=utf-8", buf.Bytes())
+}
+
+// YAML serializa v como YAML y lo escribe con status. Soporta los tipos
+// que produce un decode JSON típico: structs, mapas, slices y escalares.
+func YAML(w http.ResponseWriter, status int, v any) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	if err := encodeYAML(buf, v); err != nil {
+		return err
+	}
+	return write(w, status, "application/yaml; charset=utf-8", buf.Bytes())
+}
+
+// Text escribe s como texto plano con status.
+func Text(w http.ResponseWriter, status int, s string) error {
+	buf := getBuffer()
+	defer putBuffer(buf)
+	buf.WriteString(s)
+	return write(w, status, "text/plain; charset=utf-8", buf.Bytes())
+}
+
+// Blob escribe data tal cual con el content type indicado. Si contentType
+// está vacío se detecta con http.DetectContentType.
+func Blob(w http.ResponseWriter, status int, contentType string, data []byte) error {
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	return write(w, status, contentType, data)
+}
+
+func write(w http.ResponseWriter, status int, contentType string, body []byte) error {
+	h := w.Header()
+	h.Set("Content-Type", contentType)
+	h.Set("X-Content-Type-Options", "nosniff")
+	// 1xx, 204 y 304 no llevan cuerpo ni Content-Length.
+	if status < 200 || status == http.StatusNoContent || status == http.StatusNotModified {
+		w.WriteHeader(status)
+		return nil
+	}
+	h.Set("Content-Length", strconv.Itoa(len(body)))
+	w.WriteHeader(status)
+	_, err := w.Write(body)
+	return err
+}
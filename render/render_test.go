@@ -0,0 +1,154 @@
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+)
+
+type item struct {
+	XMLName xml.Name `json:"-"`
+	ID      int      `json:"id" xml:"id"`
+	Name    string   `json:"name" xml:"name"`
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	in := item{ID: 7, Name: "<ñandú & cía>"}
+	w := httptest.NewRecorder()
+	if err := JSON(w, http.StatusCreated, in); err != nil {
+		t.Fatal(err)
+	}
+	var out item
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.ID != in.ID || out.Name != in.Name {
+		t.Errorf("ida y vuelta = %+v, se esperaba %+v", out, in)
+	}
+	if w.Code != http.StatusCreated || w.Header().Get("Content-Length") != strconv.Itoa(w.Body.Len()) {
+		t.Errorf("status = %d, Content-Length = %q", w.Code, w.Header().Get("Content-Length"))
+	}
+}
+
+func TestXMLRoundTrip(t *testing.T) {
+	in := item{ID: 7, Name: "<ñandú & cía>"}
+	w := httptest.NewRecorder()
+	if err := XML(w, http.StatusOK, in); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(w.Body.Bytes(), []byte(xml.Header)) {
+		t.Error("falta la declaración XML")
+	}
+	var out item
+	if err := xml.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.ID != in.ID || out.Name != in.Name {
+		t.Errorf("ida y vuelta = %+v, se esperaba %+v", out, in)
+	}
+}
+
+func TestNoBodyStatuses(t *testing.T) {
+	for _, status := range []int{http.StatusNoContent, http.StatusNotModified, http.StatusOK} {
+		w := httptest.NewRecorder()
+		if err := JSON(w, status, map[string]int{"a": 1}); err != nil {
+			t.Fatal(err)
+		}
+		wantBody := status == http.StatusOK
+		if (w.Body.Len() > 0) != wantBody || (w.Header().Get("Content-Length") != "") != wantBody {
+			t.Errorf("%d: cuerpo de %d bytes, Content-Length = %q", status, w.Body.Len(), w.Header().Get("Content-Length"))
+		}
+	}
+}
+
+func TestNeedsQuote(t *testing.T) {
+	tests := []struct {
+		s    string
+		want bool
+	}{
+		{"hola", false},
+		{"hola mundo", false},
+		{"a:b", false},
+		{"", true},
+		{" hola", true},
+		{"true", true},
+		{"No", true},
+		{"y", true},
+		{"~", true},
+		{"null", true},
+		{".inf", true},
+		{".NaN", true},
+		{"<<", true},
+		{"42", true},
+		{"-1.5e3", true},
+		{"0x1F", true},
+		{"0o17", true},
+		{"1_000", true},
+		{"1:30", true},
+		{"2024-01-02", true},
+		{"2024-01-02T10:00:00Z", true},
+		{"- item", true},
+		{"#comentario", true},
+		{"clave: valor", true},
+		{"texto #nota", true},
+		{"termina:", true},
+		{"a\tb", true},
+		{"@usuario", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.s, func(t *testing.T) {
+			if got := needsQuote(tt.s); got != tt.want {
+				t.Errorf("needsQuote(%q) = %v, se esperaba %v", tt.s, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestYAML(t *testing.T) {
+	w := httptest.NewRecorder()
+	v := map[string]any{"nombre": "yes", "cantidad": 3, "tags": []string{"a", "1:30"}}
+	if err := YAML(w, http.StatusOK, v); err != nil {
+		t.Fatal(err)
+	}
+	want := "cantidad: 3\nnombre: \"yes\"\ntags:\n  - a\n  - \"1:30\"\n"
+	if got := w.Body.String(); got != want {
+		t.Errorf("YAML =\n%s\nse esperaba\n%s", got, want)
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		accept     string
+		wantStatus int
+		wantType   string
+	}{
+		{"", http.StatusOK, "application/json"},
+		{"*/*", http.StatusOK, "application/json"},
+		{"application/xml", http.StatusOK, "application/xml"},
+		{"text/html;q=0.9, application/xml;q=0.5", http.StatusOK, "application/xml"},
+		{"image/png", http.StatusNotAcceptable, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.accept, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+			if err := Negotiate(w, r, http.StatusOK, item{ID: 1}); err != nil {
+				t.Fatal(err)
+			}
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, se esperaba %d", w.Code, tt.wantStatus)
+			}
+			if tt.wantType != "" && w.Header().Get("Content-Type") != tt.wantType {
+				t.Errorf("Content-Type = %q, se esperaba %q", w.Header().Get("Content-Type"), tt.wantType)
+			}
+			if w.Header().Get("Vary") != "Accept" {
+				t.Error("falta Vary: Accept")
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package render
+
+import (
+	"errors"
+	"io"
+	"net/http"
+)
+
+// ErrStreamingUnsupported indica que el writer no permite hacer flush, por
+// lo que la respuesta llegaría completa al final en vez de en partes.
+var ErrStreamingUnsupported = errors.New("render: el writer no soporta streaming")
+
+// Stream envía la respuesta en partes: cada Write de fn llega al cliente
+// de inmediato. Antes de invocar fn comprueba que el writer admite flush
+// (directamente o vía Unwrap) y, si no, retorna ErrStreamingUnsupported
+// sin escribir nada. Si el cliente se desconecta, los Write siguientes
+// fallan con el error del contexto de r.
+//
+// Los headers deben definirse antes de llamar a Stream, que los envía con
+// status 200. Si el handler no definió Content-Type se usa
+// application/octet-stream.
+func Stream(w http.ResponseWriter, r *http.Request, fn func(io.Writer) error) error {
+	h := w.Header()
+	if h.Get("Content-Type") == "" {
+		h.Set("Content-Type", "application/octet-stream")
+	}
+	h.Del("Content-Length")
+	// Evita que proxies como nginx acumulen la respuesta.
+	h.Set("X-Accel-Buffering", "no")
+
+	rc := http.NewResponseController(w)
+	if err := rc.Flush(); err != nil {
+		if errors.Is(err, http.ErrNotSupported) {
+			return ErrStreamingUnsupported
+		}
+		return err
+	}
+	return fn(&streamWriter{w: w, rc: rc, r: r})
+}
+
+type streamWriter struct {
+	w  http.ResponseWriter
+	rc *http.ResponseController
+	r  *http.Request
+}
+
+func (s *streamWriter) Write(p []byte) (int, error) {
+	if err := s.r.Context().Err(); err != nil {
+		return 0, err
+	}
+	n, err := s.w.Write(p)
+	if err != nil {
+		return n, err
+	}
+	return n, s.rc.Flush()
+}
@@ -0,0 +1,139 @@
+package render
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"iter"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+)
+
+const xlsxContentType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+
+// XLSX escribe una planilla de una sola hoja en formato Office Open XML.
+// El archivo se genera en streaming sobre w: cada fila se escribe como
+// celdas de texto en línea, sin tabla de strings compartidos, de modo que
+// el uso de memoria no depende de la cantidad de filas.
+func XLSX(w http.ResponseWriter, headers []string, rows iter.Seq2[[]string, error], opts ...ExportOption) error {
+	cfg := newExportConfig(opts)
+	if err := checkSheetName(cfg.sheet); err != nil {
+		return err
+	}
+	cfg.writeHeaders(w, xlsxContentType)
+	w.WriteHeader(http.StatusOK)
+
+	zw := zip.NewWriter(w)
+
+	static := []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/workbook.xml", strings.Replace(xlsxWorkbook, "{{sheet}}", xmlEscape(cfg.sheet), 1)},
+	}
+	for _, f := range static {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, f.body); err != nil {
+			return err
+		}
+	}
+
+	fw, err := zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriter(fw)
+
+	bw.WriteString(xml.Header)
+	bw.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+
+	if len(headers) > 0 {
+		writeXLSXRow(bw, headers)
+	}
+
+	n := 0
+	for row, err := range rows {
+		if err != nil {
+			return iterError(n+1, err)
+		}
+		writeXLSXRow(bw, row)
+		n++
+		if n%flushEvery == 0 {
+			if err := bw.Flush(); err != nil {
+				return err
+			}
+			if err := zw.Flush(); err != nil {
+				return err
+			}
+			flush(w)
+		}
+	}
+
+	bw.WriteString(`</sheetData></worksheet>`)
+	if err := bw.Flush(); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// writeXLSXRow escribe row sin WithFormulaEscaping: Excel nunca evalúa
+// las celdas de texto en línea, así que la comilla quedaría visible.
+func writeXLSXRow(bw *bufio.Writer, row []string) {
+	bw.WriteString("<row>")
+	for _, v := range row {
+		bw.WriteString(`<c t="inlineStr"><is><t xml:space="preserve">`)
+		bw.WriteString(xmlEscape(v))
+		bw.WriteString("</t></is></c>")
+	}
+	bw.WriteString("</row>")
+}
+
+// checkSheetName aplica las reglas de Excel para nombres de hoja; un
+// nombre inválido hace que Excel rechace el archivo completo.
+func checkSheetName(name string) error {
+	if name == "" || utf8.RuneCountInString(name) > 31 || strings.ContainsAny(name, `[]:*?/\`) ||
+		strings.HasPrefix(name, "'") || strings.HasSuffix(name, "'") {
+		return fmt.Errorf("render: nombre de hoja inválido %q: hasta 31 caracteres, sin []:*?/\\ ni comillas en los extremos", name)
+	}
+	return nil
+}
+
+// xmlEscape escapa el texto y descarta los caracteres que XML 1.0 no admite,
+// que de otro modo dejarían el archivo ilegible para Excel.
+func xmlEscape(s string) string {
+	var sb strings.Builder
+	clean := strings.Map(func(r rune) rune {
+		if r == '\t' || r == '\n' || r == '\r' ||
+			(r >= 0x20 && r <= 0xD7FF) || (r >= 0xE000 && r <= 0xFFFD) || r >= 0x10000 && r <= utf8.MaxRune {
+			return r
+		}
+		return -1
+	}, s)
+	_ = xml.EscapeText(&sb, []byte(clean))
+	return sb.String()
+}
+
+const xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`</Types>`
+
+const xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+	`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="{{sheet}}" sheetId="1" r:id="rId1"/></sheets></workbook>`
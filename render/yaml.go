@@ -0,0 +1,261 @@
+package render
+
+import (
+	"bytes"
+	"cmp"
+	"encoding"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// encodeYAML implementa el subconjunto de YAML necesario para respuestas:
+// mapas y structs como mappings en bloque, slices como secuencias y
+// escalares con comillas cuando el texto plano sería ambiguo. Los campos de
+// struct usan la etiqueta yaml, luego json, y si no el nombre en minúsculas.
+func encodeYAML(buf *bytes.Buffer, v any) error {
+	rv := reflect.ValueOf(v)
+	if isScalar(rv) {
+		if err := yamlScalar(buf, rv); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		return nil
+	}
+	return yamlNode(buf, rv, 0)
+}
+
+var (
+	timeType      = reflect.TypeFor[time.Time]()
+	marshalerType = reflect.TypeFor[encoding.TextMarshaler]()
+)
+
+func deref(v reflect.Value) reflect.Value {
+	for v.IsValid() && (v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface) {
+		if v.IsNil() {
+			return reflect.Value{}
+		}
+		v = v.Elem()
+	}
+	return v
+}
+
+func isScalar(v reflect.Value) bool {
+	v = deref(v)
+	if !v.IsValid() || v.Type() == timeType || v.Type().Implements(marshalerType) {
+		return true
+	}
+	switch v.Kind() {
+	case reflect.Map, reflect.Struct:
+		return false
+	case reflect.Slice, reflect.Array:
+		return v.Type().Elem().Kind() == reflect.Uint8
+	}
+	return true
+}
+
+type yamlField struct {
+	key   string
+	value reflect.Value
+}
+
+func yamlFields(v reflect.Value) ([]yamlField, error) {
+	var out []yamlField
+	switch v.Kind() {
+	case reflect.Map:
+		for _, k := range v.MapKeys() {
+			out = append(out, yamlField{key: fmt.Sprint(k.Interface()), value: v.MapIndex(k)})
+		}
+		slices.SortFunc(out, func(a, b yamlField) int { return cmp.Compare(a.key, b.key) })
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			f := t.Field(i)
+			if !f.IsExported() {
+				continue
+			}
+			name, omitempty := fieldName(f)
+			if name == "-" {
+				continue
+			}
+			fv := v.Field(i)
+			if omitempty && fv.IsZero() {
+				continue
+			}
+			out = append(out, yamlField{key: name, value: fv})
+		}
+	default:
+		return nil, fmt.Errorf("render: yaml: tipo no soportado %s", v.Type())
+	}
+	return out, nil
+}
+
+func fieldName(f reflect.StructField) (string, bool) {
+	for _, tag := range []string{"yaml", "json"} {
+		if v, ok := f.Tag.Lookup(tag); ok {
+			name, opts, _ := strings.Cut(v, ",")
+			omit := strings.Contains(opts, "omitempty")
+			if name == "" {
+				name = strings.ToLower(f.Name)
+			}
+			return name, omit
+		}
+	}
+	return strings.ToLower(f.Name), false
+}
+
+func yamlNode(buf *bytes.Buffer, v reflect.Value, indent int) error {
+	v = deref(v)
+	pad := strings.Repeat("  ", indent)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			buf.WriteString(pad + "[]\n")
+			return nil
+		}
+		for i := range v.Len() {
+			buf.WriteString(pad + "-")
+			if err := yamlValue(buf, v.Index(i), indent+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	fields, err := yamlFields(v)
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		buf.WriteString(pad + "{}\n")
+		return nil
+	}
+	for _, f := range fields {
+		buf.WriteString(pad)
+		if err := yamlString(buf, f.key); err != nil {
+			return err
+		}
+		buf.WriteByte(':')
+		if err := yamlValue(buf, f.value, indent+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// yamlValue escribe el valor que sigue a "key:" o "-": los escalares en la
+// misma línea y las colecciones en un bloque indentado.
+func yamlValue(buf *bytes.Buffer, v reflect.Value, indent int) error {
+	if isScalar(v) {
+		buf.WriteByte(' ')
+		if err := yamlScalar(buf, v); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		return nil
+	}
+	d := deref(v)
+	if (d.Kind() == reflect.Slice || d.Kind() == reflect.Array || d.Kind() == reflect.Map) && d.Len() == 0 {
+		if d.Kind() == reflect.Map {
+			buf.WriteString(" {}\n")
+		} else {
+			buf.WriteString(" []\n")
+		}
+		return nil
+	}
+	buf.WriteByte('\n')
+	return yamlNode(buf, d, indent)
+}
+
+func yamlScalar(buf *bytes.Buffer, v reflect.Value) error {
+	v = deref(v)
+	if !v.IsValid() {
+		buf.WriteString("null")
+		return nil
+	}
+	if v.Type() == timeType {
+		buf.WriteString(v.Interface().(time.Time).Format(time.RFC3339Nano))
+		return nil
+	}
+	if v.Type().Implements(marshalerType) {
+		text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		if err != nil {
+			return err
+		}
+		return yamlString(buf, string(text))
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		buf.WriteString(strconv.FormatBool(v.Bool()))
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		buf.WriteString(strconv.FormatInt(v.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		buf.WriteString(strconv.FormatUint(v.Uint(), 10))
+	case reflect.Float32, reflect.Float64:
+		buf.WriteString(strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()))
+	case reflect.String:
+		return yamlString(buf, v.String())
+	case reflect.Slice, reflect.Array:
+		buf.WriteString("!!binary ")
+		buf.WriteString(strconv.Quote(fmt.Sprintf("%x", v.Bytes())))
+	default:
+		return fmt.Errorf("render: yaml: tipo no soportado %s", v.Type())
+	}
+	return nil
+}
+
+// yamlString escribe s en texto plano si no hay ambigüedad, y entre
+// comillas dobles (con escapes compatibles con YAML) en caso contrario.
+func yamlString(buf *bytes.Buffer, s string) error {
+	if needsQuote(s) {
+		buf.WriteString(strconv.Quote(s))
+	} else {
+		buf.WriteString(s)
+	}
+	return nil
+}
+
+// yamlSpecial son los escalares que YAML 1.1 o 1.2 leen como null,
+// booleano, número especial o clave de merge.
+var yamlSpecial = map[string]bool{
+	"null": true, "~": true, "true": true, "false": true,
+	"yes": true, "no": true, "on": true, "off": true, "y": true, "n": true,
+	".inf": true, "+.inf": true, "-.inf": true, ".nan": true, "<<": true, "=": true,
+}
+
+// yamlImplicit reconoce números sexagesimales de YAML 1.1 ("1:30") y
+// fechas, que se leen como números o timestamps.
+var yamlImplicit = regexp.MustCompile(`^[-+]?[0-9][0-9_]*(:[0-5]?[0-9])+(\.[0-9_]*)?$|^[0-9]{4}-[0-9]{1,2}-[0-9]{1,2}([Tt ]|$)`)
+
+// needsQuote reporta si s cambiaría de tipo o de valor al leerse como
+// escalar plano.
+func needsQuote(s string) bool {
+	if s == "" || strings.TrimSpace(s) != s {
+		return true
+	}
+	if yamlSpecial[strings.ToLower(s)] {
+		return true
+	}
+	if _, err := strconv.ParseFloat(s, 64); err == nil {
+		return true
+	}
+	// ParseInt con base 0 cubre 0x1F, 0o17, 0b101 y separadores "_".
+	if _, err := strconv.ParseInt(s, 0, 64); err == nil {
+		return true
+	}
+	if yamlImplicit.MatchString(s) {
+		return true
+	}
+	if strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`") {
+		return true
+	}
+	for _, r := range s {
+		if r < 0x20 || r == 0x7f {
+			return true
+		}
+	}
+	return strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":")
+}
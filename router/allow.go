@@ -0,0 +1,29 @@
+package router
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// SetAllow escribe el header Allow con los métodos registrados para una
+// ruta. HEAD se agrega si existe GET, y OPTIONS siempre, ya que los
+// adapters lo responden aunque no haya un handler explícito. Los adapters
+// lo usan antes de invocar el handler de MethodNotAllowed.
+func SetAllow(h http.Header, methods []string) {
+	set := make([]string, 0, len(methods)+2)
+	for _, m := range methods {
+		m = strings.ToUpper(m)
+		if !slices.Contains(set, m) {
+			set = append(set, m)
+		}
+	}
+	if slices.Contains(set, http.MethodGet) && !slices.Contains(set, http.MethodHead) {
+		set = append(set, http.MethodHead)
+	}
+	if !slices.Contains(set, http.MethodOptions) {
+		set = append(set, http.MethodOptions)
+	}
+	slices.Sort(set)
+	h.Set("Allow", strings.Join(set, ", "))
+}
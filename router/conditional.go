@@ -0,0 +1,88 @@
+package router
+
+import (
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Conditional evalúa las precondiciones de r (RFC 9110, sección 13)
+// contra los validadores actuales del recurso y escribe ETag y
+// Last-Modified. Si la petición puede resolverse sin cuerpo responde 304
+// (GET/HEAD) o 412 y retorna false; si retorna true el handler debe
+// continuar. Un etag vacío o lastModified cero omiten ese validador; etag
+// debe incluir las comillas, p. ej. `"v42"` o `W/"v42"`.
+func Conditional(w http.ResponseWriter, r *http.Request, lastModified time.Time, etag string) bool {
+	h := w.Header()
+	if etag != "" {
+		h.Set("ETag", etag)
+	}
+	if !lastModified.IsZero() {
+		h.Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	safe := r.Method == http.MethodGet || r.Method == http.MethodHead
+
+	if im := r.Header.Get("If-Match"); im != "" {
+		if !matchETag(im, etag, true) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return false
+		}
+	} else if ius := r.Header.Get("If-Unmodified-Since"); ius != "" && !lastModified.IsZero() {
+		if t, err := http.ParseTime(ius); err == nil && lastModified.Truncate(time.Second).After(t) {
+			w.WriteHeader(http.StatusPreconditionFailed)
+			return false
+		}
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if matchETag(inm, etag, false) {
+			if safe {
+				notModified(w)
+			} else {
+				w.WriteHeader(http.StatusPreconditionFailed)
+			}
+			return false
+		}
+		return true
+	}
+	if ims := r.Header.Get("If-Modified-Since"); ims != "" && safe && !lastModified.IsZero() {
+		if t, err := http.ParseTime(ims); err == nil && !lastModified.Truncate(time.Second).After(t) {
+			notModified(w)
+			return false
+		}
+	}
+	return true
+}
+
+// notModified responde 304 quitando los headers de representación que no
+// corresponden sin cuerpo.
+func notModified(w http.ResponseWriter) {
+	h := w.Header()
+	h.Del("Content-Type")
+	h.Del("Content-Length")
+	h.Del("Content-Encoding")
+	w.WriteHeader(http.StatusNotModified)
+}
+
+// matchETag reporta si etag figura en la lista del header. "*" coincide
+// con cualquier recurso existente. If-Match usa comparación fuerte (los
+// ETag débiles nunca coinciden) e If-None-Match, débil.
+func matchETag(header, etag string, strong bool) bool {
+	if strings.TrimSpace(header) == "*" {
+		return etag != ""
+	}
+	if etag == "" || (strong && strings.HasPrefix(etag, "W/")) {
+		return false
+	}
+	want := strings.TrimPrefix(etag, "W/")
+	for _, c := range strings.Split(header, ",") {
+		c = strings.TrimSpace(c)
+		if strong && strings.HasPrefix(c, "W/") {
+			continue
+		}
+		if strings.TrimPrefix(c, "W/") == want {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,81 @@
+package router
+
+import (
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// Config agrupa la configuración de servidor que cada adapter traslada a
+// su motor (http.Server, fiber.Config, el servidor de echo, ...). Los
+// valores cero conservan los defaults de la librería subyacente.
+type Config struct {
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	MaxHeaderBytes    int
+
+	// FallbackUpstream, si no está vacío, es la URL del backend legado al
+	// que se envían las peticiones que no coinciden con ninguna ruta. Se
+	// aplica con WithFallback.
+	FallbackUpstream string
+
+	// BasePath antepone un prefijo a todas las rutas registradas, p. ej.
+	// "/service-a" detrás de un ingress. Si está vacío se usa la variable
+	// de entorno BasePathEnv.
+	BasePath string
+
+	// Warmup son peticiones que se despachan en proceso antes de declarar
+	// lista la aplicación. Ver WarmUp.
+	Warmup []WarmupRequest
+}
+
+// BasePathEnv es la variable de entorno que define el prefijo base cuando
+// Config.BasePath está vacío.
+const BasePathEnv = "TRANSWARP_BASE_PATH"
+
+// ResolveBasePath retorna el prefijo base normalizado: con "/" inicial,
+// sin "/" final, y vacío si no hay prefijo.
+func (c Config) ResolveBasePath() string {
+	base := c.BasePath
+	if base == "" {
+		base = os.Getenv(BasePathEnv)
+	}
+	base = strings.Trim(strings.TrimSpace(base), "/")
+	if base == "" {
+		return ""
+	}
+	return "/" + base
+}
+
+// WithBasePath retorna el Router sobre el que la aplicación debe registrar
+// sus rutas: un grupo bajo el prefijo base de c, o r mismo si no hay
+// prefijo. Así el mismo binario funciona en "/" y bajo un ingress.
+func WithBasePath(r Router, c Config) Router {
+	if base := c.ResolveBasePath(); base != "" {
+		return r.Group(base)
+	}
+	return r
+}
+
+// ApplyTo copia a srv los valores definidos en c. La usan los adapters que
+// sirven mediante net/http.
+func (c Config) ApplyTo(srv *http.Server) {
+	if c.ReadTimeout > 0 {
+		srv.ReadTimeout = c.ReadTimeout
+	}
+	if c.ReadHeaderTimeout > 0 {
+		srv.ReadHeaderTimeout = c.ReadHeaderTimeout
+	}
+	if c.WriteTimeout > 0 {
+		srv.WriteTimeout = c.WriteTimeout
+	}
+	if c.IdleTimeout > 0 {
+		srv.IdleTimeout = c.IdleTimeout
+	}
+	if c.MaxHeaderBytes > 0 {
+		srv.MaxHeaderBytes = c.MaxHeaderBytes
+	}
+}
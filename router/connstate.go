@@ -0,0 +1,60 @@
+package router
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// ConnStats lleva la cuenta de conexiones por estado. Su método Track se
+// registra con Router.ConnState y Snapshot alimenta los gauges de métricas.
+type ConnStats struct {
+	states sync.Map // net.Conn -> http.ConnState
+	counts [5]atomic.Int64
+	total  atomic.Int64
+}
+
+// ConnSnapshot es una lectura puntual de ConnStats.
+type ConnSnapshot struct {
+	New, Active, Idle int64
+	// Hijacked es acumulativo: el servidor deja de seguir esas conexiones.
+	Hijacked int64
+	// Accepted es el total de conexiones aceptadas desde el arranque.
+	Accepted int64
+}
+
+// Open retorna las conexiones abiertas en cualquier estado.
+func (s ConnSnapshot) Open() int64 {
+	return s.New + s.Active + s.Idle
+}
+
+// Track registra la transición de c a state.
+func (s *ConnStats) Track(c net.Conn, state http.ConnState) {
+	if state == http.StateNew {
+		s.total.Add(1)
+	}
+	if prev, ok := s.states.Load(c); ok {
+		s.counts[prev.(http.ConnState)].Add(-1)
+	}
+	if state == http.StateClosed || state == http.StateHijacked {
+		s.states.Delete(c)
+		if state == http.StateHijacked {
+			s.counts[state].Add(1)
+		}
+		return
+	}
+	s.states.Store(c, state)
+	s.counts[state].Add(1)
+}
+
+// Snapshot retorna los contadores actuales.
+func (s *ConnStats) Snapshot() ConnSnapshot {
+	return ConnSnapshot{
+		New:      s.counts[http.StateNew].Load(),
+		Active:   s.counts[http.StateActive].Load(),
+		Idle:     s.counts[http.StateIdle].Load(),
+		Hijacked: s.counts[http.StateHijacked].Load(),
+		Accepted: s.total.Load(),
+	}
+}
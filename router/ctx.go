@@ -0,0 +1,91 @@
+package router
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+)
+
+// Ctx agrupa la petición, el writer y el Router en una API al estilo de
+// Gin, Echo o Fiber, para quienes migran desde esos frameworks. Es
+// opcional: los handlers estándar siguen funcionando igual.
+type Ctx struct {
+	Writer  http.ResponseWriter
+	Request *http.Request
+	router  Router
+}
+
+// CtxHandlerFunc es un handler que recibe un Ctx y puede retornar un error.
+type CtxHandlerFunc func(c *Ctx) error
+
+// Handler adapta fn a http.HandlerFunc para registrarlo en rt. Los errores
+// que retorna fn se manejan como en Wrap.
+func Handler(rt Router, fn CtxHandlerFunc) http.HandlerFunc {
+	return Wrap(func(w http.ResponseWriter, r *http.Request) error {
+		return fn(&Ctx{Writer: w, Request: r, router: rt})
+	})
+}
+
+// Param retorna el parámetro de ruta key.
+func (c *Ctx) Param(key string) string {
+	return c.router.Param(c.Request, key)
+}
+
+// Query retorna el parámetro de query key.
+func (c *Ctx) Query(key string) string {
+	return c.Request.URL.Query().Get(key)
+}
+
+// QueryInt retorna el parámetro de query key como entero, o def si no
+// está presente. Un valor no numérico produce un HTTPError 400.
+func (c *Ctx) QueryInt(key string, def int) (int, error) {
+	v := c.Query(key)
+	if v == "" {
+		return def, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def, &HTTPError{Code: http.StatusBadRequest, Message: "parámetro " + key + " inválido", Err: err}
+	}
+	return n, nil
+}
+
+// BindJSON decodifica el cuerpo JSON en dst. Un cuerpo inválido produce
+// un HTTPError 400.
+func (c *Ctx) BindJSON(dst any) error {
+	if err := json.NewDecoder(c.Request.Body).Decode(dst); err != nil {
+		return &HTTPError{Code: http.StatusBadRequest, Message: "JSON inválido", Err: err}
+	}
+	return nil
+}
+
+// JSON responde v como JSON con status.
+func (c *Ctx) JSON(status int, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	c.Writer.Header().Set("Content-Type", "application/json; charset=utf-8")
+	c.Writer.WriteHeader(status)
+	_, err = c.Writer.Write(data)
+	return err
+}
+
+// String responde s como texto plano con status.
+func (c *Ctx) String(status int, s string) error {
+	c.Writer.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	c.Writer.WriteHeader(status)
+	_, err := c.Writer.Write([]byte(s))
+	return err
+}
+
+// NoContent responde 204 sin cuerpo.
+func (c *Ctx) NoContent() error {
+	c.Writer.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// Error responde err con el ErrorHandler vigente para la petición.
+func (c *Ctx) Error(err error) {
+	HandleError(c.Writer, c.Request, err)
+}
@@ -0,0 +1,101 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// Dispatch enruta una petición sintética a través de h (normalmente un
+// Router, con toda su cadena de middlewares) sin pasar por la red, y
+// retorna la respuesta capturada. Sirve para componer endpoints internos:
+// peticiones batch, tareas programadas o pruebas.
+func Dispatch(ctx context.Context, h http.Handler, method, path string, body io.Reader) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	return DispatchRequest(h, req), nil
+}
+
+// DispatchRequest es como Dispatch pero recibe una petición ya construida,
+// para cuando se necesitan headers o cookies propios.
+func DispatchRequest(h http.Handler, req *http.Request) *http.Response {
+	// Como en el servidor, el cuerpo de una petición entrante nunca es nil.
+	if req.Body == nil {
+		req.Body = http.NoBody
+	}
+	if req.RequestURI == "" {
+		req.RequestURI = req.URL.RequestURI()
+	}
+	if req.RemoteAddr == "" {
+		req.RemoteAddr = "127.0.0.1:0"
+	}
+	if req.Host == "" {
+		req.Host = "localhost"
+	}
+
+	rec := &recorder{header: http.Header{}}
+	h.ServeHTTP(rec, req)
+	return rec.result(req)
+}
+
+// recorder captura la respuesta de DispatchRequest en memoria. Reemplaza a
+// httptest.ResponseRecorder para no arrastrar ese paquete a los binarios.
+type recorder struct {
+	header      http.Header
+	snapshot    http.Header
+	status      int
+	body        bytes.Buffer
+	wroteHeader bool
+}
+
+func (rw *recorder) Header() http.Header { return rw.header }
+
+func (rw *recorder) WriteHeader(status int) {
+	if rw.wroteHeader {
+		return
+	}
+	rw.wroteHeader = true
+	rw.status = status
+	rw.snapshot = rw.header.Clone()
+}
+
+func (rw *recorder) Write(b []byte) (int, error) {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	return rw.body.Write(b)
+}
+
+// Flush no hace nada: todo queda en memoria hasta que el handler retorna.
+func (rw *recorder) Flush() {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+}
+
+func (rw *recorder) result(req *http.Request) *http.Response {
+	if !rw.wroteHeader {
+		rw.WriteHeader(http.StatusOK)
+	}
+	// Como en un servidor real, los headers son los vigentes al enviar el
+	// status; si falta Content-Type se detecta del cuerpo.
+	h := rw.snapshot
+	if _, ok := h["Content-Type"]; !ok && rw.body.Len() > 0 {
+		h.Set("Content-Type", http.DetectContentType(rw.body.Bytes()))
+	}
+	return &http.Response{
+		Status:        strconv.Itoa(rw.status) + " " + http.StatusText(rw.status),
+		StatusCode:    rw.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        h,
+		Body:          io.NopCloser(bytes.NewReader(rw.body.Bytes())),
+		ContentLength: int64(rw.body.Len()),
+		Request:       req,
+	}
+}
@@ -0,0 +1,79 @@
+package router
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestDispatch(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /texto", func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, "hola")
+		// Los headers posteriores al status no llegan al cliente.
+		w.Header().Set("X-Tarde", "1")
+	})
+	mux.HandleFunc("POST /eco", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		io.Copy(w, r.Body)
+	})
+	mux.HandleFunc("GET /vacio", func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc("CONNECT /tunel", func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusAccepted) })
+	mux.HandleFunc("TRACE /traza", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "message/http")
+		io.WriteString(w, r.Method+" "+r.RequestURI)
+	})
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantType   string
+		wantBody   string
+	}{
+		{"texto sin Content-Type", http.MethodGet, "/texto", "", http.StatusOK, "text/plain; charset=utf-8", "hola"},
+		{"eco", http.MethodPost, "/eco", `{"a":1}`, http.StatusCreated, "application/json", `{"a":1}`},
+		{"sin escribir", http.MethodGet, "/vacio", "", http.StatusOK, "", ""},
+		{"CONNECT", http.MethodConnect, "/tunel", "", http.StatusAccepted, "", ""},
+		{"TRACE", http.MethodTrace, "/traza?x=1", "", http.StatusOK, "message/http", "TRACE /traza?x=1"},
+		{"inexistente", http.MethodGet, "/nada", "", http.StatusNotFound, "text/plain; charset=utf-8", "404 page not found\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := Dispatch(context.Background(), mux, tt.method, tt.path, strings.NewReader(tt.body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, se esperaba %d", resp.StatusCode, tt.wantStatus)
+			}
+			if got := resp.Header.Get("Content-Type"); got != tt.wantType {
+				t.Errorf("Content-Type = %q, se esperaba %q", got, tt.wantType)
+			}
+			if string(body) != tt.wantBody || resp.ContentLength != int64(len(tt.wantBody)) {
+				t.Errorf("cuerpo = %q (%d), se esperaba %q", body, resp.ContentLength, tt.wantBody)
+			}
+			if resp.Header.Get("X-Tarde") != "" {
+				t.Error("un header escrito tras el status no debe capturarse")
+			}
+		})
+	}
+}
+
+func TestDispatchRequestDefaults(t *testing.T) {
+	var got *http.Request
+	h := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { got = r })
+	req, _ := http.NewRequest(http.MethodGet, "/a?b=1", nil)
+	req.Host = ""
+	DispatchRequest(h, req)
+	if got.Body == nil || got.RequestURI != "/a?b=1" || got.RemoteAddr == "" || got.Host != "localhost" {
+		t.Errorf("petición = body %v, RequestURI %q, RemoteAddr %q, Host %q", got.Body, got.RequestURI, got.RemoteAddr, got.Host)
+	}
+}
@@ -0,0 +1,143 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Severity clasifica un Diagnostic.
+type Severity int
+
+const (
+	// SeverityWarning es una configuración sospechosa que no impide
+	// arrancar.
+	SeverityWarning Severity = iota
+	// SeverityError es una configuración inválida.
+	SeverityError
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+	return "warning"
+}
+
+// Diagnostic es un hallazgo de Config.Validate o Doctor.
+type Diagnostic struct {
+	Severity Severity
+	// Code identifica el hallazgo de forma estable, p. ej. "write-timeout".
+	Code    string
+	Message string
+}
+
+func (d Diagnostic) String() string {
+	return d.Severity.String() + " [" + d.Code + "] " + d.Message
+}
+
+// Validate revisa c y retorna sus hallazgos. Los de severidad
+// SeverityError indican valores que el servidor no puede usar; Err los
+// combina en un error.
+func (c Config) Validate() []Diagnostic {
+	var ds []Diagnostic
+	add := func(s Severity, code, format string, args ...any) {
+		ds = append(ds, Diagnostic{Severity: s, Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for _, f := range []struct {
+		name string
+		v    int64
+	}{
+		{"ReadTimeout", int64(c.ReadTimeout)},
+		{"ReadHeaderTimeout", int64(c.ReadHeaderTimeout)},
+		{"WriteTimeout", int64(c.WriteTimeout)},
+		{"IdleTimeout", int64(c.IdleTimeout)},
+		{"MaxHeaderBytes", int64(c.MaxHeaderBytes)},
+	} {
+		if f.v < 0 {
+			add(SeverityError, "negative-value", "%s es negativo", f.name)
+		}
+	}
+	if c.ReadHeaderTimeout == 0 && c.ReadTimeout == 0 {
+		add(SeverityWarning, "slowloris", "sin ReadHeaderTimeout ni ReadTimeout el servidor queda expuesto a clientes lentos")
+	}
+	if c.ReadTimeout > 0 && c.ReadHeaderTimeout > c.ReadTimeout {
+		add(SeverityWarning, "read-header-timeout", "ReadHeaderTimeout (%s) supera ReadTimeout (%s) y no tiene efecto", c.ReadHeaderTimeout, c.ReadTimeout)
+	}
+	if c.WriteTimeout > 0 {
+		add(SeverityWarning, "write-timeout", "WriteTimeout (%s) corta las respuestas en streaming, SSE y descargas largas", c.WriteTimeout)
+	}
+	if c.FallbackUpstream != "" {
+		if _, err := parseUpstream(c.FallbackUpstream); err != nil {
+			add(SeverityError, "fallback-upstream", "FallbackUpstream %q no es una URL absoluta http o https", c.FallbackUpstream)
+		}
+	}
+	if strings.ContainsAny(c.ResolveBasePath(), " ?#") {
+		add(SeverityError, "base-path", "el prefijo base %q contiene caracteres inválidos", c.ResolveBasePath())
+	}
+	return ds
+}
+
+// Err combina los hallazgos de severidad SeverityError de ds, o retorna
+// nil.
+func Err(ds []Diagnostic) error {
+	var errs []error
+	for _, d := range ds {
+		if d.Severity == SeverityError {
+			errs = append(errs, errors.New(d.String()))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type doctorProbeKey struct{}
+
+// isDoctorProbe reporta si r es el sondeo de Doctor, que Fallback no debe
+// reenviar al upstream.
+func isDoctorProbe(r *http.Request) bool {
+	return r.Context().Value(doctorProbeKey{}) != nil
+}
+
+// Doctor revisa c junto con el Router ya configurado. Además de Validate,
+// detecta entre mods (lo que retorna Install) módulos con el mismo prefijo
+// y sondea r en proceso: una ruta inexistente debe responder 404, ya que
+// un catch-all que responde 200 oculta errores de los clientes. El sondeo
+// no llega al upstream de Fallback. Conviene llamarlo al arrancar y
+// registrar los hallazgos.
+//
+// Doctor no detecta timeouts sobre rutas de streaming, la falta de un
+// middleware de recover, compresión sobre SSE ni rutas superpuestas: la
+// interfaz Router no expone sus rutas ni la cadena de middlewares de cada
+// una, de modo que esos chequeos no pueden hacerse desde aquí.
+func Doctor(r Router, c Config, mods ...ModuleInfo) []Diagnostic {
+	ds := c.Validate()
+	add := func(s Severity, code, format string, args ...any) {
+		ds = append(ds, Diagnostic{Severity: s, Code: code, Message: fmt.Sprintf(format, args...)})
+	}
+
+	seen := map[string]string{}
+	for _, m := range mods {
+		if prev, ok := seen[m.Prefix]; ok {
+			add(SeverityError, "module-conflict", "los módulos %s y %s comparten el prefijo %s", prev, m.Name, m.Prefix)
+			continue
+		}
+		seen[m.Prefix] = m.Name
+	}
+
+	const probe = "/.transwarp-doctor/inexistente"
+	ctx := context.WithValue(context.Background(), doctorProbeKey{}, true)
+	resp, err := Dispatch(ctx, r, http.MethodGet, c.ResolveBasePath()+probe, nil)
+	switch {
+	case err != nil:
+		add(SeverityWarning, "probe", "no se pudo sondear el router: %v", err)
+	case resp.StatusCode == http.StatusOK:
+		resp.Body.Close()
+		add(SeverityWarning, "catch-all", "una ruta inexistente responde 200; revise SPA o comodines montados en la raíz")
+	default:
+		resp.Body.Close()
+	}
+	return ds
+}
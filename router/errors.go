@@ -0,0 +1,113 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// HandlerFuncE es la variante de http.HandlerFunc que retorna un error, de
+// modo que el mapeo de errores a respuestas ocurre en un solo lugar.
+type HandlerFuncE func(w http.ResponseWriter, r *http.Request) error
+
+// ErrorHandlerFunc responde un error retornado por un handler.
+type ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+
+// HTTPError es un error con status HTTP asociado.
+type HTTPError struct {
+	Code    int
+	Message string
+	Err     error
+}
+
+// NewHTTPError crea un HTTPError. Si message está vacío se usa el texto
+// estándar del status.
+func NewHTTPError(code int, message string) *HTTPError {
+	if message == "" {
+		message = http.StatusText(code)
+	}
+	return &HTTPError{Code: code, Message: message}
+}
+
+func (e *HTTPError) Error() string {
+	if e.Err != nil {
+		return fmt.Sprintf("%d %s: %v", e.Code, e.Message, e.Err)
+	}
+	return fmt.Sprintf("%d %s", e.Code, e.Message)
+}
+
+func (e *HTTPError) Unwrap() error { return e.Err }
+
+// DefaultErrorHandler responde un *HTTPError con su status y mensaje, y
+// cualquier otro error con 500 sin exponer su texto.
+func DefaultErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	var he *HTTPError
+	if errors.As(err, &he) {
+		http.Error(w, he.Message, he.Code)
+		return
+	}
+	http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+}
+
+type errorHandlerKey struct{}
+
+// WithErrorHandler es el middleware con el que los drivers implementan
+// Router.ErrorHandler: deja fn en el contexto para que Wrap y Ctx lo usen.
+func WithErrorHandler(fn ErrorHandlerFunc) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := context.WithValue(r.Context(), errorHandlerKey{}, fn)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// HandleError responde err con el ErrorHandler configurado para r o, si no
+// hay uno, con DefaultErrorHandler.
+func HandleError(w http.ResponseWriter, r *http.Request, err error) {
+	if fn, ok := r.Context().Value(errorHandlerKey{}).(ErrorHandlerFunc); ok && fn != nil {
+		fn(w, r, err)
+		return
+	}
+	DefaultErrorHandler(w, r, err)
+}
+
+// Wrap adapta fn a http.HandlerFunc. Si fn retorna un error y aún no
+// comenzó la respuesta, el error se entrega a HandleError; si ya la
+// comenzó, solo puede descartarse.
+func Wrap(fn HandlerFuncE) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sw := &startedWriter{ResponseWriter: w}
+		if err := fn(sw, r); err != nil && !sw.started {
+			HandleError(w, r, err)
+		}
+	}
+}
+
+// startedWriter registra si el handler ya comenzó la respuesta.
+type startedWriter struct {
+	http.ResponseWriter
+	started bool
+}
+
+func (w *startedWriter) WriteHeader(status int) {
+	w.started = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *startedWriter) Write(b []byte) (int, error) {
+	w.started = true
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush permite hacer streaming a través del wrapper.
+func (w *startedWriter) Flush() {
+	w.started = true
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap expone el writer original a http.ResponseController.
+func (w *startedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,98 @@
+package router
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+)
+
+// Fallback reenvía a un backend legado las peticiones que la aplicación
+// aún no atiende (patrón strangler fig) y cuenta cuántas recibe cada ruta,
+// de modo que se vea qué tráfico falta migrar. Se registra con
+// WithFallback a partir de Config.FallbackUpstream, o directamente con
+// Router.NotFound(fb.ServeHTTP).
+type Fallback struct {
+	proxy *httputil.ReverseProxy
+
+	mu    sync.Mutex
+	hits  map[string]int64
+	limit int
+}
+
+// maxFallbackPaths acota las rutas distintas que cuenta Fallback, para que
+// un escaneo de URLs no haga crecer la memoria sin límite.
+const maxFallbackPaths = 10000
+
+// NewFallback crea un Fallback hacia upstream, que debe ser una URL
+// absoluta http o https.
+func NewFallback(upstream string) (*Fallback, error) {
+	target, err := parseUpstream(upstream)
+	if err != nil {
+		return nil, err
+	}
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			pr.SetXForwarded()
+		},
+	}
+	return &Fallback{proxy: proxy, hits: make(map[string]int64), limit: maxFallbackPaths}, nil
+}
+
+func parseUpstream(upstream string) (*url.URL, error) {
+	u, err := url.Parse(upstream)
+	if err != nil {
+		return nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("router: el upstream %q debe ser una URL absoluta http o https", upstream)
+	}
+	return u, nil
+}
+
+// WithFallback registra en r un Fallback hacia c.FallbackUpstream como
+// handler de las rutas inexistentes y lo retorna para consultar sus Hits.
+// Si FallbackUpstream está vacío no modifica r y retorna nil.
+func WithFallback(r Router, c Config) (*Fallback, error) {
+	if c.FallbackUpstream == "" {
+		return nil, nil
+	}
+	fb, err := NewFallback(c.FallbackUpstream)
+	if err != nil {
+		return nil, err
+	}
+	r.NotFound(fb.ServeHTTP)
+	return fb, nil
+}
+
+// ServeHTTP reenvía r al upstream. El sondeo de Doctor responde 404 sin
+// salir del proceso.
+func (f *Fallback) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if isDoctorProbe(r) {
+		http.NotFound(w, r)
+		return
+	}
+	key := r.Method + " " + r.URL.Path
+	f.mu.Lock()
+	if _, ok := f.hits[key]; ok || len(f.hits) < f.limit {
+		f.hits[key]++
+	} else {
+		f.hits["(otras)"]++
+	}
+	f.mu.Unlock()
+
+	f.proxy.ServeHTTP(w, r)
+}
+
+// Hits retorna cuántas peticiones se reenviaron por método y ruta.
+func (f *Fallback) Hits() map[string]int64 {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	out := make(map[string]int64, len(f.hits))
+	for k, v := range f.hits {
+		out[k] = v
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+package router
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewFallback(t *testing.T) {
+	tests := []struct {
+		name     string
+		upstream string
+		wantErr  bool
+	}{
+		{"http", "http://legado:8080", false},
+		{"https con ruta", "https://legado.example.com/v1", false},
+		{"relativa", "/legado", true},
+		{"sin host", "http:///x", true},
+		{"sin esquema", "legado:8080", true},
+		{"otro esquema", "ftp://legado", true},
+		{"inválida", "http://[::1", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFallback(tt.upstream)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("err = %v, se esperaba error: %v", err, tt.wantErr)
+			}
+			ds := Config{ReadHeaderTimeout: 1, FallbackUpstream: tt.upstream}.Validate()
+			if (Err(ds) != nil) != tt.wantErr {
+				t.Errorf("Validate = %v, se esperaba error: %v", ds, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFallback(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Forwarded-Host-Seen", r.Header.Get("X-Forwarded-Host"))
+		io.WriteString(w, "legado "+r.Method+" "+r.URL.Path)
+	}))
+	defer upstream.Close()
+
+	fb, err := NewFallback(upstream.URL + "/base")
+	if err != nil {
+		t.Fatal(err)
+	}
+	fb.limit = 2
+
+	tests := []struct {
+		method, path string
+		want         string
+	}{
+		{http.MethodGet, "/a", "legado GET /base/a"},
+		{http.MethodGet, "/a", "legado GET /base/a"},
+		{http.MethodPost, "/a", "legado POST /base/a"},
+		{http.MethodGet, "/b", "legado GET /base/b"},
+		{http.MethodGet, "/c", "legado GET /base/c"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		fb.ServeHTTP(w, httptest.NewRequest(tt.method, tt.path, nil))
+		if w.Body.String() != tt.want || w.Header().Get("X-Forwarded-Host-Seen") != "example.com" {
+			t.Errorf("%s %s: cuerpo = %q, X-Forwarded-Host = %q", tt.method, tt.path, w.Body, w.Header().Get("X-Forwarded-Host-Seen"))
+		}
+	}
+
+	// Superado el límite de rutas, las nuevas se agrupan en "(otras)".
+	want := map[string]int64{"GET /a": 2, "POST /a": 1, "(otras)": 2}
+	got := fb.Hits()
+	if len(got) != len(want) {
+		t.Errorf("Hits = %v, se esperaba %v", got, want)
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("Hits[%q] = %d, se esperaba %d", k, got[k], v)
+		}
+	}
+
+	// El sondeo de Doctor no llega al upstream ni se cuenta.
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest(http.MethodGet, "/sondeo", nil)
+	fb.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), doctorProbeKey{}, true)))
+	if w.Code != http.StatusNotFound || fb.Hits()["(otras)"] != 2 {
+		t.Errorf("sondeo: status = %d, Hits = %v", w.Code, fb.Hits())
+	}
+}
+
+// notFoundRouter registra el handler que recibe NotFound.
+type notFoundRouter struct {
+	Router
+	notFound http.HandlerFunc
+}
+
+func (r *notFoundRouter) NotFound(h http.HandlerFunc) { r.notFound = h }
+
+func TestWithFallback(t *testing.T) {
+	r := &notFoundRouter{}
+	if fb, err := WithFallback(r, Config{}); fb != nil || err != nil || r.notFound != nil {
+		t.Errorf("sin FallbackUpstream: %v, %v; no debe registrar nada", fb, err)
+	}
+	if _, err := WithFallback(r, Config{FallbackUpstream: "legado"}); err == nil || r.notFound != nil {
+		t.Errorf("upstream inválido: err = %v", err)
+	}
+	fb, err := WithFallback(r, Config{FallbackUpstream: "http://legado"})
+	if err != nil || fb == nil || r.notFound == nil {
+		t.Fatalf("WithFallback = %v, %v", fb, err)
+	}
+}
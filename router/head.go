@@ -0,0 +1,26 @@
+package router
+
+import "net/http"
+
+// HeadHandler adapta el handler de un GET para responder HEAD: conserva
+// status y headers pero descarta el cuerpo. Los adapters con AutoHead
+// activo lo registran junto a cada GET; es necesario en los motores que,
+// a diferencia de net/http, no descartan el cuerpo por sí mismos.
+func HeadHandler(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		h(&headWriter{ResponseWriter: w}, r)
+	}
+}
+
+type headWriter struct {
+	http.ResponseWriter
+}
+
+func (w *headWriter) Write(b []byte) (int, error) {
+	return len(b), nil
+}
+
+// Unwrap expone el writer original a http.ResponseController.
+func (w *headWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
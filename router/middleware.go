@@ -1,7 +1,87 @@
 package router
 
-import "net/http"
+import (
+	"net/http"
+	"path"
+	"strings"
+)
 
 // Middleware define la firma estándar de Go para funciones de envoltura
 
 type Middleware func(http.Handler) http.Handler
+
+// Chain compone mws en un solo Middleware. El primero es el más externo:
+// Chain(a, b)(h) equivale a a(b(h)).
+func Chain(mws ...Middleware) Middleware {
+	return func(h http.Handler) http.Handler {
+		for i := len(mws) - 1; i >= 0; i-- {
+			h = mws[i](h)
+		}
+		return h
+	}
+}
+
+// Apply envuelve h con mws en el mismo orden que Chain. Los drivers lo usan
+// para aplicar los middlewares por ruta antes de registrar el handler.
+func Apply(h http.HandlerFunc, mws ...Middleware) http.HandlerFunc {
+	if len(mws) == 0 {
+		return h
+	}
+	return Chain(mws...)(h).ServeHTTP
+}
+
+// Skip retorna un Middleware que omite mw cuando skip(r) es verdadero,
+// entregando la petición directamente al handler. Sirve con Use para
+// excluir health checks, estáticos o prefijos sin duplicar el árbol de
+// rutas.
+func Skip(mw Middleware, skip func(*http.Request) bool) Middleware {
+	return func(next http.Handler) http.Handler {
+		wrapped := mw(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if skip(r) {
+				next.ServeHTTP(w, r)
+				return
+			}
+			wrapped.ServeHTTP(w, r)
+		})
+	}
+}
+
+// PathPrefix es un predicado para Skip que coincide si la ruta comienza con
+// alguno de los prefijos.
+func PathPrefix(prefixes ...string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		for _, p := range prefixes {
+			if strings.HasPrefix(r.URL.Path, p) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// PathIn es un predicado para Skip que coincide con rutas exactas.
+func PathIn(paths ...string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		for _, p := range paths {
+			if r.URL.Path == p {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// PathExt es un predicado para Skip que coincide por extensión de archivo,
+// por ejemplo PathExt(".css", ".js").
+func PathExt(exts ...string) func(*http.Request) bool {
+	return func(r *http.Request) bool {
+		ext := path.Ext(r.URL.Path)
+		for _, e := range exts {
+			if strings.EqualFold(ext, e) {
+				return true
+			}
+		}
+		return false
+	}
+}
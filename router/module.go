@@ -0,0 +1,88 @@
+package router
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Module agrupa las rutas de una funcionalidad. Install lo monta bajo su
+// propio prefijo.
+type Module interface {
+	Name() string
+	Register(r Router)
+}
+
+// Interfaces opcionales que un Module puede implementar para ajustar su
+// montaje y sus metadatos.
+type (
+	// ModulePrefix reemplaza el prefijo derivado del nombre.
+	ModulePrefix interface{ Prefix() string }
+	// ModuleMiddleware aplica middlewares solo a las rutas del módulo.
+	ModuleMiddleware interface{ Middleware() []Middleware }
+	// ModuleOwner declara el equipo responsable del módulo.
+	ModuleOwner interface{ Owner() string }
+	// ModuleTags declara los tags OpenAPI de las rutas del módulo.
+	ModuleTags interface{ Tags() []string }
+)
+
+// ModuleInfo describe un módulo instalado.
+type ModuleInfo struct {
+	Name   string
+	Prefix string
+	Owner  string
+	Tags   []string
+}
+
+// Install monta cada módulo en un Group propio de r, aplica sus
+// middlewares y registra sus rutas. El prefijo por defecto es el nombre en
+// kebab-case ("UserAccounts" → "/user-accounts") y los tags OpenAPI por
+// defecto, el nombre. Retorna la descripción de los módulos instalados, en
+// orden y con Prefix relativo a r, para pasarla a Doctor o publicarla.
+func Install(r Router, mods ...Module) []ModuleInfo {
+	infos := make([]ModuleInfo, 0, len(mods))
+	for _, m := range mods {
+		info := ModuleInfo{Name: m.Name(), Prefix: "/" + kebab(m.Name())}
+		if p, ok := m.(ModulePrefix); ok {
+			info.Prefix = p.Prefix()
+		}
+		if o, ok := m.(ModuleOwner); ok {
+			info.Owner = o.Owner()
+		}
+		info.Tags = []string{m.Name()}
+		if t, ok := m.(ModuleTags); ok {
+			info.Tags = t.Tags()
+		}
+
+		g := r.Group(info.Prefix)
+		if mw, ok := m.(ModuleMiddleware); ok {
+			for _, fn := range mw.Middleware() {
+				g.Use(fn)
+			}
+		}
+		m.Register(g)
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+func kebab(name string) string {
+	var b strings.Builder
+	prevLower := false
+	for _, r := range name {
+		switch {
+		case unicode.IsUpper(r):
+			if prevLower {
+				b.WriteByte('-')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			prevLower = false
+		case r == ' ' || r == '_':
+			b.WriteByte('-')
+			prevLower = false
+		default:
+			b.WriteRune(r)
+			prevLower = unicode.IsLower(r) || unicode.IsDigit(r)
+		}
+	}
+	return b.String()
+}
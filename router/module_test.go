@@ -0,0 +1,117 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+// groupRouter implementa Group, Use, GET y ServeHTTP sobre un
+// http.ServeMux compartido.
+type groupRouter struct {
+	Router
+	prefix string
+	mux    *http.ServeMux
+	mws    []Middleware
+}
+
+func newGroupRouter() *groupRouter { return &groupRouter{mux: http.NewServeMux()} }
+
+func (g *groupRouter) Group(prefix string) Router {
+	return &groupRouter{prefix: g.prefix + prefix, mux: g.mux, mws: append([]Middleware(nil), g.mws...)}
+}
+
+func (g *groupRouter) Use(mw Middleware) { g.mws = append(g.mws, mw) }
+
+func (g *groupRouter) GET(path string, h http.HandlerFunc, _ ...Middleware) {
+	var handler http.Handler = h
+	for i := len(g.mws) - 1; i >= 0; i-- {
+		handler = g.mws[i](handler)
+	}
+	g.mux.Handle("GET "+g.prefix+path, handler)
+}
+
+func (g *groupRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) { g.mux.ServeHTTP(w, r) }
+
+type plainModule struct{ name string }
+
+func (m plainModule) Name() string { return m.name }
+func (m plainModule) Register(r Router) {
+	r.GET("/", func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(m.name)) })
+}
+
+// fullModule implementa todas las interfaces opcionales.
+type fullModule struct{ plainModule }
+
+func (fullModule) Prefix() string { return "/v2/billing" }
+func (fullModule) Owner() string  { return "pagos" }
+func (fullModule) Tags() []string { return []string{"Billing", "Invoices"} }
+func (fullModule) Middleware() []Middleware {
+	return []Middleware{func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("X-Modulo", "billing")
+			next.ServeHTTP(w, r)
+		})
+	}}
+}
+
+func TestInstall(t *testing.T) {
+	r := newGroupRouter()
+	got := Install(r, plainModule{"UserAccounts"}, fullModule{plainModule{"Billing"}})
+	want := []ModuleInfo{
+		{Name: "UserAccounts", Prefix: "/user-accounts", Tags: []string{"UserAccounts"}},
+		{Name: "Billing", Prefix: "/v2/billing", Owner: "pagos", Tags: []string{"Billing", "Invoices"}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Install = %+v, se esperaba %+v", got, want)
+	}
+
+	tests := []struct {
+		path, body, header string
+	}{
+		{"/user-accounts/", "UserAccounts", ""},
+		{"/v2/billing/", "Billing", "billing"},
+	}
+	for _, tt := range tests {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+		if w.Body.String() != tt.body || w.Header().Get("X-Modulo") != tt.header {
+			t.Errorf("GET %s = %q (X-Modulo %q), se esperaba %q (%q)", tt.path, w.Body, w.Header().Get("X-Modulo"), tt.body, tt.header)
+		}
+	}
+
+	// Los middlewares del módulo no alcanzan al router padre.
+	if len(r.mws) != 0 {
+		t.Errorf("el router padre tiene %d middlewares, se esperaban 0", len(r.mws))
+	}
+}
+
+func TestKebab(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Users", "users"},
+		{"UserAccounts", "user-accounts"},
+		{"user_accounts", "user-accounts"},
+		{"Billing V2", "billing-v2"},
+		{"OAuth2Clients", "oauth2-clients"},
+		{"API", "api"},
+	}
+	for _, tt := range tests {
+		if got := kebab(tt.in); got != tt.want {
+			t.Errorf("kebab(%q) = %q, se esperaba %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDoctorModuleConflict(t *testing.T) {
+	mods := []ModuleInfo{{Name: "Users", Prefix: "/users"}, {Name: "Cuentas", Prefix: "/users"}, {Name: "Orders", Prefix: "/orders"}}
+	var conflicts []string
+	for _, d := range Doctor(newGroupRouter(), Config{ReadHeaderTimeout: 1}, mods...) {
+		if d.Code == "module-conflict" {
+			conflicts = append(conflicts, d.Message)
+		}
+	}
+	if want := []string{"los módulos Users y Cuentas comparten el prefijo /users"}; !reflect.DeepEqual(conflicts, want) {
+		t.Errorf("conflictos = %q, se esperaba %q", conflicts, want)
+	}
+}
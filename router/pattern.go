@@ -0,0 +1,25 @@
+package router
+
+import "strings"
+
+// CatchAll reconoce el comodín portable al final de un patrón: "*name" o
+// ":name*", como en "/docs/*path". Retorna el prefijo (con la barra final)
+// y el nombre del parámetro. Los adapters lo usan para traducir el patrón a
+// la sintaxis de su motor (*name en Gin, {name...} en net/http, /* en Chi,
+// * en Fiber) y para que Param(r, name) retorne el resto de la ruta.
+func CatchAll(pattern string) (prefix, name string, ok bool) {
+	i := strings.LastIndexByte(pattern, '/')
+	if i < 0 {
+		return "", "", false
+	}
+	last := pattern[i+1:]
+	switch {
+	case strings.HasPrefix(last, "*") && len(last) > 1:
+		name = last[1:]
+	case strings.HasPrefix(last, ":") && strings.HasSuffix(last, "*") && len(last) > 2:
+		name = last[1 : len(last)-1]
+	default:
+		return "", "", false
+	}
+	return pattern[:i+1], name, true
+}
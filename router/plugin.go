@@ -0,0 +1,96 @@
+package router
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// Plugin es una extensión empaquetada por terceros (proveedores de
+// observabilidad, equipos de plataforma, ...). Se registra en un init con
+// RegisterPlugin, como los drivers de database/sql, y se activa con
+// LoadPlugins.
+type Plugin interface {
+	Name() string
+	// Init configura el plugin antes de aplicar sus middlewares y rutas.
+	Init(cfg Config) error
+	// Middleware retorna los middlewares globales del plugin; puede ser
+	// nil.
+	Middleware() []Middleware
+	// Routes registra las rutas propias del plugin.
+	Routes(r Router)
+	// Shutdown libera los recursos del plugin.
+	Shutdown(ctx context.Context) error
+}
+
+var (
+	pluginsMu sync.RWMutex
+	plugins   = map[string]Plugin{}
+)
+
+// RegisterPlugin deja p disponible para LoadPlugins. Registrar dos veces
+// el mismo nombre provoca panic.
+func RegisterPlugin(p Plugin) {
+	pluginsMu.Lock()
+	defer pluginsMu.Unlock()
+	name := p.Name()
+	if _, dup := plugins[name]; dup {
+		panic("router: plugin " + name + " ya registrado")
+	}
+	plugins[name] = p
+}
+
+// Plugins retorna los nombres de los plugins registrados, ordenados.
+func Plugins() []string {
+	pluginsMu.RLock()
+	defer pluginsMu.RUnlock()
+	return slices.Sorted(maps.Keys(plugins))
+}
+
+// LoadPlugins inicializa los plugins indicados (todos si names está vacío,
+// en orden alfabético), aplica sus middlewares con r.Use y registra sus
+// rutas. Retorna una función que los apaga en orden inverso. Si un Init
+// falla, se apagan los ya inicializados y se retorna el error.
+func LoadPlugins(r Router, cfg Config, names ...string) (shutdown func(context.Context) error, err error) {
+	if len(names) == 0 {
+		names = Plugins()
+	}
+	pluginsMu.RLock()
+	selected := make([]Plugin, 0, len(names))
+	for _, n := range names {
+		p, ok := plugins[n]
+		if !ok {
+			pluginsMu.RUnlock()
+			return nil, fmt.Errorf("router: plugin %q no registrado", n)
+		}
+		selected = append(selected, p)
+	}
+	pluginsMu.RUnlock()
+
+	var loaded []Plugin
+	shutdown = func(ctx context.Context) error {
+		var errs []error
+		for _, p := range slices.Backward(loaded) {
+			if err := p.Shutdown(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("router: plugin %s: %w", p.Name(), err))
+			}
+		}
+		return errors.Join(errs...)
+	}
+	for _, p := range selected {
+		if err := p.Init(cfg); err != nil {
+			return nil, errors.Join(fmt.Errorf("router: plugin %s: %w", p.Name(), err), shutdown(context.Background()))
+		}
+		loaded = append(loaded, p)
+	}
+	for _, p := range loaded {
+		for _, mw := range p.Middleware() {
+			r.Use(mw)
+		}
+		p.Routes(r)
+	}
+	return shutdown, nil
+}
@@ -0,0 +1,35 @@
+package router
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/profe-ajedrez/transwarp/render"
+)
+
+// Redirect registra path en rt para cualquier método, redirigiendo a
+// target con code. Un code que no cumple render.IsRedirect provoca panic
+// al registrar.
+func Redirect(rt Router, path, target string, code int) {
+	if !render.IsRedirect(code) {
+		panic("router: código de redirección inválido " + strconv.Itoa(code))
+	}
+	rt.Any(path, func(w http.ResponseWriter, r *http.Request) {
+		_ = render.Redirect(w, r, withQuery(target, r), code)
+	})
+}
+
+// withQuery agrega a target la query string de r si target no trae una,
+// antes del fragmento si lo hay.
+func withQuery(target string, r *http.Request) string {
+	base, fragment, hasFragment := strings.Cut(target, "#")
+	if r.URL.RawQuery == "" || strings.Contains(base, "?") {
+		return target
+	}
+	base += "?" + r.URL.RawQuery
+	if hasFragment {
+		base += "#" + fragment
+	}
+	return base
+}
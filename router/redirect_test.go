@@ -0,0 +1,32 @@
+package router
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWithQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		target string
+		query  string
+		want   string
+	}{
+		{"sin query", "/nuevo", "", "/nuevo"},
+		{"agrega la query", "/nuevo", "a=1&b=2", "/nuevo?a=1&b=2"},
+		{"respeta la query del destino", "/nuevo?x=1", "a=1", "/nuevo?x=1"},
+		{"antes del fragmento", "/docs#intro", "a=1", "/docs?a=1#intro"},
+		{"fragmento sin query", "/docs#intro", "", "/docs#intro"},
+		{"destino con query y fragmento", "/docs?x=1#intro", "a=1", "/docs?x=1#intro"},
+		{"URL absoluta", "https://example.com/n#f", "a=1", "https://example.com/n?a=1#f"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/viejo?"+tt.query, nil)
+			if got := withQuery(tt.target, r); got != tt.want {
+				t.Errorf("withQuery = %q, se esperaba %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package router
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+)
+
+// registro global de middlewares con nombre y de qué rutas protegen.
+var (
+	namedMu     sync.RWMutex
+	named       = map[string]Middleware{}
+	routeGuards = map[string][]string{}
+)
+
+// RegisterMiddleware registra mw bajo name para referirlo por texto desde
+// tablas de rutas o archivos de configuración. Registrar dos veces el
+// mismo nombre es un error de programación y provoca panic.
+func RegisterMiddleware(name string, mw Middleware) {
+	namedMu.Lock()
+	defer namedMu.Unlock()
+	if _, dup := named[name]; dup {
+		panic("router: middleware " + name + " ya registrado")
+	}
+	named[name] = mw
+}
+
+// LookupMiddleware retorna el middleware registrado como name.
+func LookupMiddleware(name string) (Middleware, bool) {
+	namedMu.RLock()
+	defer namedMu.RUnlock()
+	mw, ok := named[name]
+	return mw, ok
+}
+
+// MiddlewareNames retorna los nombres registrados, ordenados.
+func MiddlewareNames() []string {
+	namedMu.RLock()
+	defer namedMu.RUnlock()
+	return slices.Sorted(maps.Keys(named))
+}
+
+// Named compone los middlewares registrados como names, en ese orden (el
+// primero es el más externo). Falla si alguno no existe.
+func Named(names ...string) (Middleware, error) {
+	namedMu.RLock()
+	defer namedMu.RUnlock()
+	mws := make([]Middleware, 0, len(names))
+	for _, n := range names {
+		mw, ok := named[n]
+		if !ok {
+			return nil, fmt.Errorf("router: middleware %q no registrado", n)
+		}
+		mws = append(mws, mw)
+	}
+	return Chain(mws...), nil
+}
+
+// Guard es como Named pero además anota que route queda protegida por
+// names, para consultarlo luego con Guards. route es una etiqueta libre,
+// por convención "METHOD /path".
+func Guard(route string, names ...string) (Middleware, error) {
+	mw, err := Named(names...)
+	if err != nil {
+		return nil, err
+	}
+	namedMu.Lock()
+	routeGuards[route] = append(routeGuards[route], names...)
+	namedMu.Unlock()
+	return mw, nil
+}
+
+// Guards retorna, por ruta, los middlewares con nombre aplicados mediante
+// Guard.
+func Guards() map[string][]string {
+	namedMu.RLock()
+	defer namedMu.RUnlock()
+	out := make(map[string][]string, len(routeGuards))
+	for k, v := range routeGuards {
+		out[k] = slices.Clone(v)
+	}
+	return out
+}
@@ -0,0 +1,75 @@
+package router
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/profe-ajedrez/transwarp/render"
+)
+
+// Retired registra endpoints retirados sobre un Router y cuenta el tráfico
+// residual que siguen recibiendo, para saber cuándo es seguro quitarlos.
+type Retired struct {
+	r      Router
+	sunset time.Time
+
+	mu   sync.Mutex
+	hits map[string]int64
+}
+
+// NewRetired crea un Retired sobre r. Si sunset no es cero, las respuestas
+// incluyen la cabecera Sunset (RFC 8594) con esa fecha.
+func NewRetired(r Router, sunset time.Time) *Retired {
+	return &Retired{r: r, sunset: sunset, hits: make(map[string]int64)}
+}
+
+// Gone registra path para cualquier método respondiendo 410 con message.
+func (rt *Retired) Gone(path, message string) {
+	if message == "" {
+		message = http.StatusText(http.StatusGone)
+	}
+	rt.r.Any(path, func(w http.ResponseWriter, r *http.Request) {
+		rt.track(path)
+		rt.setSunset(w)
+		http.Error(w, message, http.StatusGone)
+	})
+}
+
+// MovedPermanently registra path para cualquier método redirigiendo a
+// target. GET y HEAD reciben 301; el resto 308, que conserva método y
+// cuerpo. La query string original se conserva si target no trae una.
+func (rt *Retired) MovedPermanently(path, target string) {
+	rt.r.Any(path, func(w http.ResponseWriter, r *http.Request) {
+		rt.track(path)
+		rt.setSunset(w)
+		status := http.StatusPermanentRedirect
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			status = http.StatusMovedPermanently
+		}
+		_ = render.Redirect(w, r, withQuery(target, r), status)
+	})
+}
+
+// Hits retorna cuántas peticiones recibió cada ruta retirada.
+func (rt *Retired) Hits() map[string]int64 {
+	rt.mu.Lock()
+	defer rt.mu.Unlock()
+	out := make(map[string]int64, len(rt.hits))
+	for k, v := range rt.hits {
+		out[k] = v
+	}
+	return out
+}
+
+func (rt *Retired) track(path string) {
+	rt.mu.Lock()
+	rt.hits[path]++
+	rt.mu.Unlock()
+}
+
+func (rt *Retired) setSunset(w http.ResponseWriter) {
+	if !rt.sunset.IsZero() {
+		w.Header().Set("Sunset", rt.sunset.UTC().Format(http.TimeFormat))
+	}
+}
@@ -1,18 +1,38 @@
 package router
 
-import "net/http"
+import (
+	"context"
+	"io/fs"
+	"net"
+	"net/http"
+)
 
 type Router interface {
 	http.Handler
-	GET(path string, handler http.HandlerFunc)
-	POST(path string, handler http.HandlerFunc)
-	PUT(path string, handler http.HandlerFunc)
-	HEAD(path string, handler http.HandlerFunc)
-	DELETE(path string, handler http.HandlerFunc)
+	GET(path string, handler http.HandlerFunc, mws ...Middleware)
+	POST(path string, handler http.HandlerFunc, mws ...Middleware)
+	PUT(path string, handler http.HandlerFunc, mws ...Middleware)
+	HEAD(path string, handler http.HandlerFunc, mws ...Middleware)
+	DELETE(path string, handler http.HandlerFunc, mws ...Middleware)
+	CONNECT(path string, handler http.HandlerFunc, mws ...Middleware)
+	TRACE(path string, handler http.HandlerFunc, mws ...Middleware)
+	Any(path string, handler http.HandlerFunc, mws ...Middleware)
 	Use(mw Middleware)
+	NotFound(h http.HandlerFunc)
+	MethodNotAllowed(h http.HandlerFunc)
+	ErrorHandler(fn ErrorHandlerFunc)
+	AutoOptions(enabled bool)
+	AutoHead(enabled bool)
 	Param(r *http.Request, key string) string
 	Group(prefix string) Router
 	Serve(port string) error
+	ServeTLS(addr, certFile, keyFile string) error
+	ServeListener(l net.Listener) error
+	ServeContext(ctx context.Context, addr string) error
+	ConnState(fn func(net.Conn, http.ConnState))
 	Handle(pattern string, h http.Handler)
+	Mount(prefix string, h http.Handler)
 	HandleFunc(pattern string, h http.HandlerFunc)
+	StaticFS(prefix string, fsys fs.FS)
+	SPA(prefix string, fsys fs.FS, indexFile string)
 }
@@ -0,0 +1,42 @@
+package router
+
+import (
+	"io/fs"
+	"net/http"
+	"path"
+	"strings"
+)
+
+// SPAHandler sirve los archivos de fsys y responde indexFile para cualquier
+// ruta sin extensión que no exista, de modo que el ruteo del lado del
+// cliente funcione al recargar la página. Las rutas con extensión que no
+// existen responden 404 para no ocultar assets faltantes. Los adapters lo
+// montan como fallback del prefijo, después de las rutas registradas, y
+// le quitan el prefijo antes de invocarlo.
+func SPAHandler(fsys fs.FS, indexFile string) http.Handler {
+	files := http.FileServerFS(fsys)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		name := strings.TrimPrefix(path.Clean("/"+r.URL.Path), "/")
+		if name != "" && name != indexFile {
+			if info, err := fs.Stat(fsys, name); err == nil && !info.IsDir() {
+				files.ServeHTTP(w, r)
+				return
+			}
+			if path.Ext(name) != "" {
+				http.NotFound(w, r)
+				return
+			}
+		}
+
+		// El index no debe cachearse: referencia assets con hash que
+		// cambian en cada despliegue.
+		w.Header().Set("Cache-Control", "no-cache")
+		http.ServeFileFS(w, r, fsys, indexFile)
+	})
+}
@@ -0,0 +1,98 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+)
+
+// WarmupHeader marca las peticiones de calentamiento, para que métricas y
+// logs puedan excluirlas.
+const WarmupHeader = "X-Warmup"
+
+// WarmupRequest es una petición de calentamiento.
+type WarmupRequest struct {
+	// Method por defecto es GET.
+	Method string
+	Path   string
+	Header http.Header
+	Body   []byte
+	// Status es el status esperado; cero acepta cualquier 2xx o 3xx.
+	Status int
+}
+
+// WarmUp despacha en proceso las peticiones de c.Warmup sobre h, en orden,
+// para pagar antes del tráfico real los costos de primera vez (parseo de
+// plantillas, llenado de pools, primera conexión a la base de datos).
+// Retorna los fallos combinados; conviene no marcar la aplicación como
+// lista si hay error.
+func (c Config) WarmUp(ctx context.Context, h http.Handler) error {
+	var errs []error
+	for _, wr := range c.Warmup {
+		if err := ctx.Err(); err != nil {
+			return errors.Join(append(errs, err)...)
+		}
+		method := wr.Method
+		if method == "" {
+			method = http.MethodGet
+		}
+		req, err := http.NewRequestWithContext(ctx, method, wr.Path, bytes.NewReader(wr.Body))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for k, v := range wr.Header {
+			req.Header[k] = v
+		}
+		req.Header.Set(WarmupHeader, "1")
+
+		resp := DispatchRequest(h, req)
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		ok := resp.StatusCode >= 200 && resp.StatusCode < 400
+		if wr.Status != 0 {
+			ok = resp.StatusCode == wr.Status
+		}
+		if !ok {
+			errs = append(errs, fmt.Errorf("router: warm-up %s %s: status %d", method, wr.Path, resp.StatusCode))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Readiness es el estado de la probe de readiness. Arranca en "no lista".
+type Readiness struct {
+	ready atomic.Bool
+}
+
+// SetReady cambia el estado.
+func (rd *Readiness) SetReady(ready bool) { rd.ready.Store(ready) }
+
+// Ready reporta el estado actual.
+func (rd *Readiness) Ready() bool { return rd.ready.Load() }
+
+// Handler responde 200 si está lista y 503 en caso contrario.
+func (rd *Readiness) Handler() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "no-store")
+		if !rd.Ready() {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// WarmUpAndReady ejecuta c.WarmUp sobre h y, si no hay errores, marca rd
+// como lista.
+func (c Config) WarmUpAndReady(ctx context.Context, h http.Handler, rd *Readiness) error {
+	if err := c.WarmUp(ctx, h); err != nil {
+		return err
+	}
+	rd.SetReady(true)
+	return nil
+}
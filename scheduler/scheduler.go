@@ -0,0 +1,210 @@
+// Package scheduler invoca handlers internos según expresiones cron,
+// atravesando el mismo http.Handler que atiende el tráfico real. Así las
+// tareas periódicas reutilizan el ruteo, la autenticación y los middlewares
+// de la aplicación.
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/profe-ajedrez/transwarp/internal/workerpool"
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// JobHeader es el header que identifica las peticiones generadas por el
+// scheduler; su valor es el nombre del job.
+const JobHeader = "X-Scheduled-Job"
+
+// Locker coordina la ejecución entre réplicas. TryLock retorna ok=false si
+// otra réplica ya tomó el lock para esa ejecución. El Scheduler no invoca
+// release: el lock debe expirar con su TTL, de modo que una réplica más
+// lenta que llega al mismo minuto no repita el job.
+type Locker interface {
+	TryLock(ctx context.Context, key string, ttl time.Duration) (release func(), ok bool, err error)
+}
+
+// Job describe una invocación periódica.
+type Job struct {
+	Name     string
+	Schedule Schedule
+	Method   string
+	Path     string
+	Body     []byte
+	Header   http.Header
+	// Timeout limita la duración de cada ejecución; cero significa sin límite.
+	Timeout time.Duration
+}
+
+// Result describe el resultado de una ejecución.
+type Result struct {
+	Job      string
+	Start    time.Time
+	Duration time.Duration
+	Status   int
+	Err      error
+}
+
+// Scheduler ejecuta Jobs sobre un http.Handler.
+type Scheduler struct {
+	handler http.Handler
+
+	// Locker es opcional; sin él cada réplica ejecuta todos los jobs.
+	Locker Locker
+	// OnResult recibe el resultado de cada ejecución; puede ser nil.
+	OnResult func(Result)
+	// Workers limita las ejecuciones simultáneas; por defecto 8.
+	Workers int
+
+	mu   sync.Mutex
+	jobs []*entry
+}
+
+type entry struct {
+	job  Job
+	next time.Time
+}
+
+// New crea un Scheduler que invoca h en proceso.
+func New(h http.Handler) *Scheduler {
+	return &Scheduler{handler: h}
+}
+
+// Add registra un job que ejecuta method path según spec.
+func (s *Scheduler) Add(name, spec, method, path string) error {
+	sched, err := Parse(spec)
+	if err != nil {
+		return err
+	}
+	return s.AddJob(Job{Name: name, Schedule: sched, Method: method, Path: path})
+}
+
+// AddJob registra un Job ya construido.
+func (s *Scheduler) AddJob(job Job) error {
+	if job.Name == "" {
+		return errors.New("scheduler: el job requiere un nombre")
+	}
+	if job.Method == "" {
+		job.Method = http.MethodPost
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, e := range s.jobs {
+		if e.job.Name == job.Name {
+			return errors.New("scheduler: job duplicado " + job.Name)
+		}
+	}
+	s.jobs = append(s.jobs, &entry{job: job, next: job.Schedule.Next(time.Now())})
+	return nil
+}
+
+// ErrBusy se reporta en OnResult cuando una ejecución se omite porque
+// todos los workers están ocupados.
+var ErrBusy = errors.New("scheduler: sin workers libres, se omite la ejecución")
+
+// Run ejecuta los jobs hasta que ctx se cancele y luego espera a que
+// terminen las ejecuciones en curso. Un job que entra en panic se reporta
+// con error en OnResult sin detener al scheduler. Si no hay workers libres
+// la ejecución se omite y se reporta con ErrBusy, en lugar de retrasar el
+// resto de los jobs.
+func (s *Scheduler) Run(ctx context.Context) {
+	pool := workerpool.New(workerpool.Options{Workers: s.Workers, OnPanic: func(any, []byte) {}})
+	defer pool.Close(context.Background())
+	for {
+		now := time.Now()
+		wait, due := s.due(now)
+		for _, job := range due {
+			if err := pool.TrySubmit(func() { s.Trigger(ctx, job) }); err != nil && s.OnResult != nil {
+				s.OnResult(Result{Job: job.Name, Start: now, Err: ErrBusy})
+			}
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+	}
+}
+
+// due retorna los jobs que deben ejecutarse en now, avanzando su próxima
+// ejecución, y cuánto esperar hasta el siguiente.
+func (s *Scheduler) due(now time.Time) (time.Duration, []Job) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []Job
+	wait := time.Minute
+	for _, e := range s.jobs {
+		if e.next.IsZero() {
+			continue
+		}
+		if !e.next.After(now) {
+			due = append(due, e.job)
+			e.next = e.job.Schedule.Next(now)
+		}
+		if d := e.next.Sub(now); d < wait {
+			wait = d
+		}
+	}
+	return wait, due
+}
+
+// Trigger ejecuta job inmediatamente, respetando el Locker si existe.
+func (s *Scheduler) Trigger(ctx context.Context, job Job) Result {
+	res := Result{Job: job.Name, Start: time.Now()}
+	defer func() {
+		v := recover()
+		if v != nil {
+			res.Err = fmt.Errorf("scheduler: panic en %s: %v", job.Name, v)
+		}
+		res.Duration = time.Since(res.Start)
+		if s.OnResult != nil {
+			s.OnResult(res)
+		}
+		if v != nil {
+			panic(v)
+		}
+	}()
+
+	if job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, job.Timeout)
+		defer cancel()
+	}
+
+	if s.Locker != nil {
+		key := job.Name + "@" + res.Start.Truncate(time.Minute).UTC().Format(time.RFC3339)
+		// El lock cubre al menos el minuto completo de la clave.
+		_, ok, err := s.Locker.TryLock(ctx, key, max(job.Timeout, time.Minute))
+		if err != nil {
+			res.Err = err
+			return res
+		}
+		if !ok {
+			return res
+		}
+	}
+
+	req, err := http.NewRequestWithContext(ctx, job.Method, job.Path, bytes.NewReader(job.Body))
+	if err != nil {
+		res.Err = err
+		return res
+	}
+	for k, v := range job.Header {
+		req.Header[k] = v
+	}
+	req.Header.Set(JobHeader, job.Name)
+
+	resp := router.DispatchRequest(s.handler, req)
+	resp.Body.Close()
+	res.Status = resp.StatusCode
+	return res
+}
@@ -0,0 +1,131 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// memLocker es un Locker en memoria compartible entre Schedulers, como si
+// fueran réplicas.
+type memLocker struct {
+	mu       sync.Mutex
+	held     map[string]bool
+	ttls     []time.Duration
+	released int
+	err      error
+}
+
+func (l *memLocker) TryLock(_ context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.err != nil {
+		return nil, false, l.err
+	}
+	l.ttls = append(l.ttls, ttl)
+	if l.held[key] {
+		return nil, false, nil
+	}
+	if l.held == nil {
+		l.held = map[string]bool{}
+	}
+	l.held[key] = true
+	return func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		l.released++
+		delete(l.held, key)
+	}, true, nil
+}
+
+func ok(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusAccepted) }
+
+func TestTriggerLocking(t *testing.T) {
+	tests := []struct {
+		name    string
+		timeout time.Duration
+		wantTTL time.Duration
+	}{
+		{"sin timeout", 0, time.Minute},
+		{"timeout corto", 10 * time.Second, time.Minute},
+		{"timeout largo", 5 * time.Minute, 5 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &memLocker{}
+			a, b := New(http.HandlerFunc(ok)), New(http.HandlerFunc(ok))
+			a.Locker, b.Locker = l, l
+			job := Job{Name: "reporte", Method: http.MethodPost, Path: "/jobs/reporte", Timeout: tt.timeout}
+
+			first := a.Trigger(context.Background(), job)
+			second := b.Trigger(context.Background(), job)
+			// Si el minuto cambió entre ambas ejecuciones las claves difieren.
+			if first.Start.Truncate(time.Minute) != second.Start.Truncate(time.Minute) {
+				t.Skip("las ejecuciones cayeron en minutos distintos")
+			}
+
+			if first.Status != http.StatusAccepted {
+				t.Errorf("primera réplica: status = %d, err = %v", first.Status, first.Err)
+			}
+			if second.Status != 0 || second.Err != nil {
+				t.Errorf("segunda réplica: status = %d, err = %v; se esperaba omitida", second.Status, second.Err)
+			}
+			if l.released != 0 {
+				t.Errorf("el lock se liberó %d veces; debe expirar por TTL", l.released)
+			}
+			if l.ttls[0] != tt.wantTTL {
+				t.Errorf("TTL = %v, se esperaba %v", l.ttls[0], tt.wantTTL)
+			}
+		})
+	}
+}
+
+func TestTriggerLockError(t *testing.T) {
+	boom := errors.New("redis caído")
+	s := New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("el job no debe ejecutarse sin lock")
+	}))
+	s.Locker = &memLocker{err: boom}
+	if res := s.Trigger(context.Background(), Job{Name: "j", Method: http.MethodPost, Path: "/"}); !errors.Is(res.Err, boom) {
+		t.Errorf("Err = %v, se esperaba %v", res.Err, boom)
+	}
+}
+
+func TestRunSkipsWhenBusy(t *testing.T) {
+	block := make(chan struct{})
+	s := New(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { <-block }))
+	s.Workers = 1
+	results := make(chan Result, 8)
+	s.OnResult = func(r Result) { results <- r }
+	for _, name := range []string{"a", "b", "c"} {
+		if err := s.Add(name, "* * * * *", http.MethodPost, "/"+name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Un worker y una cola de uno no alcanzan para tres jobs vencidos.
+	for _, e := range s.jobs {
+		e.next = time.Now().Add(-time.Second)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		s.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case r := <-results:
+		if !errors.Is(r.Err, ErrBusy) {
+			t.Errorf("Err = %v, se esperaba ErrBusy", r.Err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Error("no se reportó la ejecución omitida")
+	}
+	close(block)
+	cancel()
+	<-done
+}
@@ -0,0 +1,123 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Vectores de la suite pública de AWS (aws-sig-v4-test-suite) y del
+// ejemplo de la documentación de IAM, con las credenciales de ejemplo.
+func TestSignV4Vectors(t *testing.T) {
+	const (
+		id     = "AKIDEXAMPLE"
+		secret = "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY"
+	)
+	now := time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+	emptyHash := sha256Hex(nil)
+
+	tests := []struct {
+		name    string
+		method  string
+		url     string
+		header  map[string]string
+		signed  []string
+		service string
+		want    string
+	}{
+		{
+			"get-vanilla", http.MethodGet, "https://example.amazonaws.com/", nil,
+			[]string{"host", "x-amz-date"}, "service",
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5fa00fa31553b73ebf1942676e86291e8372ff2a2260956d9b8aae1d763fbf31",
+		},
+		{
+			"post-vanilla", http.MethodPost, "https://example.amazonaws.com/", nil,
+			[]string{"host", "x-amz-date"}, "service",
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, SignedHeaders=host;x-amz-date, Signature=5da7c1a2acd57cee7505fc6676e4e544621c30862966e37dddb68e92efbe5d6b",
+		},
+		{
+			"iam ListUsers", http.MethodGet, "https://iam.amazonaws.com/?Version=2010-05-08&Action=ListUsers",
+			map[string]string{"Content-Type": "application/x-www-form-urlencoded; charset=utf-8"},
+			[]string{"content-type", "host", "x-amz-date"}, "iam",
+			"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, SignedHeaders=content-type;host;x-amz-date, Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, tt.url, nil)
+			for k, v := range tt.header {
+				req.Header.Set(k, v)
+			}
+			signV4(req, emptyHash, tt.signed, tt.service, "us-east-1", id, secret, now)
+			if got := req.Header.Get("Authorization"); got != tt.want {
+				t.Errorf("Authorization =\n%s\nse esperaba\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCanonicalQuery(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodGet, "https://x/?b=2&a=z&a=y&c=a%20b&d=~*", nil)
+	if got, want := canonicalQuery(req.URL.Query()), "a=y&a=z&b=2&c=a%20b&d=~%2A"; got != want {
+		t.Errorf("canonicalQuery = %q, se esperaba %q", got, want)
+	}
+}
+
+func TestAWSGet(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    string
+		wantErr error
+	}{
+		{"SecretString", http.StatusOK, `{"Name":"db","SecretString":"s3creto"}`, "s3creto", nil},
+		{"SecretBinary", http.StatusOK, `{"Name":"db","SecretBinary":"AAEC"}`, "\x00\x01\x02", nil},
+		{"inexistente", http.StatusBadRequest, `{"__type":"ResourceNotFoundException","message":"no"}`, "", ErrNotFound},
+		{"error del servicio", http.StatusInternalServerError, `{"__type":"InternalServiceError"}`, "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Header.Get("X-Amz-Target") != "secretsmanager.GetSecretValue" {
+					t.Errorf("X-Amz-Target = %q", r.Header.Get("X-Amz-Target"))
+				}
+				auth := r.Header.Get("Authorization")
+				if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=AKID/") ||
+					!strings.Contains(auth, "/eu-west-1/secretsmanager/aws4_request") ||
+					!strings.Contains(auth, "x-amz-security-token;x-amz-target") {
+					t.Errorf("Authorization = %q", auth)
+				}
+				if r.Header.Get("X-Amz-Security-Token") != "tok" {
+					t.Error("falta X-Amz-Security-Token")
+				}
+				var in struct{ SecretId string }
+				b, _ := io.ReadAll(r.Body)
+				if json.Unmarshal(b, &in); in.SecretId != "db" || r.Header.Get("X-Amz-Content-Sha256") != sha256Hex(b) {
+					t.Errorf("cuerpo = %s", b)
+				}
+				w.WriteHeader(tt.status)
+				io.WriteString(w, tt.body)
+			}))
+			defer srv.Close()
+
+			a := &AWS{Region: "eu-west-1", AccessKeyID: "AKID", SecretAccessKey: "s", SessionToken: "tok", Endpoint: srv.URL + "/", Client: srv.Client()}
+			got, err := a.Get(context.Background(), "db")
+			if tt.status != http.StatusOK {
+				if err == nil || (tt.wantErr != nil && !errors.Is(err, tt.wantErr)) {
+					t.Errorf("err = %v, se esperaba %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || string(got) != tt.want {
+				t.Errorf("Get = %q, %v; se esperaba %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package secrets
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// EncryptedPrefix marca los valores de configuración cifrados. El formato
+// completo es
+//
+//	enc:v1:<clave maestra>:<clave de datos envuelta>:<valor cifrado>
+//
+// con ambas partes binarias en base64 sin padding (URL-safe).
+const EncryptedPrefix = "enc:v1:"
+
+var envB64 = base64.RawURLEncoding
+
+// Envelope cifra valores de configuración con cifrado de sobre: cada valor
+// usa una clave de datos AES-256-GCM aleatoria, que a su vez se cifra con
+// la clave maestra KeyName del Provider. El nombre de la clave maestra
+// queda en el valor, de modo que para rotarla basta con publicar la nueva
+// bajo otro nombre y apuntar KeyName a ella: los valores existentes siguen
+// descifrándose con la anterior mientras el Provider la conserve.
+//
+// El valor cifrado se autentica junto con la ruta del campo ("DB.Password",
+// "Upstreams[0].Token"), así que no puede copiarse a otro campo.
+type Envelope struct {
+	Provider Provider
+	// KeyName es el secreto con la clave maestra (32 bytes, en crudo o en
+	// base64) con la que Encrypt envuelve las claves de datos.
+	KeyName string
+}
+
+// keyring cachea las claves maestras leídas del Provider, para consultarlo
+// una sola vez por clave durante un Resolve.
+type keyring struct {
+	e     Envelope
+	aeads map[string]cipher.AEAD
+}
+
+func (e Envelope) keyring() *keyring {
+	return &keyring{e: e, aeads: map[string]cipher.AEAD{}}
+}
+
+func (k *keyring) master(ctx context.Context, name string) (cipher.AEAD, error) {
+	if aead, ok := k.aeads[name]; ok {
+		return aead, nil
+	}
+	key, err := k.e.Provider.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if decoded, err := base64.StdEncoding.DecodeString(string(key)); err == nil && len(decoded) == 32 {
+		key = decoded
+	}
+	if len(key) != 32 {
+		return nil, fmt.Errorf("secrets: la clave %s debe tener 32 bytes", name)
+	}
+	aead, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	k.aeads[name] = aead
+	return aead, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func seal(aead cipher.AEAD, plaintext, aad []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, aad), nil
+}
+
+func open(aead cipher.AEAD, sealed, aad []byte) ([]byte, error) {
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("secrets: valor cifrado mal formado")
+	}
+	return aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], aad)
+}
+
+// Encrypt cifra plaintext para el campo field (la ruta que Resolve le
+// asigna) y retorna un valor con EncryptedPrefix, listo para pegar en el
+// archivo de configuración.
+func (e Envelope) Encrypt(ctx context.Context, field, plaintext string) (string, error) {
+	return e.keyring().encrypt(ctx, field, plaintext)
+}
+
+func (k *keyring) encrypt(ctx context.Context, field, plaintext string) (string, error) {
+	name := k.e.KeyName
+	if name == "" || strings.Contains(name, ":") {
+		return "", fmt.Errorf("secrets: nombre de clave maestra inválido %q", name)
+	}
+	master, err := k.master(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	dek := make([]byte, 32)
+	if _, err := rand.Read(dek); err != nil {
+		return "", err
+	}
+	wrapped, err := seal(master, dek, []byte(name))
+	if err != nil {
+		return "", err
+	}
+	aead, err := newGCM(dek)
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(aead, []byte(plaintext), []byte(field))
+	if err != nil {
+		return "", err
+	}
+	return EncryptedPrefix + name + ":" + envB64.EncodeToString(wrapped) + ":" + envB64.EncodeToString(sealed), nil
+}
+
+// Decrypt descifra value, cifrado para field, si tiene EncryptedPrefix; en
+// otro caso lo retorna sin cambios.
+func (e Envelope) Decrypt(ctx context.Context, field, value string) (string, error) {
+	return e.keyring().decrypt(ctx, field, value)
+}
+
+func (k *keyring) decrypt(ctx context.Context, field, value string) (string, error) {
+	rest, ok := strings.CutPrefix(value, EncryptedPrefix)
+	if !ok {
+		return value, nil
+	}
+	parts := strings.Split(rest, ":")
+	if len(parts) != 3 || parts[0] == "" {
+		return "", errors.New("secrets: valor cifrado mal formado")
+	}
+	wrapped, err1 := envB64.DecodeString(parts[1])
+	sealed, err2 := envB64.DecodeString(parts[2])
+	if err1 != nil || err2 != nil {
+		return "", errors.New("secrets: valor cifrado mal formado")
+	}
+	master, err := k.master(ctx, parts[0])
+	if err != nil {
+		return "", err
+	}
+	dek, err := open(master, wrapped, []byte(parts[0]))
+	if err != nil {
+		return "", errors.New("secrets: no se pudo desenvolver la clave de datos")
+	}
+	aead, err := newGCM(dek)
+	if err != nil {
+		return "", err
+	}
+	plain, err := open(aead, sealed, []byte(field))
+	if err != nil {
+		return "", errors.New("secrets: no se pudo descifrar el valor")
+	}
+	return string(plain), nil
+}
+
+// Resolve recorre dst (puntero a struct, map o slice) y reemplaza en su
+// lugar cada string cifrado por su valor en claro. La ruta de cada campo
+// usa los nombres de Go: "DB.Password", "Hosts[0]", "Tokens[github]". Se
+// invoca tras cargar o recargar la configuración.
+func (e Envelope) Resolve(ctx context.Context, dst any) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return errors.New("secrets: Resolve requiere un puntero")
+	}
+	return e.keyring().resolve(ctx, v.Elem(), "")
+}
+
+func (k *keyring) resolve(ctx context.Context, v reflect.Value, path string) error {
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Interface {
+			// Los valores dentro de una interfaz no son asignables: se
+			// resuelve una copia y se vuelve a guardar.
+			inner := reflect.New(v.Elem().Type()).Elem()
+			inner.Set(v.Elem())
+			if err := k.resolve(ctx, inner, path); err != nil {
+				return err
+			}
+			if v.CanSet() {
+				v.Set(inner)
+			}
+			return nil
+		}
+		return k.resolve(ctx, v.Elem(), path)
+	case reflect.Struct:
+		t := v.Type()
+		for i := range v.NumField() {
+			if !t.Field(i).IsExported() {
+				continue
+			}
+			if err := k.resolve(ctx, v.Field(i), path+"."+t.Field(i).Name); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			if err := k.resolve(ctx, v.Index(i), fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case reflect.Map:
+		iter := v.MapRange()
+		for iter.Next() {
+			elem := reflect.New(iter.Value().Type()).Elem()
+			elem.Set(iter.Value())
+			if err := k.resolve(ctx, elem, fmt.Sprintf("%s[%v]", path, iter.Key())); err != nil {
+				return err
+			}
+			v.SetMapIndex(iter.Key(), elem)
+		}
+	case reflect.String:
+		if !strings.HasPrefix(v.String(), EncryptedPrefix) {
+			return nil
+		}
+		field := strings.TrimPrefix(path, ".")
+		plain, err := k.decrypt(ctx, field, v.String())
+		if err != nil {
+			return fmt.Errorf("%s: %w", field, err)
+		}
+		if !v.CanSet() {
+			return fmt.Errorf("secrets: %s no es asignable", field)
+		}
+		v.SetString(plain)
+	}
+	return nil
+}
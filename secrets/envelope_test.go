@@ -0,0 +1,143 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// keys es un Provider en memoria que cuenta sus lecturas.
+type keys struct {
+	m     map[string][]byte
+	calls int
+}
+
+func (k *keys) Get(_ context.Context, name string) ([]byte, error) {
+	k.calls++
+	v, ok := k.m[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return v, nil
+}
+
+func newKeys() *keys {
+	return &keys{m: map[string][]byte{
+		"k1": bytes.Repeat([]byte{1}, 32),
+		"k2": []byte(base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{2}, 32))),
+	}}
+}
+
+func TestEnvelopeRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	e := Envelope{Provider: newKeys(), KeyName: "k1"}
+	a, err := e.Encrypt(ctx, "DB.Password", "s3creto")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := e.Encrypt(ctx, "DB.Password", "s3creto")
+	if a == b {
+		t.Error("dos cifrados del mismo valor no deben coincidir")
+	}
+	if !strings.HasPrefix(a, EncryptedPrefix+"k1:") || strings.Contains(a, "s3creto") {
+		t.Errorf("formato inesperado: %q", a)
+	}
+
+	parts := strings.Split(a, ":")
+	otherDEK, _ := e.Encrypt(ctx, "DB.Password", "otro")
+	tests := []struct {
+		name    string
+		field   string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{"ida y vuelta", "DB.Password", a, "s3creto", false},
+		{"texto plano", "DB.Password", "sin cifrar", "sin cifrar", false},
+		{"otro campo", "DB.User", a, "", true},
+		{"valor alterado", "DB.Password", strings.Join(append(parts[:4:4], flipB64(parts[4])), ":"), "", true},
+		{"clave envuelta alterada", "DB.Password", strings.Join([]string{parts[0], parts[1], parts[2], flipB64(parts[3]), parts[4]}, ":"), "", true},
+		{"clave de datos de otro valor", "DB.Password", strings.Join([]string{parts[0], parts[1], parts[2], strings.Split(otherDEK, ":")[3], parts[4]}, ":"), "", true},
+		{"otra clave maestra", "DB.Password", strings.Replace(a, ":k1:", ":k2:", 1), "", true},
+		{"clave maestra inexistente", "DB.Password", strings.Replace(a, ":k1:", ":k9:", 1), "", true},
+		{"mal formado", "DB.Password", EncryptedPrefix + "k1:abc", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := e.Decrypt(ctx, tt.field, tt.value)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("Decrypt = %q, %v; se esperaba %q (error: %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// flipB64 altera el primer carácter de s sin salir del alfabeto.
+func flipB64(s string) string {
+	c := "A"
+	if s[0] == 'A' {
+		c = "B"
+	}
+	return c + s[1:]
+}
+
+func TestEnvelopeRotation(t *testing.T) {
+	ctx := context.Background()
+	p := newKeys()
+	old, _ := Envelope{Provider: p, KeyName: "k1"}.Encrypt(ctx, "Token", "viejo")
+	rotated := Envelope{Provider: p, KeyName: "k2"}
+	fresh, err := rotated.Encrypt(ctx, "Token", "nuevo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for value, want := range map[string]string{old: "viejo", fresh: "nuevo"} {
+		if got, err := rotated.Decrypt(ctx, "Token", value); err != nil || got != want {
+			t.Errorf("Decrypt = %q, %v; se esperaba %q", got, err, want)
+		}
+	}
+}
+
+func TestEnvelopeResolve(t *testing.T) {
+	ctx := context.Background()
+	p := newKeys()
+	e := Envelope{Provider: p, KeyName: "k1"}
+	enc := func(field, v string) string {
+		s, err := e.Encrypt(ctx, field, v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return s
+	}
+
+	type db struct{ User, Password string }
+	cfg := struct {
+		DB     db
+		Hosts  []string
+		Tokens map[string]string
+		Extra  any
+	}{
+		DB:     db{User: "app", Password: enc("DB.Password", "pw")},
+		Hosts:  []string{"a", enc("Hosts[1]", "b")},
+		Tokens: map[string]string{"github": enc("Tokens[github]", "gh")},
+		Extra:  enc("Extra", "x"),
+	}
+	p.calls = 0
+	if err := e.Resolve(ctx, &cfg); err != nil {
+		t.Fatal(err)
+	}
+	if cfg.DB.Password != "pw" || cfg.Hosts[1] != "b" || cfg.Tokens["github"] != "gh" || cfg.Extra != "x" {
+		t.Errorf("Resolve = %+v", cfg)
+	}
+	if p.calls != 1 {
+		t.Errorf("el Provider se consultó %d veces, se esperaba 1", p.calls)
+	}
+
+	// Un valor copiado a otro campo no se descifra.
+	swapped := struct{ User, Password string }{User: enc("Password", "pw")}
+	if err := e.Resolve(ctx, &swapped); err == nil || !strings.Contains(err.Error(), "User") {
+		t.Errorf("err = %v, se esperaba un error en User", err)
+	}
+}
@@ -0,0 +1,117 @@
+// Package secrets abstrae el origen de los secretos (variables de entorno,
+// archivos montados, Vault, AWS Secrets Manager) que consumen los
+// subsistemas de TLS, firma de cookies, JWT y URLs firmadas, e informa de
+// sus rotaciones mediante callbacks.
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNotFound indica que el proveedor no conoce el secreto.
+var ErrNotFound = errors.New("secrets: secreto no encontrado")
+
+// Provider obtiene el valor actual de un secreto por nombre.
+type Provider interface {
+	Get(ctx context.Context, name string) ([]byte, error)
+}
+
+// ProviderFunc adapta una función al interfaz Provider.
+type ProviderFunc func(ctx context.Context, name string) ([]byte, error)
+
+// Get implementa Provider.
+func (f ProviderFunc) Get(ctx context.Context, name string) ([]byte, error) { return f(ctx, name) }
+
+// Env lee secretos de variables de entorno. El nombre se pasa a
+// mayúsculas, se reemplazan '-', '.' y '/' por '_' y se antepone Prefix.
+type Env struct {
+	Prefix string
+}
+
+// Get implementa Provider.
+func (e Env) Get(_ context.Context, name string) ([]byte, error) {
+	key := e.Prefix + strings.ToUpper(strings.NewReplacer("-", "_", ".", "_", "/", "_").Replace(name))
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, key)
+	}
+	return []byte(v), nil
+}
+
+// File lee cada secreto de un archivo dentro de Dir, como los que monta
+// Kubernetes o Docker. Se descarta el salto de línea final.
+type File struct {
+	Dir string
+}
+
+// Get implementa Provider.
+func (f File) Get(_ context.Context, name string) ([]byte, error) {
+	if !filepath.IsLocal(name) {
+		return nil, fmt.Errorf("secrets: nombre inválido %q", name)
+	}
+	data, err := os.ReadFile(filepath.Join(f.Dir, name))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(data, "\r\n"), nil
+}
+
+// Chain consulta los proveedores en orden y retorna el primer secreto
+// encontrado. Otros errores se retornan de inmediato.
+func Chain(providers ...Provider) Provider {
+	return ProviderFunc(func(ctx context.Context, name string) ([]byte, error) {
+		for _, p := range providers {
+			v, err := p.Get(ctx, name)
+			if err == nil {
+				return v, nil
+			}
+			if !errors.Is(err, ErrNotFound) {
+				return nil, err
+			}
+		}
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	})
+}
+
+// Watch consulta name cada interval hasta que ctx se cancele e invoca
+// onRotate cada vez que su valor cambia, incluida la primera lectura. Los
+// errores de lectura se informan a onError, si no es nil, y no detienen la
+// vigilancia. Debe ejecutarse en su propia goroutine.
+func Watch(ctx context.Context, p Provider, name string, interval time.Duration, onRotate func([]byte), onError func(error)) {
+	var last []byte
+	check := func() {
+		v, err := p.Get(ctx, name)
+		if err != nil {
+			if onError != nil && ctx.Err() == nil {
+				onError(err)
+			}
+			return
+		}
+		if last == nil || !bytes.Equal(v, last) {
+			last = v
+			onRotate(v)
+		}
+	}
+
+	check()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			check()
+		}
+	}
+}
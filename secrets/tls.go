@@ -0,0 +1,91 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Certificate mantiene un certificado TLS cuyo PEM (certificado y clave)
+// proviene de un Provider. Su método GetCertificate se asigna a
+// tls.Config.GetCertificate, de modo que las rotaciones se aplican a las
+// conexiones nuevas sin reiniciar el servidor.
+type Certificate struct {
+	provider Provider
+	certName string
+	keyName  string
+	current  atomic.Pointer[tls.Certificate]
+
+	mu                sync.Mutex
+	lastCert, lastKey []byte
+	// OnRotate se invoca cuando una recarga trae un par distinto del
+	// vigente; puede ser nil.
+	OnRotate func(*tls.Certificate)
+}
+
+// LoadCertificate lee el par certName/keyName de p.
+func LoadCertificate(ctx context.Context, p Provider, certName, keyName string) (*Certificate, error) {
+	c := &Certificate{provider: p, certName: certName, keyName: keyName}
+	if err := c.Reload(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Reload vuelve a leer el certificado desde el Provider. Si el PEM no
+// cambió, conserva el par vigente sin invocar OnRotate.
+func (c *Certificate) Reload(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	certPEM, err := c.provider.Get(ctx, c.certName)
+	if err != nil {
+		return err
+	}
+	keyPEM, err := c.provider.Get(ctx, c.keyName)
+	if err != nil {
+		return err
+	}
+	if c.lastCert != nil && bytes.Equal(certPEM, c.lastCert) && bytes.Equal(keyPEM, c.lastKey) {
+		return nil
+	}
+	pair, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return err
+	}
+	c.current.Store(&pair)
+	c.lastCert, c.lastKey = certPEM, keyPEM
+	if c.OnRotate != nil {
+		c.OnRotate(&pair)
+	}
+	return nil
+}
+
+// Watch recarga el certificado cada interval hasta que ctx se cancele.
+// Debe ejecutarse en su propia goroutine.
+func (c *Certificate) Watch(ctx context.Context, interval time.Duration, onError func(error)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := c.Reload(ctx); err != nil && onError != nil && ctx.Err() == nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// GetCertificate implementa la firma de tls.Config.GetCertificate.
+func (c *Certificate) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	return c.current.Load(), nil
+}
+
+// TLSConfig retorna una configuración TLS que sirve este certificado.
+func (c *Certificate) TLSConfig() *tls.Config {
+	return &tls.Config{MinVersion: tls.VersionTLS12, GetCertificate: c.GetCertificate}
+}
@@ -0,0 +1,85 @@
+package secrets
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"testing"
+	"time"
+)
+
+// selfSigned genera un par PEM autofirmado para cn.
+func selfSigned(t *testing.T, cn string) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: cn},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+func TestCertificateReload(t *testing.T) {
+	ctx := context.Background()
+	p := &keys{m: map[string][]byte{}}
+	p.m["cert"], p.m["key"] = selfSigned(t, "uno")
+
+	c, err := LoadCertificate(ctx, p, "cert", "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	first, _ := c.GetCertificate(nil)
+	var rotations []*tls.Certificate
+	c.OnRotate = func(cert *tls.Certificate) { rotations = append(rotations, cert) }
+
+	for range 3 {
+		if err := c.Reload(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cur, _ := c.GetCertificate(nil); len(rotations) != 0 || cur != first {
+		t.Errorf("sin cambios: %d rotaciones, se esperaba 0 y el mismo certificado", len(rotations))
+	}
+
+	p.m["cert"], p.m["key"] = selfSigned(t, "dos")
+	if err := c.Reload(ctx); err != nil {
+		t.Fatal(err)
+	}
+	c.Reload(ctx)
+	cur, _ := c.GetCertificate(nil)
+	if len(rotations) != 1 || cur != rotations[0] || cur == first {
+		t.Fatalf("tras el cambio: %d rotaciones, se esperaba 1 con el certificado nuevo", len(rotations))
+	}
+	if leaf, _ := x509.ParseCertificate(cur.Certificate[0]); leaf.Subject.CommonName != "dos" {
+		t.Errorf("CN = %q, se esperaba dos", leaf.Subject.CommonName)
+	}
+
+	// Un par inválido no reemplaza al vigente.
+	p.m["key"] = []byte("basura")
+	if err := c.Reload(ctx); err == nil {
+		t.Error("se esperaba un error con una clave inválida")
+	}
+	if now, _ := c.GetCertificate(nil); now != cur || len(rotations) != 1 {
+		t.Error("un par inválido no debe reemplazar al vigente")
+	}
+}
@@ -0,0 +1,80 @@
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Vault lee secretos del motor KV versión 2 de HashiCorp Vault. El nombre
+// tiene la forma "ruta#campo"; si se omite el campo se usa "value".
+type Vault struct {
+	// Address es la URL del servidor, p. ej. https://vault:8200.
+	Address string
+	Token   string
+	// Mount es el punto de montaje del motor KV; por defecto "secret".
+	Mount string
+	// Namespace se envía como X-Vault-Namespace si no está vacío.
+	Namespace string
+	Client    *http.Client
+}
+
+// Get implementa Provider.
+func (v *Vault) Get(ctx context.Context, name string) ([]byte, error) {
+	path, field, ok := strings.Cut(name, "#")
+	if !ok {
+		field = "value"
+	}
+	mount := v.Mount
+	if mount == "" {
+		mount = "secret"
+	}
+
+	url := strings.TrimRight(v.Address, "/") + "/v1/" + mount + "/data/" + strings.TrimLeft(path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", v.Token)
+	if v.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", v.Namespace)
+	}
+
+	client := v.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("secrets: vault: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	case resp.StatusCode != http.StatusOK:
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("secrets: vault: status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]any `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("secrets: vault: %w", err)
+	}
+	value, ok := body.Data.Data[field]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if s, ok := value.(string); ok {
+		return []byte(s), nil
+	}
+	return json.Marshal(value)
+}
@@ -0,0 +1,81 @@
+package secrets
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVaultGet(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "tok" || r.Header.Get("X-Vault-Namespace") != "equipo" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/kv/data/app/db":
+			io.WriteString(w, `{"data":{"data":{"value":"pw","user":"app","port":5432,"hosts":["a","b"]}}}`)
+		case "/v1/kv/data/roto":
+			w.WriteHeader(http.StatusInternalServerError)
+			io.WriteString(w, `{"errors":["fallo"]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	tests := []struct {
+		name    string
+		key     string
+		token   string
+		want    string
+		wantErr error
+	}{
+		{"campo por defecto", "app/db", "tok", "pw", nil},
+		{"campo explícito", "/app/db#user", "tok", "app", nil},
+		{"número", "app/db#port", "tok", "5432", nil},
+		{"lista", "app/db#hosts", "tok", `["a","b"]`, nil},
+		{"campo inexistente", "app/db#nada", "tok", "", ErrNotFound},
+		{"ruta inexistente", "otra", "tok", "", ErrNotFound},
+		{"error del servidor", "roto", "tok", "", errors.New("status 500")},
+		{"token inválido", "app/db", "malo", "", errors.New("status 403")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &Vault{Address: srv.URL + "/", Token: tt.token, Mount: "kv", Namespace: "equipo", Client: srv.Client()}
+			got, err := v.Get(context.Background(), tt.key)
+			if tt.wantErr != nil {
+				if err == nil || (errors.Is(tt.wantErr, ErrNotFound) && !errors.Is(err, ErrNotFound)) {
+					t.Errorf("err = %v, se esperaba %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || string(got) != tt.want {
+				t.Errorf("Get = %q, %v; se esperaba %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVaultDefaultMount(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		if _, ok := r.Header["X-Vault-Namespace"]; ok {
+			t.Error("sin Namespace no debe enviarse X-Vault-Namespace")
+		}
+		io.WriteString(w, `{"data":{"data":{"value":"x"}}}`)
+	}))
+	defer srv.Close()
+
+	v := &Vault{Address: srv.URL, Token: "tok", Client: srv.Client()}
+	if _, err := v.Get(context.Background(), "app"); err != nil {
+		t.Fatal(err)
+	}
+	if path != "/v1/secret/data/app" {
+		t.Errorf("ruta = %q, se esperaba /v1/secret/data/app", path)
+	}
+}
@@ -0,0 +1,187 @@
+// Package signedurl genera y verifica URLs firmadas con HMAC y fecha de
+// expiración, para enlaces de descarga temporales y callbacks de webhooks.
+//
+// La firma cubre la ruta, la query (ordenada) y la expiración, de modo que
+// cualquier cambio en ellas invalida el enlace.
+package signedurl
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/profe-ajedrez/transwarp/router"
+	"github.com/profe-ajedrez/transwarp/secrets"
+)
+
+// Nombres de los parámetros de query que agrega Sign.
+const (
+	ExpiresParam   = "expires"
+	SignatureParam = "signature"
+)
+
+// maxKeys acota cuántas claves previas sigue aceptando un Signer tras
+// sucesivas rotaciones.
+const maxKeys = 3
+
+// Errores retornados por Verify.
+var (
+	ErrMissing   = errors.New("signedurl: la URL no está firmada")
+	ErrExpired   = errors.New("signedurl: la URL expiró")
+	ErrSignature = errors.New("signedurl: firma inválida")
+)
+
+// Signer firma y verifica URLs. La primera clave se usa para firmar; todas
+// se aceptan al verificar, lo que permite rotar claves sin invalidar los
+// enlaces ya emitidos.
+type Signer struct {
+	mu   sync.RWMutex
+	keys [][]byte
+}
+
+// New crea un Signer con la clave actual y, opcionalmente, claves previas
+// aún aceptadas.
+func New(current []byte, previous ...[]byte) *Signer {
+	return &Signer{keys: append([][]byte{current}, previous...)}
+}
+
+// FromProvider crea un Signer cuya clave es el secreto name de p. Para
+// seguir sus rotaciones, pase Rotate como callback de secrets.Watch.
+func FromProvider(ctx context.Context, p secrets.Provider, name string) (*Signer, error) {
+	key, err := p.Get(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	return New(key), nil
+}
+
+// Rotate pasa a firmar con key y conserva la clave anterior para verificar
+// los enlaces emitidos con ella. Se conservan a lo sumo maxKeys claves.
+func (s *Signer) Rotate(key []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.keys) > 0 && hmac.Equal(s.keys[0], key) {
+		return
+	}
+	s.keys = append([][]byte{key}, s.keys[:min(len(s.keys), maxKeys-1)]...)
+}
+
+func (s *Signer) snapshot() [][]byte {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keys
+}
+
+// Sign construye la ruta a partir de route (p. ej. "/files/:id" o
+// "/files/{id}") reemplazando sus parámetros con params, agrega como query
+// los params que no aparecen en la ruta, y firma el resultado con validez
+// expiry.
+func (s *Signer) Sign(route string, params map[string]string, expiry time.Duration) (string, error) {
+	keys := s.snapshot()
+	if len(keys) == 0 || len(keys[0]) == 0 {
+		return "", errors.New("signedurl: no hay clave de firma")
+	}
+	path, rest, err := expand(route, params)
+	if err != nil {
+		return "", err
+	}
+	query := url.Values{}
+	for k, v := range rest {
+		query.Set(k, v)
+	}
+	query.Set(ExpiresParam, strconv.FormatInt(time.Now().Add(expiry).Unix(), 10))
+	query.Set(SignatureParam, sign(keys[0], path, query))
+	return path + "?" + query.Encode(), nil
+}
+
+// Verify comprueba la firma y la expiración de la URL de r.
+func (s *Signer) Verify(r *http.Request) error {
+	query := r.URL.Query()
+	sig := query.Get(SignatureParam)
+	expStr := query.Get(ExpiresParam)
+	if sig == "" || expStr == "" {
+		return ErrMissing
+	}
+	exp, err := strconv.ParseInt(expStr, 10, 64)
+	if err != nil {
+		return ErrSignature
+	}
+	for _, key := range s.snapshot() {
+		if hmac.Equal([]byte(sig), []byte(sign(key, r.URL.EscapedPath(), query))) {
+			if time.Now().Unix() > exp {
+				return ErrExpired
+			}
+			return nil
+		}
+	}
+	return ErrSignature
+}
+
+// Middleware rechaza con 403 las peticiones cuya URL no tiene una firma
+// válida y vigente.
+func (s *Signer) Middleware() router.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if err := s.Verify(r); err != nil {
+				http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// sign calcula el HMAC de path y la query sin el parámetro de firma.
+// url.Values.Encode ordena las claves, por lo que el resultado es canónico.
+func sign(key []byte, path string, query url.Values) string {
+	q := url.Values{}
+	for k, v := range query {
+		if k != SignatureParam {
+			q[k] = v
+		}
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(path))
+	mac.Write([]byte{'?'})
+	mac.Write([]byte(q.Encode()))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// expand reemplaza los segmentos :name, {name} y *name de route. Retorna
+// los params no usados.
+func expand(route string, params map[string]string) (string, map[string]string, error) {
+	rest := make(map[string]string, len(params))
+	for k, v := range params {
+		rest[k] = v
+	}
+
+	segments := strings.Split(route, "/")
+	for i, seg := range segments {
+		var name string
+		switch {
+		case strings.HasPrefix(seg, ":"):
+			name = strings.TrimSuffix(seg[1:], "*")
+		case strings.HasPrefix(seg, "*"):
+			name = seg[1:]
+		case strings.HasPrefix(seg, "{") && strings.HasSuffix(seg, "}"):
+			name = strings.TrimSuffix(seg[1:len(seg)-1], "...")
+		default:
+			continue
+		}
+		v, ok := rest[name]
+		if !ok {
+			return "", nil, errors.New("signedurl: falta el parámetro " + name)
+		}
+		delete(rest, name)
+		segments[i] = url.PathEscape(v)
+	}
+	return strings.Join(segments, "/"), rest, nil
+}
@@ -0,0 +1,105 @@
+package signedurl
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name     string
+		route    string
+		params   map[string]string
+		wantPath string
+		wantRest int
+		wantErr  bool
+	}{
+		{"dos puntos", "/files/:id", map[string]string{"id": "42"}, "/files/42", 0, false},
+		{"llaves", "/files/{id}", map[string]string{"id": "42", "dl": "1"}, "/files/42", 1, false},
+		{"comodín", "/static/{path...}", map[string]string{"path": "a/b"}, "/static/a%2Fb", 0, false},
+		{"escapa el valor", "/u/:name", map[string]string{"name": "a b"}, "/u/a%20b", 0, false},
+		{"falta parámetro", "/files/:id", nil, "", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path, rest, err := expand(tt.route, tt.params)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("err = %v, se esperaba error: %v", err, tt.wantErr)
+			}
+			if path != tt.wantPath || len(rest) != tt.wantRest {
+				t.Errorf("expand = %q, %v; se esperaba %q con %d restantes", path, rest, tt.wantPath, tt.wantRest)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	s := New([]byte("clave-actual"))
+	signed, err := s.Sign("/files/:id", map[string]string{"id": "42", "dl": "1"}, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired, _ := s.Sign("/files/:id", map[string]string{"id": "42"}, -time.Minute)
+	other, _ := New([]byte("otra-clave")).Sign("/files/:id", map[string]string{"id": "42"}, time.Hour)
+
+	tests := []struct {
+		name   string
+		target string
+		want   error
+	}{
+		{"válida", signed, nil},
+		{"sin firma", "/files/42", ErrMissing},
+		{"expirada", expired, ErrExpired},
+		{"otra clave", other, ErrSignature},
+		{"ruta alterada", strings.Replace(signed, "/42", "/43", 1), ErrSignature},
+		{"query alterada", strings.Replace(signed, "dl=1", "dl=2", 1), ErrSignature},
+		{"parámetro agregado", signed + "&admin=1", ErrSignature},
+		{"expiración alterada", strings.Replace(signed, "expires=", "expires=9", 1), ErrSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := s.Verify(httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Verify = %v, se esperaba %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	s := New([]byte("k1"))
+	old, _ := s.Sign("/a", nil, time.Hour)
+	s.Rotate([]byte("k2"))
+	s.Rotate([]byte("k2"))
+	current, _ := s.Sign("/a", nil, time.Hour)
+
+	for _, target := range []string{old, current} {
+		if err := s.Verify(httptest.NewRequest(http.MethodGet, target, nil)); err != nil {
+			t.Errorf("%s: %v", target, err)
+		}
+	}
+
+	s.Rotate([]byte("k3"))
+	s.Rotate([]byte("k4"))
+	if err := s.Verify(httptest.NewRequest(http.MethodGet, old, nil)); !errors.Is(err, ErrSignature) {
+		t.Errorf("tras %d rotaciones la clave inicial debe descartarse, Verify = %v", maxKeys, err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	s := New([]byte("clave"))
+	signed, _ := s.Sign("/files/:id", map[string]string{"id": "1"}, time.Hour)
+	h := s.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	for target, want := range map[string]int{signed: http.StatusOK, "/files/1": http.StatusForbidden} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+		if w.Code != want {
+			t.Errorf("%s: status = %d, se esperaba %d", target, w.Code, want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Package staticexport convierte una aplicación transwarp en un generador
+// de sitios estáticos: despacha en proceso las rutas GET indicadas (y,
+// opcionalmente, las que descubre siguiendo enlaces) y escribe cada
+// respuesta en un directorio.
+package staticexport
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// Options configura Export.
+type Options struct {
+	// Dir es el directorio de salida.
+	Dir string
+	// Paths son las rutas a exportar: las rutas sin parámetros y las
+	// expansiones de las rutas con parámetros (p. ej. "/posts/hello").
+	Paths []string
+	// Crawl sigue los enlaces locales de las páginas HTML exportadas.
+	Crawl bool
+	// Header se agrega a cada petición despachada.
+	Header http.Header
+}
+
+// Result resume una exportación.
+type Result struct {
+	Written []string
+	// Failed asocia cada ruta que no respondió 200 con su status.
+	Failed map[string]int
+}
+
+// linkRE captura los atributos href y src con rutas absolutas locales.
+var linkRE = regexp.MustCompile(`(?i)\b(?:href|src)\s*=\s*["'](/[^"'#?]*)`)
+
+// Export despacha cada ruta contra h y escribe las respuestas 200 en
+// opts.Dir. Las respuestas HTML se guardan como <ruta>/index.html y el
+// resto con el nombre de la ruta.
+func Export(ctx context.Context, h http.Handler, opts Options) (*Result, error) {
+	if opts.Dir == "" {
+		return nil, fmt.Errorf("staticexport: falta el directorio de salida")
+	}
+	res := &Result{Failed: map[string]int{}}
+	queue := slices.Clone(opts.Paths)
+	seen := map[string]bool{}
+
+	for len(queue) > 0 {
+		if err := ctx.Err(); err != nil {
+			return res, err
+		}
+		p := queue[0]
+		queue = queue[1:]
+		if p == "" {
+			p = "/"
+		}
+		if seen[p] {
+			continue
+		}
+		seen[p] = true
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, p, nil)
+		if err != nil {
+			return res, fmt.Errorf("staticexport: %s: %w", p, err)
+		}
+		for k, v := range opts.Header {
+			req.Header[k] = v
+		}
+		resp := router.DispatchRequest(h, req)
+		body, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return res, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			res.Failed[p] = resp.StatusCode
+			continue
+		}
+
+		mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+		isHTML := mediaType == "text/html"
+		target, err := outputPath(opts.Dir, p, isHTML)
+		if err != nil {
+			return res, err
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+			return res, err
+		}
+		if err := os.WriteFile(target, body, 0o644); err != nil {
+			return res, err
+		}
+		res.Written = append(res.Written, target)
+
+		if opts.Crawl && isHTML {
+			for _, m := range linkRE.FindAllSubmatch(body, -1) {
+				link := string(m[1])
+				if !strings.HasPrefix(link, "//") && !seen[link] {
+					queue = append(queue, link)
+				}
+			}
+		}
+	}
+	return res, nil
+}
+
+// outputPath resuelve el archivo de salida de la ruta p dentro de dir,
+// rechazando rutas que escapen de él.
+func outputPath(dir, p string, isHTML bool) (string, error) {
+	unescaped, err := url.PathUnescape(p)
+	if err != nil {
+		return "", fmt.Errorf("staticexport: ruta inválida %q", p)
+	}
+	clean := path.Clean("/" + unescaped)
+	rel := strings.TrimPrefix(clean, "/")
+	switch {
+	case isHTML && path.Ext(clean) != ".html":
+		rel = path.Join(rel, "index.html")
+	case rel == "":
+		rel = "index.html"
+	}
+	if !filepath.IsLocal(filepath.FromSlash(rel)) {
+		return "", fmt.Errorf("staticexport: ruta fuera del directorio %q", p)
+	}
+	return filepath.Join(dir, filepath.FromSlash(rel)), nil
+}
@@ -0,0 +1,47 @@
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"time"
+)
+
+// TokenBucket lo implementan los Store que pueden consumir un token de un
+// bucket de forma atómica. Un bucket admite capacity tokens y recupera uno
+// cada interval; se crea lleno la primera vez que se usa key.
+type TokenBucket interface {
+	// TakeToken consume un token de key. Si no hay, ok es false y wait es
+	// cuánto falta para el próximo; remaining son los tokens que quedan
+	// tras la operación.
+	TakeToken(ctx context.Context, key string, capacity int, interval time.Duration) (ok bool, remaining int, wait time.Duration, err error)
+}
+
+// TakeToken implementa TokenBucket. El estado se guarda como entrada de
+// Memory y expira cuando el bucket volvería a estar lleno.
+func (m *Memory) TakeToken(_ context.Context, key string, capacity int, interval time.Duration) (bool, int, time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := m.now()
+	tokens := float64(capacity)
+	if e, ok := m.lookup(key); ok && len(e.value) == 16 {
+		tokens = math.Float64frombits(binary.BigEndian.Uint64(e.value))
+		last := time.Unix(0, int64(binary.BigEndian.Uint64(e.value[8:])))
+		tokens = min(float64(capacity), tokens+float64(now.Sub(last))/float64(interval))
+	}
+
+	ok := tokens >= 1
+	var wait time.Duration
+	if ok {
+		tokens--
+	} else {
+		wait = time.Duration((1 - tokens) * float64(interval))
+	}
+
+	value := make([]byte, 16)
+	binary.BigEndian.PutUint64(value, math.Float64bits(tokens))
+	binary.BigEndian.PutUint64(value[8:], uint64(now.UnixNano()))
+	ttl := time.Duration((float64(capacity) - tokens) * float64(interval))
+	m.put(key, entry{value: value, expires: m.expiry(max(ttl, time.Millisecond))})
+	return ok, int(tokens), wait, nil
+}
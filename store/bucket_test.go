@@ -0,0 +1,75 @@
+package store
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"testing"
+	"time"
+)
+
+func TestMemoryTakeToken(t *testing.T) {
+	base := time.Unix(1000, 0)
+	var now time.Time
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		at        time.Duration // desde base
+		ok        bool
+		remaining int
+		wait      time.Duration
+		tokens    float64 // estado guardado tras la operación
+	}{
+		{"bucket nuevo lleno", 0, true, 2, 0, 2},
+		{"consume", 0, true, 1, 0, 1},
+		{"consume el último", 0, true, 0, 0, 0},
+		{"vacío", 0, false, 0, 10 * time.Second, 0},
+		{"recupera parcialmente", 5 * time.Second, false, 0, 5 * time.Second, 0.5},
+		{"recupera uno", 15 * time.Second, true, 0, 0, 0.5},
+		{"no supera la capacidad", time.Hour, true, 2, 0, 2},
+	}
+	for _, tt := range tests {
+		now = base.Add(tt.at)
+		ok, remaining, wait, err := m.TakeToken(ctx, "b", 3, 10*time.Second)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != tt.ok || remaining != tt.remaining || wait != tt.wait {
+			t.Errorf("%s: TakeToken = %v, %d, %v; se esperaba %v, %d, %v", tt.name, ok, remaining, wait, tt.ok, tt.remaining, tt.wait)
+		}
+
+		// El estado son los tokens (float64) y el instante (Unix ns), en
+		// big endian, y expira cuando el bucket volvería a estar lleno.
+		e := m.items["b"]
+		if len(e.value) != 16 {
+			t.Fatalf("%s: estado de %d bytes, se esperaban 16", tt.name, len(e.value))
+		}
+		tokens := math.Float64frombits(binary.BigEndian.Uint64(e.value))
+		last := int64(binary.BigEndian.Uint64(e.value[8:]))
+		if tokens != tt.tokens || last != now.UnixNano() {
+			t.Errorf("%s: estado = %v tokens en %d, se esperaba %v en %d", tt.name, tokens, last, tt.tokens, now.UnixNano())
+		}
+		if want := now.Add(time.Duration((3 - tt.tokens) * float64(10*time.Second))); !e.expires.Equal(want) {
+			t.Errorf("%s: expira %v, se esperaba %v", tt.name, e.expires, want)
+		}
+	}
+}
+
+func TestMemoryTakeTokenExpired(t *testing.T) {
+	now := time.Unix(1000, 0)
+	m := NewMemory()
+	m.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	m.TakeToken(ctx, "b", 1, time.Second)
+	now = now.Add(time.Second)
+	if _, err := m.Get(ctx, "b"); err != ErrNotFound {
+		t.Errorf("Get = %v, se esperaba ErrNotFound al llenarse el bucket", err)
+	}
+	if ok, remaining, _, _ := m.TakeToken(ctx, "b", 1, time.Second); !ok || remaining != 0 {
+		t.Errorf("TakeToken = %v, %d; se esperaba un bucket nuevo", ok, remaining)
+	}
+}
@@ -0,0 +1,120 @@
+package store
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// sweepEvery indica cada cuántas escrituras Memory elimina las claves
+// expiradas.
+const sweepEvery = 1024
+
+// Memory es un Store en memoria para un solo proceso.
+type Memory struct {
+	mu     sync.Mutex
+	items  map[string]entry
+	writes int
+	now    func() time.Time
+}
+
+type entry struct {
+	value   []byte
+	expires time.Time
+}
+
+func (e entry) expired(now time.Time) bool {
+	return !e.expires.IsZero() && !now.Before(e.expires)
+}
+
+// NewMemory crea un Store en memoria.
+func NewMemory() *Memory {
+	return &Memory{items: make(map[string]entry), now: time.Now}
+}
+
+func (m *Memory) expiry(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+	return m.now().Add(ttl)
+}
+
+// lookup retorna la entrada vigente de key. Debe llamarse con mu tomado.
+func (m *Memory) lookup(key string) (entry, bool) {
+	e, ok := m.items[key]
+	if ok && e.expired(m.now()) {
+		delete(m.items, key)
+		return entry{}, false
+	}
+	return e, ok
+}
+
+// put guarda e y cada sweepEvery escrituras purga las expiradas. Debe
+// llamarse con mu tomado.
+func (m *Memory) put(key string, e entry) {
+	m.items[key] = e
+	m.writes++
+	if m.writes%sweepEvery != 0 {
+		return
+	}
+	now := m.now()
+	for k, v := range m.items {
+		if v.expired(now) {
+			delete(m.items, k)
+		}
+	}
+}
+
+func (m *Memory) Get(_ context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.lookup(key)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return append([]byte(nil), e.value...), nil
+}
+
+func (m *Memory) Set(_ context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.put(key, entry{value: append([]byte(nil), value...), expires: m.expiry(ttl)})
+	return nil
+}
+
+func (m *Memory) SetNX(_ context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.lookup(key); ok {
+		return false, nil
+	}
+	m.put(key, entry{value: append([]byte(nil), value...), expires: m.expiry(ttl)})
+	return true, nil
+}
+
+func (m *Memory) Delete(_ context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.items, key)
+	return nil
+}
+
+func (m *Memory) Incr(_ context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.lookup(key)
+	var n int64
+	if ok {
+		var err error
+		if n, err = strconv.ParseInt(string(e.value), 10, 64); err != nil {
+			return 0, err
+		}
+	} else {
+		e.expires = m.expiry(ttl)
+	}
+	n += delta
+	e.value = strconv.AppendInt(nil, n, 10)
+	m.put(key, e)
+	return n, nil
+}
@@ -0,0 +1,181 @@
+package store
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// RedisClient es lo único que Redis necesita de un cliente: ejecutar un
+// script Lua con EVAL. Cualquier cliente se adapta con pocas líneas; con
+// go-redis, por ejemplo:
+//
+//	type evaler struct{ *redis.Client }
+//
+//	func (c evaler) Eval(ctx context.Context, script string, keys []string, args ...any) (any, error) {
+//		return c.Client.Eval(ctx, script, keys, args...).Result()
+//	}
+//
+// Los enteros de la respuesta deben llegar como int64 y los strings como
+// string o []byte.
+type RedisClient interface {
+	Eval(ctx context.Context, script string, keys []string, args ...any) (any, error)
+}
+
+// Redis es un Store respaldado por Redis 5 o superior, para compartir
+// contadores y límites entre réplicas. Cada operación es un script Lua, de
+// modo que es atómica también en Incr, SetNX y TakeToken. Los TTL tienen
+// resolución de milisegundos.
+type Redis struct {
+	client RedisClient
+}
+
+// NewRedis crea un Store sobre client.
+func NewRedis(client RedisClient) *Redis {
+	return &Redis{client: client}
+}
+
+// Los scripts nunca retornan nil, porque cada cliente lo representa de
+// forma distinta.
+const (
+	redisGet = `local v = redis.call('GET', KEYS[1])
+if v then return {1, v} end
+return {0, ''}`
+
+	redisSet = `if tonumber(ARGV[2]) > 0 then
+  redis.call('SET', KEYS[1], ARGV[1], 'PX', ARGV[2])
+else
+  redis.call('SET', KEYS[1], ARGV[1])
+end
+return 1`
+
+	redisSetNX = `local ok
+if tonumber(ARGV[2]) > 0 then
+  ok = redis.call('SET', KEYS[1], ARGV[1], 'NX', 'PX', ARGV[2])
+else
+  ok = redis.call('SET', KEYS[1], ARGV[1], 'NX')
+end
+if ok then return 1 end
+return 0`
+
+	redisIncr = `local created = redis.call('EXISTS', KEYS[1]) == 0
+local n = redis.call('INCRBY', KEYS[1], ARGV[1])
+if created and tonumber(ARGV[2]) > 0 then
+  redis.call('PEXPIRE', KEYS[1], ARGV[2])
+end
+return n`
+
+	// El reloj es el de Redis, para que todas las réplicas vean el mismo
+	// tiempo. Los tiempos van en microsegundos.
+	redisTakeToken = `local cap = tonumber(ARGV[1])
+local interval = tonumber(ARGV[2])
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000000 + tonumber(t[2])
+local s = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = cap
+if s[1] then
+  tokens = math.min(cap, tonumber(s[1]) + (now - tonumber(s[2])) / interval)
+end
+local ok, wait = 0, 0
+if tokens >= 1 then
+  tokens = tokens - 1
+  ok = 1
+else
+  wait = math.ceil((1 - tokens) * interval)
+end
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', tostring(now))
+redis.call('PEXPIRE', KEYS[1], math.max(1, math.ceil((cap - tokens) * interval / 1000)))
+return {ok, math.floor(tokens), wait}`
+)
+
+func (s *Redis) Get(ctx context.Context, key string) ([]byte, error) {
+	res, err := s.eval(ctx, redisGet, key, 2)
+	if err != nil {
+		return nil, err
+	}
+	found, err := redisInt(res[0])
+	if err != nil {
+		return nil, err
+	}
+	if found == 0 {
+		return nil, ErrNotFound
+	}
+	switch v := res[1].(type) {
+	case string:
+		return []byte(v), nil
+	case []byte:
+		return v, nil
+	}
+	return nil, fmt.Errorf("store: respuesta de redis inesperada: %T", res[1])
+}
+
+func (s *Redis) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	_, err := s.client.Eval(ctx, redisSet, []string{key}, string(value), ttl.Milliseconds())
+	return err
+}
+
+func (s *Redis) SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	res, err := s.client.Eval(ctx, redisSetNX, []string{key}, string(value), ttl.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+	n, err := redisInt(res)
+	return n == 1, err
+}
+
+func (s *Redis) Delete(ctx context.Context, key string) error {
+	_, err := s.client.Eval(ctx, `return redis.call('DEL', KEYS[1])`, []string{key})
+	return err
+}
+
+func (s *Redis) Incr(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error) {
+	res, err := s.client.Eval(ctx, redisIncr, []string{key}, delta, ttl.Milliseconds())
+	if err != nil {
+		return 0, err
+	}
+	return redisInt(res)
+}
+
+// TakeToken implementa TokenBucket. El bucket se guarda como un hash con
+// los tokens restantes y la hora de la última operación.
+func (s *Redis) TakeToken(ctx context.Context, key string, capacity int, interval time.Duration) (bool, int, time.Duration, error) {
+	res, err := s.eval(ctx, redisTakeToken, key, 3, capacity, max(interval.Microseconds(), 1))
+	if err != nil {
+		return false, 0, 0, err
+	}
+	var n [3]int64
+	for i := range n {
+		if n[i], err = redisInt(res[i]); err != nil {
+			return false, 0, 0, err
+		}
+	}
+	return n[0] == 1, int(n[1]), time.Duration(n[2]) * time.Microsecond, nil
+}
+
+// eval ejecuta un script que retorna un arreglo de size elementos.
+func (s *Redis) eval(ctx context.Context, script, key string, size int, args ...any) ([]any, error) {
+	res, err := s.client.Eval(ctx, script, []string{key}, args...)
+	if err != nil {
+		return nil, err
+	}
+	arr, ok := res.([]any)
+	if !ok || len(arr) != size {
+		return nil, fmt.Errorf("store: respuesta de redis inesperada: %v", res)
+	}
+	return arr, nil
+}
+
+func redisInt(v any) (int64, error) {
+	switch n := v.(type) {
+	case int64:
+		return n, nil
+	case int:
+		return int64(n), nil
+	case string:
+		return strconv.ParseInt(n, 10, 64)
+	case []byte:
+		return strconv.ParseInt(string(n), 10, 64)
+	}
+	return 0, fmt.Errorf("store: respuesta de redis inesperada: %T", v)
+}
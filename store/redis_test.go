@@ -0,0 +1,244 @@
+package store
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fakeRedis reproduce en Go los scripts de Redis sobre un mapa y verifica
+// las claves y argumentos con que se invocan. Las respuestas usan los
+// tipos que entregan los clientes reales: int64, string y []any.
+type fakeRedis struct {
+	t       *testing.T
+	strings map[string]string
+	ttls    map[string]int64 // milisegundos
+	hashes  map[string][2]float64
+	now     int64 // microsegundos, como TIME
+	// bytes hace que GET responda []byte en vez de string.
+	bytes bool
+	// reply, si no es nil, reemplaza la respuesta del script.
+	reply any
+	err   error
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	return &fakeRedis{t: t, strings: map[string]string{}, ttls: map[string]int64{}, hashes: map[string][2]float64{}}
+}
+
+func (f *fakeRedis) Eval(_ context.Context, script string, keys []string, args ...any) (any, error) {
+	f.t.Helper()
+	if len(keys) != 1 {
+		f.t.Fatalf("keys = %v, se esperaba una", keys)
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	key := keys[0]
+	res := f.run(script, key, args)
+	if f.reply != nil {
+		return f.reply, nil
+	}
+	return res, nil
+}
+
+func (f *fakeRedis) run(script, key string, args []any) any {
+	f.t.Helper()
+	argc := map[string]int{redisGet: 0, redisSet: 2, redisSetNX: 2, redisIncr: 2, redisTakeToken: 2}
+	if want, ok := argc[script]; ok && len(args) != want {
+		f.t.Fatalf("%d argumentos, se esperaban %d: %v", len(args), want, args)
+	}
+	switch script {
+	case redisGet:
+		v, ok := f.strings[key]
+		switch {
+		case !ok:
+			return []any{int64(0), ""}
+		case f.bytes:
+			return []any{int64(1), []byte(v)}
+		}
+		return []any{int64(1), v}
+	case redisSet, redisSetNX:
+		value := args[0].(string)
+		ttl := args[1].(int64)
+		if _, exists := f.strings[key]; script == redisSetNX && exists {
+			return int64(0)
+		}
+		f.strings[key] = value
+		delete(f.ttls, key)
+		if ttl > 0 {
+			f.ttls[key] = ttl
+		}
+		return int64(1)
+	case redisIncr:
+		delta := args[0].(int64)
+		ttl := args[1].(int64)
+		n, created := int64(0), true
+		if v, ok := f.strings[key]; ok {
+			n, _ = strconv.ParseInt(v, 10, 64)
+			created = false
+		}
+		n += delta
+		f.strings[key] = strconv.FormatInt(n, 10)
+		if created && ttl > 0 {
+			f.ttls[key] = ttl
+		}
+		return n
+	case redisTakeToken:
+		capacity := float64(args[0].(int))
+		interval := float64(args[1].(int64))
+		tokens := capacity
+		if s, ok := f.hashes[key]; ok {
+			tokens = math.Min(capacity, s[0]+(float64(f.now)-s[1])/interval)
+		}
+		ok, wait := int64(0), int64(0)
+		if tokens >= 1 {
+			tokens--
+			ok = 1
+		} else {
+			wait = int64(math.Ceil((1 - tokens) * interval))
+		}
+		f.hashes[key] = [2]float64{tokens, float64(f.now)}
+		f.ttls[key] = int64(math.Max(1, math.Ceil((capacity-tokens)*interval/1000)))
+		// Algunos clientes entregan los enteros como texto.
+		return []any{ok, strconv.FormatInt(int64(math.Floor(tokens)), 10), wait}
+	case `return redis.call('DEL', KEYS[1])`:
+		delete(f.strings, key)
+		delete(f.ttls, key)
+		return int64(1)
+	}
+	f.t.Fatalf("script desconocido: %s", script)
+	return nil
+}
+
+func TestRedisStringOps(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeRedis(t)
+	s := NewRedis(f)
+
+	if _, err := s.Get(ctx, "a"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get de clave inexistente: err = %v, se esperaba ErrNotFound", err)
+	}
+	if err := s.Set(ctx, "a", []byte("uno"), 1500*time.Millisecond); err != nil || f.ttls["a"] != 1500 {
+		t.Errorf("Set: err = %v, ttl = %d ms, se esperaba 1500", err, f.ttls["a"])
+	}
+	for _, bytes := range []bool{false, true} {
+		f.bytes = bytes
+		if v, err := s.Get(ctx, "a"); err != nil || string(v) != "uno" {
+			t.Errorf("Get (bytes %v) = %q, %v; se esperaba uno", bytes, v, err)
+		}
+	}
+	if err := s.Set(ctx, "a", []byte("dos"), 0); err != nil || f.ttls["a"] != 0 {
+		t.Errorf("Set sin ttl: err = %v, ttl = %d", err, f.ttls["a"])
+	}
+
+	if ok, err := s.SetNX(ctx, "a", []byte("tres"), time.Second); ok || err != nil || f.strings["a"] != "dos" {
+		t.Errorf("SetNX sobre clave existente = %v, %v (valor %q)", ok, err, f.strings["a"])
+	}
+	if ok, err := s.SetNX(ctx, "b", []byte("x"), time.Second); !ok || err != nil || f.ttls["b"] != 1000 {
+		t.Errorf("SetNX sobre clave nueva = %v, %v (ttl %d)", ok, err, f.ttls["b"])
+	}
+	if err := s.Delete(ctx, "b"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Get(ctx, "b"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get tras Delete: err = %v", err)
+	}
+}
+
+func TestRedisIncr(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeRedis(t)
+	s := NewRedis(f)
+
+	tests := []struct {
+		name  string
+		delta int64
+		ttl   time.Duration
+		want  int64
+	}{
+		{"crea con ttl", 2, time.Minute, 2},
+		{"conserva el ttl", 3, time.Hour, 5},
+		{"decrementa", -1, time.Hour, 4},
+	}
+	for _, tt := range tests {
+		n, err := s.Incr(ctx, "c", tt.delta, tt.ttl)
+		if err != nil || n != tt.want {
+			t.Errorf("%s: Incr = %d, %v; se esperaba %d", tt.name, n, err, tt.want)
+		}
+		if f.ttls["c"] != time.Minute.Milliseconds() {
+			t.Errorf("%s: ttl = %d ms, se esperaba %d", tt.name, f.ttls["c"], time.Minute.Milliseconds())
+		}
+	}
+}
+
+func TestRedisTakeToken(t *testing.T) {
+	ctx := context.Background()
+	f := newFakeRedis(t)
+	s := NewRedis(f)
+
+	tests := []struct {
+		name      string
+		at        int64 // microsegundos
+		ok        bool
+		remaining int
+		wait      time.Duration
+	}{
+		{"bucket nuevo", 0, true, 1, 0},
+		{"consume el último", 0, true, 0, 0},
+		{"vacío", 0, false, 0, 500 * time.Millisecond},
+		{"recupera parcialmente", 250_000, false, 0, 250 * time.Millisecond},
+		{"recupera uno", 500_000, true, 0, 0},
+	}
+	for _, tt := range tests {
+		f.now = tt.at
+		ok, remaining, wait, err := s.TakeToken(ctx, "b", 2, 500*time.Millisecond)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ok != tt.ok || remaining != tt.remaining || wait != tt.wait {
+			t.Errorf("%s: TakeToken = %v, %d, %v; se esperaba %v, %d, %v", tt.name, ok, remaining, wait, tt.ok, tt.remaining, tt.wait)
+		}
+	}
+	if f.ttls["b"] != 1000 {
+		t.Errorf("ttl = %d ms, se esperaba 1000 (dos tokens a 500ms)", f.ttls["b"])
+	}
+
+	// Un interval menor a un microsegundo se envía como 1.
+	f.now = 0
+	s.TakeToken(ctx, "n", 1, time.Nanosecond)
+	if f.ttls["n"] != 1 {
+		t.Errorf("ttl = %d ms, se esperaba el mínimo de 1", f.ttls["n"])
+	}
+}
+
+func TestRedisReplyErrors(t *testing.T) {
+	ctx := context.Background()
+	boom := errors.New("conexión cerrada")
+	tests := []struct {
+		name  string
+		reply any
+		err   error
+		call  func(s *Redis) error
+	}{
+		{"error del cliente", nil, boom, func(s *Redis) error { _, err := s.Incr(ctx, "k", 1, 0); return err }},
+		{"arreglo corto", []any{int64(1)}, nil, func(s *Redis) error { _, err := s.Get(ctx, "k"); return err }},
+		{"no es arreglo", int64(1), nil, func(s *Redis) error { _, _, _, err := s.TakeToken(ctx, "k", 1, time.Second); return err }},
+		{"valor de tipo inesperado", []any{int64(1), 3.5}, nil, func(s *Redis) error { _, err := s.Get(ctx, "k"); return err }},
+		{"entero inválido", "x", nil, func(s *Redis) error { _, err := s.SetNX(ctx, "k", nil, 0); return err }},
+		{"token no numérico", []any{int64(1), "uno", int64(0)}, nil, func(s *Redis) error { _, _, _, err := s.TakeToken(ctx, "k", 1, time.Second); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newFakeRedis(t)
+			f.reply, f.err = tt.reply, tt.err
+			err := tt.call(NewRedis(f))
+			if err == nil || (tt.err != nil && !errors.Is(err, tt.err)) {
+				t.Errorf("err = %v, se esperaba un error", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+// Package store define el almacenamiento clave-valor con expiración que
+// comparten los middlewares con estado (deduplicación, rate limit, ...),
+// de modo que un mismo backend sirva a todos y pueda compartirse entre
+// réplicas.
+package store
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNotFound indica que la clave no existe o expiró.
+var ErrNotFound = errors.New("store: clave inexistente")
+
+// Store es un almacenamiento clave-valor con TTL. Las implementaciones
+// deben ser seguras para uso concurrente y, si se comparten entre
+// réplicas, cada operación debe ser atómica en el backend: los límites de
+// RateLimit y Dedup dependen de que dos réplicas nunca vean el mismo
+// SetNX como exitoso ni pierdan un Incr. Un ttl <= 0 significa sin
+// expiración. Memory sirve a un solo proceso; Redis, a varias réplicas.
+type Store interface {
+	// Get retorna ErrNotFound si key no existe o expiró.
+	Get(ctx context.Context, key string) ([]byte, error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// SetNX guarda value solo si key no existe y reporta si lo guardó.
+	SetNX(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	Delete(ctx context.Context, key string) error
+	// Incr incrementa el contador key en delta y retorna el nuevo valor.
+	// Si key no existe se crea con ttl; si existe, su expiración no cambia.
+	Incr(ctx context.Context, key string, delta int64, ttl time.Duration) (int64, error)
+}
@@ -0,0 +1,301 @@
+// Package tlsinfo expone en el contexto de cada petición los datos de la
+// negociación TLS (versión, cipher, SNI, ALPN) y la huella JA3 del
+// ClientHello, para logging y detección de bots. Sólo aplica a drivers que
+// sirven TLS mediante net/http.
+//
+// crypto/tls no expone el ClientHello tal como llegó, así que Capture
+// envuelve el listener para registrar sus bytes:
+//
+//	c := tlsinfo.NewCapture()
+//	srv := &http.Server{
+//		TLSConfig:   cfg,
+//		ConnContext: c.ConnContext,
+//		Handler:     c.Middleware()(r),
+//	}
+//	ln, err := net.Listen("tcp", ":443")
+//	...
+//	err = srv.ServeTLS(c.Listener(ln), "", "")
+package tlsinfo
+
+import (
+	"context"
+	"crypto/md5"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// Info describe la conexión TLS de una petición.
+type Info struct {
+	Version    string
+	Cipher     string
+	ServerName string
+	ALPN       string
+	// JA3 es la cadena de huella y JA3Hash su MD5 en hexadecimal. Están
+	// vacíos si la conexión no se aceptó con Capture.Listener.
+	JA3     string
+	JA3Hash string
+}
+
+type ctxKey struct{}
+type connKey struct{}
+
+// WithInfo guarda info en ctx. Middleware lo invoca por cada petición TLS;
+// sirve también para probar handlers que usan FromContext.
+func WithInfo(ctx context.Context, info Info) context.Context {
+	return context.WithValue(ctx, ctxKey{}, info)
+}
+
+// FromContext retorna la información TLS guardada por Middleware.
+func FromContext(ctx context.Context) (Info, bool) {
+	info, ok := ctx.Value(ctxKey{}).(Info)
+	return info, ok
+}
+
+// maxHello acota los bytes que se registran por conexión si el
+// ClientHello no termina nunca.
+const maxHello = 16 << 10
+
+// helloConn registra los bytes leídos de la conexión hasta completar el
+// ClientHello, y calcula su huella la primera vez que se pide.
+type helloConn struct {
+	net.Conn
+
+	mu       sync.Mutex
+	buf      []byte
+	complete bool
+	ja3      string
+}
+
+func (c *helloConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p)
+	c.mu.Lock()
+	if !c.complete && n > 0 {
+		c.buf = append(c.buf, p[:n]...)
+		_, done, err := handshakeMessage(c.buf)
+		c.complete = done || err != nil || len(c.buf) >= maxHello
+	}
+	c.mu.Unlock()
+	return n, err
+}
+
+// fingerprint retorna la cadena JA3 del ClientHello, o "" si no se pudo
+// interpretar. Los bytes registrados se liberan tras el primer cálculo.
+func (c *helloConn) fingerprint() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.complete && c.buf != nil {
+		c.ja3, _ = JA3(c.buf)
+		c.buf = nil
+	}
+	return c.ja3
+}
+
+type helloListener struct {
+	net.Listener
+}
+
+func (l helloListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return &helloConn{Conn: conn}, nil
+}
+
+// Capture correlaciona cada ClientHello con las peticiones de su conexión.
+type Capture struct{}
+
+// NewCapture crea un Capture.
+func NewCapture() *Capture {
+	return &Capture{}
+}
+
+// Listener envuelve ln para registrar el ClientHello de cada conexión
+// aceptada. Se pasa a http.Server.ServeTLS.
+func (c *Capture) Listener(ln net.Listener) net.Listener {
+	return helloListener{ln}
+}
+
+// ConnContext se asigna a http.Server.ConnContext.
+func (c *Capture) ConnContext(ctx context.Context, conn net.Conn) context.Context {
+	if tc, ok := conn.(*tls.Conn); ok {
+		conn = tc.NetConn()
+	}
+	if hc, ok := conn.(*helloConn); ok {
+		return context.WithValue(ctx, connKey{}, hc)
+	}
+	return ctx
+}
+
+// Middleware guarda Info en el contexto de las peticiones servidas por TLS.
+func (c *Capture) Middleware() router.Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.TLS == nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+			info := Info{
+				Version:    tls.VersionName(r.TLS.Version),
+				Cipher:     tls.CipherSuiteName(r.TLS.CipherSuite),
+				ServerName: r.TLS.ServerName,
+				ALPN:       r.TLS.NegotiatedProtocol,
+			}
+			if hc, ok := r.Context().Value(connKey{}).(*helloConn); ok {
+				if info.JA3 = hc.fingerprint(); info.JA3 != "" {
+					info.JA3Hash = Hash(info.JA3)
+				}
+			}
+			next.ServeHTTP(w, r.WithContext(WithInfo(r.Context(), info)))
+		})
+	}
+}
+
+// Hash retorna el MD5 en hexadecimal de una cadena JA3, la forma en que
+// suelen publicarse las huellas.
+func Hash(ja3 string) string {
+	sum := md5.Sum([]byte(ja3))
+	return hex.EncodeToString(sum[:])
+}
+
+var errHello = errors.New("tlsinfo: ClientHello mal formado")
+
+// handshakeMessage reúne el primer mensaje de handshake de los registros
+// TLS de data, que puede venir fragmentado en varios. done indica que el
+// mensaje está completo.
+func handshakeMessage(data []byte) (msg []byte, done bool, err error) {
+	for len(data) >= 5 {
+		if data[0] != 22 { // handshake
+			return nil, false, errHello
+		}
+		n := int(data[3])<<8 | int(data[4])
+		if len(data) < 5+n {
+			break
+		}
+		msg = append(msg, data[5:5+n]...)
+		data = data[5+n:]
+		if len(msg) >= 4 {
+			size := 4 + (int(msg[1])<<16 | int(msg[2])<<8 | int(msg[3]))
+			if len(msg) >= size {
+				return msg[:size], true, nil
+			}
+		}
+	}
+	return msg, false, nil
+}
+
+// cursor lee campos de un mensaje TLS; tras el primer error las lecturas
+// retornan cero.
+type cursor struct {
+	b   []byte
+	bad bool
+}
+
+func (c *cursor) bytes(n int) []byte {
+	if c.bad || len(c.b) < n {
+		c.bad = true
+		return nil
+	}
+	v := c.b[:n]
+	c.b = c.b[n:]
+	return v
+}
+
+func (c *cursor) uint8() int {
+	b := c.bytes(1)
+	if b == nil {
+		return 0
+	}
+	return int(b[0])
+}
+
+func (c *cursor) uint16() int {
+	b := c.bytes(2)
+	if b == nil {
+		return 0
+	}
+	return int(b[0])<<8 | int(b[1])
+}
+
+func (c *cursor) uint16s(n int) []uint16 {
+	b := c.bytes(n)
+	out := make([]uint16, 0, len(b)/2)
+	for i := 0; i+1 < len(b); i += 2 {
+		out = append(out, uint16(b[i])<<8|uint16(b[i+1]))
+	}
+	return out
+}
+
+// JA3 arma la cadena JA3 a partir de los registros TLS que traen el
+// ClientHello: versión legada, ciphers, extensiones en el orden enviado,
+// curvas y formatos de punto, sin valores GREASE (RFC 8701).
+func JA3(records []byte) (string, error) {
+	msg, done, err := handshakeMessage(records)
+	if err != nil {
+		return "", err
+	}
+	if !done || msg[0] != 1 { // client_hello
+		return "", errHello
+	}
+	c := &cursor{b: msg[4:]}
+	version := c.uint16()
+	c.bytes(32) // random
+	c.bytes(c.uint8())
+	ciphers := c.uint16s(c.uint16())
+	c.bytes(c.uint8())
+
+	var extensions, curves, points []uint16
+	if len(c.b) > 0 {
+		ext := &cursor{b: c.bytes(c.uint16())}
+		for len(ext.b) > 0 && !ext.bad {
+			typ := uint16(ext.uint16())
+			body := &cursor{b: ext.bytes(ext.uint16())}
+			extensions = append(extensions, typ)
+			switch typ {
+			case 10: // supported_groups
+				curves = body.uint16s(body.uint16())
+			case 11: // ec_point_formats
+				for _, p := range body.bytes(body.uint8()) {
+					points = append(points, uint16(p))
+				}
+			}
+			c.bad = c.bad || body.bad
+		}
+		c.bad = c.bad || ext.bad
+	}
+	if c.bad {
+		return "", errHello
+	}
+
+	return strings.Join([]string{
+		strconv.Itoa(version),
+		join(ciphers),
+		join(extensions),
+		join(curves),
+		join(points),
+	}, ","), nil
+}
+
+func join(values []uint16) string {
+	parts := make([]string, 0, len(values))
+	for _, v := range values {
+		if isGREASE(v) {
+			continue
+		}
+		parts = append(parts, strconv.Itoa(int(v)))
+	}
+	return strings.Join(parts, "-")
+}
+
+// isGREASE reconoce los valores reservados por RFC 8701 (0x?a?a).
+func isGREASE(v uint16) bool {
+	return v&0x0f0f == 0x0a0a && v>>8 == v&0xff
+}
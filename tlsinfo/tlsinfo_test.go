@@ -0,0 +1,166 @@
+package tlsinfo
+
+import (
+	"crypto/tls"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// clientHello arma los registros TLS de un ClientHello con los valores
+// indicados, partido en registros de a lo sumo split bytes.
+func clientHello(version uint16, ciphers, extensions, curves []uint16, points []byte, split int) []byte {
+	u16 := func(b []byte, v int) []byte { return append(b, byte(v>>8), byte(v)) }
+
+	var ext []byte
+	for _, typ := range extensions {
+		var body []byte
+		switch typ {
+		case 10:
+			body = u16(body, 2*len(curves))
+			for _, c := range curves {
+				body = u16(body, int(c))
+			}
+		case 11:
+			body = append(append(body, byte(len(points))), points...)
+		}
+		ext = u16(ext, int(typ))
+		ext = append(u16(ext, len(body)), body...)
+	}
+
+	var hello []byte
+	hello = u16(hello, int(version))
+	hello = append(hello, make([]byte, 32)...) // random
+	hello = append(hello, 0)                   // session id
+	hello = u16(hello, 2*len(ciphers))
+	for _, c := range ciphers {
+		hello = u16(hello, int(c))
+	}
+	hello = append(hello, 1, 0) // compresión nula
+	hello = u16(hello, len(ext))
+	hello = append(hello, ext...)
+
+	msg := append([]byte{1, byte(len(hello) >> 16), byte(len(hello) >> 8), byte(len(hello))}, hello...)
+	var records []byte
+	for len(msg) > 0 {
+		n := min(split, len(msg))
+		records = append(records, 22, 3, 1)
+		records = u16(records, n)
+		records = append(records, msg[:n]...)
+		msg = msg[n:]
+	}
+	return records
+}
+
+// Vector del README de JA3 (salesforce/ja3).
+const (
+	readmeJA3  = "769,47-53-5-10-49161-49162-49171-49172-50-56-19-4,0-10-11,23-24-25,0"
+	readmeHash = "ada70206e40642a3e4461f35503241d5"
+)
+
+func TestJA3Vector(t *testing.T) {
+	ciphers := []uint16{47, 53, 5, 10, 49161, 49162, 49171, 49172, 50, 56, 19, 4}
+	greased := append([]uint16{0x0a0a}, ciphers...)
+
+	tests := []struct {
+		name       string
+		version    uint16
+		ciphers    []uint16
+		extensions []uint16
+		curves     []uint16
+		split      int
+		want       string
+	}{
+		{"README", tls.VersionTLS10, ciphers, []uint16{0, 10, 11}, []uint16{23, 24, 25}, 1 << 14, readmeJA3},
+		{"fragmentado", tls.VersionTLS10, ciphers, []uint16{0, 10, 11}, []uint16{23, 24, 25}, 7, readmeJA3},
+		{"sin GREASE", tls.VersionTLS10, greased, []uint16{0x1a1a, 0, 10, 11}, []uint16{0xfafa, 23, 24, 25}, 1 << 14, readmeJA3},
+		{"versión 1.2", tls.VersionTLS12, ciphers, []uint16{0, 10, 11}, []uint16{23, 24, 25}, 1 << 14, "771" + strings.TrimPrefix(readmeJA3, "769")},
+		{"orden de extensiones", tls.VersionTLS10, ciphers, []uint16{11, 0, 10}, []uint16{23, 24, 25}, 1 << 14, "769,47-53-5-10-49161-49162-49171-49172-50-56-19-4,11-0-10,23-24-25,0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := JA3(clientHello(tt.version, tt.ciphers, tt.extensions, tt.curves, []byte{0}, tt.split))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("JA3 =\n%s\nse esperaba\n%s", got, tt.want)
+			}
+		})
+	}
+	if got := Hash(readmeJA3); got != readmeHash {
+		t.Errorf("Hash = %s, se esperaba %s", got, readmeHash)
+	}
+}
+
+func TestJA3Malformed(t *testing.T) {
+	full := clientHello(tls.VersionTLS12, []uint16{47}, []uint16{10}, []uint16{23}, nil, 1<<14)
+	tests := []struct {
+		name string
+		data []byte
+	}{
+		{"vacío", nil},
+		{"incompleto", full[:len(full)-3]},
+		{"no es handshake", append([]byte{23}, full[1:]...)},
+		{"no es ClientHello", append(append([]byte{}, full[:5]...), append([]byte{2}, full[6:]...)...)},
+		{"extensión truncada", func() []byte {
+			b := append([]byte{}, full...)
+			b[len(b)-5]++ // largo de supported_groups
+			return b
+		}()},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := JA3(tt.data); err == nil {
+				t.Errorf("JA3 = %q, se esperaba un error", got)
+			}
+		})
+	}
+}
+
+func TestCapture(t *testing.T) {
+	c := NewCapture()
+	srv := httptest.NewUnstartedServer(c.Middleware()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		info, ok := FromContext(r.Context())
+		if !ok {
+			t.Error("falta Info en el contexto")
+		}
+		io.WriteString(w, info.Version+"|"+info.JA3+"|"+info.JA3Hash)
+	})))
+	srv.Listener = c.Listener(srv.Listener)
+	srv.Config.ConnContext = c.ConnContext
+	srv.TLS = &tls.Config{MinVersion: tls.VersionTLS10}
+	srv.StartTLS()
+	defer srv.Close()
+
+	tests := []struct {
+		name        string
+		maxVersion  uint16
+		wantVersion string
+		wantPrefix  string
+	}{
+		{"TLS 1.3", tls.VersionTLS13, "TLS 1.3", "771,"},
+		{"TLS 1.1", tls.VersionTLS11, "TLS 1.1", "770,"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := srv.Client()
+			tr := client.Transport.(*http.Transport).Clone()
+			tr.TLSClientConfig.MaxVersion = tt.maxVersion
+			tr.TLSClientConfig.MinVersion = tls.VersionTLS10
+			client.Transport = tr
+			resp, err := client.Get(srv.URL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			body, _ := io.ReadAll(resp.Body)
+			resp.Body.Close()
+			parts := strings.Split(string(body), "|")
+			if len(parts) != 3 || parts[0] != tt.wantVersion || !strings.HasPrefix(parts[1], tt.wantPrefix) || parts[2] != Hash(parts[1]) {
+				t.Errorf("Info = %q, se esperaba versión %s y JA3 %s...", body, tt.wantVersion, tt.wantPrefix)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package tus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ErrNotFound indica que la subida no existe.
+var ErrNotFound = errors.New("tus: subida inexistente")
+
+// Upload describe el estado de una subida.
+type Upload struct {
+	ID        string
+	Size      int64
+	Offset    int64
+	Metadata  map[string]string
+	ExpiresAt time.Time
+}
+
+// Done reporta si se recibieron todos los bytes.
+func (u Upload) Done() bool { return u.Offset == u.Size }
+
+// Store persiste las subidas. Handler serializa el acceso a cada subida
+// dentro del proceso; un Store compartido entre réplicas debe cuidar su
+// propia consistencia.
+type Store interface {
+	Create(ctx context.Context, u Upload) error
+	Get(ctx context.Context, id string) (Upload, error)
+	// Append agrega r al final de la subida id, que está en offset, y
+	// retorna los bytes escritos. Si r falla a mitad de camino, los bytes
+	// ya escritos deben conservarse y reflejarse en el offset.
+	Append(ctx context.Context, id string, offset int64, r io.Reader) (int64, error)
+	// Touch actualiza la expiración.
+	Touch(ctx context.Context, id string, expiresAt time.Time) error
+	Delete(ctx context.Context, id string) error
+}
+
+// FileStore guarda cada subida en Dir como <id>.bin (contenido) y
+// <id>.json (estado).
+type FileStore struct {
+	Dir string
+}
+
+// paths retorna los archivos de la subida id. Solo se aceptan IDs con la
+// forma que genera Handler, de modo que un ID no pueda salir de Dir.
+func (s FileStore) paths(id string) (data, info string, err error) {
+	if !validID(id) {
+		return "", "", ErrNotFound
+	}
+	base := filepath.Join(s.Dir, id)
+	return base + ".bin", base + ".json", nil
+}
+
+func (s FileStore) Create(_ context.Context, u Upload) error {
+	data, _, err := s.paths(u.ID)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(data, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return s.save(u)
+}
+
+func (s FileStore) Get(_ context.Context, id string) (Upload, error) {
+	data, info, err := s.paths(id)
+	if err != nil {
+		return Upload{}, err
+	}
+	b, err := os.ReadFile(info)
+	if errors.Is(err, os.ErrNotExist) {
+		return Upload{}, ErrNotFound
+	}
+	if err != nil {
+		return Upload{}, err
+	}
+	var u Upload
+	if err := json.Unmarshal(b, &u); err != nil {
+		return Upload{}, err
+	}
+	// El tamaño real del archivo manda: cubre una caída entre escribir
+	// los datos y guardar el estado.
+	if st, err := os.Stat(data); err == nil {
+		u.Offset = st.Size()
+	}
+	return u, nil
+}
+
+func (s FileStore) Append(ctx context.Context, id string, offset int64, r io.Reader) (int64, error) {
+	data, _, err := s.paths(id)
+	if err != nil {
+		return 0, err
+	}
+	f, err := os.OpenFile(data, os.O_WRONLY, 0)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return 0, ErrNotFound
+		}
+		return 0, err
+	}
+	defer f.Close()
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.Copy(f, r)
+	if serr := f.Sync(); err == nil {
+		err = serr
+	}
+	u, gerr := s.Get(ctx, id)
+	if gerr == nil {
+		u.Offset = offset + n
+		gerr = s.save(u)
+	}
+	if err == nil {
+		err = gerr
+	}
+	return n, err
+}
+
+func (s FileStore) Touch(ctx context.Context, id string, expiresAt time.Time) error {
+	u, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+	u.ExpiresAt = expiresAt
+	return s.save(u)
+}
+
+func (s FileStore) Delete(_ context.Context, id string) error {
+	data, info, err := s.paths(id)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(info)
+	if errors.Is(err, os.ErrNotExist) {
+		return ErrNotFound
+	}
+	os.Remove(data)
+	return err
+}
+
+// save escribe el estado de forma atómica (temporal + rename).
+func (s FileStore) save(u Upload) error {
+	_, info, err := s.paths(u.ID)
+	if err != nil {
+		return err
+	}
+	b, err := json.Marshal(u)
+	if err != nil {
+		return err
+	}
+	tmp := info + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, info)
+}
+
+// Path retorna la ruta del contenido de la subida id, para procesarlo al
+// completarse, o "" si id no es válido.
+func (s FileStore) Path(id string) string {
+	data, _, _ := s.paths(id)
+	return data
+}
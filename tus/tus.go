@@ -0,0 +1,376 @@
+// Package tus implementa el protocolo de subidas reanudables tus 1.0.0
+// (https://tus.io) con las extensiones creation, expiration y
+// termination, sobre un Store intercambiable.
+//
+//	h := &tus.Handler{Store: tus.FileStore{Dir: "/var/uploads"}, MaxSize: 5 << 30}
+//	h.Register(r, "/files")
+package tus
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// Version es la versión del protocolo implementada.
+const Version = "1.0.0"
+
+const extensions = "creation,expiration,termination"
+
+// Handler atiende el protocolo tus.
+type Handler struct {
+	Store Store
+	// MaxSize limita el tamaño de cada subida; cero no limita.
+	MaxSize int64
+	// Expiration es el plazo desde la última actividad tras el cual una
+	// subida incompleta expira; cero no expira.
+	Expiration time.Duration
+	// OnComplete se invoca cuando una subida recibe su último byte.
+	OnComplete func(ctx context.Context, u Upload)
+
+	rt    router.Router
+	locks sync.Map // id → *sync.Mutex
+}
+
+// Register registra en r las rutas del protocolo bajo prefix: el endpoint
+// de creación en prefix y cada subida en prefix/:id. Como el Router no
+// expone PATCH ni OPTIONS por separado, ambas rutas se registran con Any.
+func (h *Handler) Register(r router.Router, prefix string) {
+	prefix = strings.TrimSuffix(prefix, "/")
+	h.rt = r
+	r.Any(prefix, h.collection)
+	r.Any(prefix+"/:id", h.resource)
+}
+
+func method(r *http.Request) string {
+	if m := r.Header.Get("X-HTTP-Method-Override"); m != "" && r.Method == http.MethodPost {
+		return strings.ToUpper(m)
+	}
+	return r.Method
+}
+
+// preamble escribe Tus-Resumable y valida la versión del cliente.
+func preamble(w http.ResponseWriter, r *http.Request) bool {
+	w.Header().Set("Tus-Resumable", Version)
+	if method(r) == http.MethodOptions {
+		return true
+	}
+	if r.Header.Get("Tus-Resumable") != Version {
+		w.Header().Set("Tus-Version", Version)
+		http.Error(w, "versión de tus no soportada", http.StatusPreconditionFailed)
+		return false
+	}
+	return true
+}
+
+func (h *Handler) collection(w http.ResponseWriter, r *http.Request) {
+	if !preamble(w, r) {
+		return
+	}
+	switch method(r) {
+	case http.MethodOptions:
+		h.options(w)
+	case http.MethodPost:
+		h.create(w, r)
+	default:
+		w.Header().Set("Allow", "OPTIONS, POST")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) resource(w http.ResponseWriter, r *http.Request) {
+	if !preamble(w, r) {
+		return
+	}
+	id := h.rt.Param(r, "id")
+	if !validID(id) {
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return
+	}
+	switch method(r) {
+	case http.MethodOptions:
+		h.options(w)
+	case http.MethodHead:
+		h.head(w, r, id)
+	case http.MethodPatch:
+		h.patch(w, r, id)
+	case http.MethodDelete:
+		h.delete(w, r, id)
+	default:
+		w.Header().Set("Allow", "OPTIONS, HEAD, PATCH, DELETE")
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (h *Handler) options(w http.ResponseWriter) {
+	hd := w.Header()
+	hd.Set("Tus-Version", Version)
+	hd.Set("Tus-Extension", extensions)
+	if h.MaxSize > 0 {
+		hd.Set("Tus-Max-Size", strconv.FormatInt(h.MaxSize, 10))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *Handler) create(w http.ResponseWriter, r *http.Request) {
+	size, err := strconv.ParseInt(r.Header.Get("Upload-Length"), 10, 64)
+	if err != nil || size < 0 {
+		http.Error(w, "Upload-Length inválido o ausente", http.StatusBadRequest)
+		return
+	}
+	if h.MaxSize > 0 && size > h.MaxSize {
+		http.Error(w, "la subida excede Tus-Max-Size", http.StatusRequestEntityTooLarge)
+		return
+	}
+	meta, err := parseMetadata(r.Header.Get("Upload-Metadata"))
+	if err != nil {
+		http.Error(w, "Upload-Metadata inválido", http.StatusBadRequest)
+		return
+	}
+
+	u := Upload{ID: newID(), Size: size, Metadata: meta, ExpiresAt: h.expiry()}
+	if err := h.Store.Create(r.Context(), u); err != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	h.setExpires(w, u)
+	// La ruta de la petición ya incluye el prefijo de grupos y BasePath,
+	// que el prefix de Register no conoce.
+	w.Header().Set("Location", strings.TrimSuffix(r.URL.Path, "/")+"/"+u.ID)
+	w.WriteHeader(http.StatusCreated)
+	if size == 0 && h.OnComplete != nil {
+		h.OnComplete(r.Context(), u)
+	}
+}
+
+// load obtiene la subida id respondiendo 404 o 410 si no está disponible.
+func (h *Handler) load(w http.ResponseWriter, r *http.Request, id string) (Upload, bool) {
+	u, err := h.Store.Get(r.Context(), id)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+		return u, false
+	case err != nil:
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return u, false
+	case !u.Done() && !u.ExpiresAt.IsZero() && time.Now().After(u.ExpiresAt):
+		http.Error(w, "la subida expiró", http.StatusGone)
+		return u, false
+	}
+	return u, true
+}
+
+func (h *Handler) head(w http.ResponseWriter, r *http.Request, id string) {
+	u, ok := h.load(w, r, id)
+	if !ok {
+		return
+	}
+	hd := w.Header()
+	hd.Set("Cache-Control", "no-store")
+	hd.Set("Upload-Offset", strconv.FormatInt(u.Offset, 10))
+	hd.Set("Upload-Length", strconv.FormatInt(u.Size, 10))
+	if len(u.Metadata) > 0 {
+		hd.Set("Upload-Metadata", formatMetadata(u.Metadata))
+	}
+	h.setExpires(w, u)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *Handler) patch(w http.ResponseWriter, r *http.Request, id string) {
+	if r.Header.Get("Content-Type") != "application/offset+octet-stream" {
+		http.Error(w, "se requiere application/offset+octet-stream", http.StatusUnsupportedMediaType)
+		return
+	}
+	offset, err := strconv.ParseInt(r.Header.Get("Upload-Offset"), 10, 64)
+	if err != nil || offset < 0 {
+		http.Error(w, "Upload-Offset inválido o ausente", http.StatusBadRequest)
+		return
+	}
+
+	mu, _ := h.locks.LoadOrStore(id, new(sync.Mutex))
+	mu.(*sync.Mutex).Lock()
+	defer mu.(*sync.Mutex).Unlock()
+
+	u, ok := h.load(w, r, id)
+	if !ok || u.Done() {
+		// Una subida inexistente o ya completa no recibe más PATCH; su
+		// lock no debe quedar en el mapa.
+		h.locks.Delete(id)
+	}
+	if !ok {
+		return
+	}
+	if offset != u.Offset {
+		http.Error(w, "Upload-Offset no coincide", http.StatusConflict)
+		return
+	}
+
+	// Un cuerpo que declara más de lo que falta se rechaza sin escribir
+	// nada.
+	remaining := u.Size - u.Offset
+	if r.ContentLength > remaining {
+		w.Header().Set("Upload-Offset", strconv.FormatInt(u.Offset, 10))
+		http.Error(w, "el cuerpo excede Upload-Length", http.StatusRequestEntityTooLarge)
+		return
+	}
+	var n int64
+	over := false
+	if r.ContentLength >= 0 {
+		n, err = h.Store.Append(r.Context(), id, offset, io.LimitReader(r.Body, remaining))
+	} else {
+		n, over, err = h.appendChunked(r.Context(), id, offset, remaining, r.Body)
+	}
+	u.Offset += n
+	if err != nil && n == 0 {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	if !u.Done() && h.Expiration > 0 {
+		u.ExpiresAt = h.expiry()
+		_ = h.Store.Touch(r.Context(), id, u.ExpiresAt)
+	}
+
+	w.Header().Set("Upload-Offset", strconv.FormatInt(u.Offset, 10))
+	if over {
+		// La subida conserva lo válido y el cliente puede retomarla
+		// desde Upload-Offset.
+		http.Error(w, "el cuerpo excede Upload-Length", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if u.Done() {
+		h.locks.Delete(id)
+	}
+	h.setExpires(w, u)
+	w.WriteHeader(http.StatusNoContent)
+	if u.Done() && n > 0 && h.OnComplete != nil {
+		h.OnComplete(r.Context(), u)
+	}
+}
+
+// appendChunked agrega un cuerpo sin Content-Length, cuyo exceso solo se
+// nota al leerlo. El último byte que falta se escribe solo si el cuerpo
+// termina justo ahí, de modo que un cuerpo excedido nunca completa la
+// subida.
+func (h *Handler) appendChunked(ctx context.Context, id string, offset, remaining int64, body io.Reader) (n int64, over bool, err error) {
+	if remaining > 1 {
+		n, err = h.Store.Append(ctx, id, offset, io.LimitReader(body, remaining-1))
+		if err != nil || n < remaining-1 {
+			return n, false, err
+		}
+	}
+	tail := make([]byte, 2)
+	k, _ := io.ReadFull(body, tail)
+	switch {
+	case int64(k) > remaining-n:
+		return n, true, nil
+	case k == 1:
+		m, err := h.Store.Append(ctx, id, offset+n, bytes.NewReader(tail[:1]))
+		return n + m, false, err
+	}
+	return n, false, nil
+}
+
+func (h *Handler) delete(w http.ResponseWriter, r *http.Request, id string) {
+	err := h.Store.Delete(r.Context(), id)
+	h.locks.Delete(id)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		http.Error(w, http.StatusText(http.StatusNotFound), http.StatusNotFound)
+	case err != nil:
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	default:
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func (h *Handler) expiry() time.Time {
+	if h.Expiration <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(h.Expiration)
+}
+
+func (h *Handler) setExpires(w http.ResponseWriter, u Upload) {
+	if !u.ExpiresAt.IsZero() && !u.Done() {
+		w.Header().Set("Upload-Expires", u.ExpiresAt.UTC().Format(http.TimeFormat))
+	}
+}
+
+// Cleanup elimina las subidas de ids que ya expiraron. El Store no
+// enumera subidas, así que quien llama aporta los candidatos (por ejemplo,
+// listando FileStore.Dir).
+func (h *Handler) Cleanup(ctx context.Context, ids []string) (removed int, err error) {
+	now := time.Now()
+	for _, id := range ids {
+		u, gerr := h.Store.Get(ctx, id)
+		if gerr != nil || u.Done() || u.ExpiresAt.IsZero() || now.Before(u.ExpiresAt) {
+			continue
+		}
+		if derr := h.Store.Delete(ctx, id); derr != nil {
+			err = errors.Join(err, derr)
+			continue
+		}
+		h.locks.Delete(id)
+		removed++
+	}
+	return removed, err
+}
+
+func newID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// validID reporta si id tiene la forma que produce newID: 32 dígitos
+// hexadecimales en minúscula.
+func validID(id string) bool {
+	if len(id) != 32 {
+		return false
+	}
+	for _, c := range id {
+		if (c < '0' || c > '9') && (c < 'a' || c > 'f') {
+			return false
+		}
+	}
+	return true
+}
+
+// parseMetadata interpreta "clave base64,clave2 base64,clave3".
+func parseMetadata(s string) (map[string]string, error) {
+	meta := map[string]string{}
+	if strings.TrimSpace(s) == "" {
+		return meta, nil
+	}
+	for pair := range strings.SplitSeq(s, ",") {
+		key, val, _ := strings.Cut(strings.TrimSpace(pair), " ")
+		if key == "" {
+			return nil, errors.New("tus: clave de metadata vacía")
+		}
+		dec, err := base64.StdEncoding.DecodeString(val)
+		if err != nil {
+			return nil, err
+		}
+		meta[key] = string(dec)
+	}
+	return meta, nil
+}
+
+func formatMetadata(meta map[string]string) string {
+	parts := make([]string, 0, len(meta))
+	for k, v := range meta {
+		parts = append(parts, k+" "+base64.StdEncoding.EncodeToString([]byte(v)))
+	}
+	return strings.Join(parts, ",")
+}
@@ -0,0 +1,256 @@
+package tus
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/profe-ajedrez/transwarp/router"
+)
+
+// muxRouter implementa lo que Register usa de router.Router sobre un
+// http.ServeMux.
+type muxRouter struct {
+	router.Router
+	mux *http.ServeMux
+}
+
+func (m muxRouter) Any(path string, h http.HandlerFunc, _ ...router.Middleware) {
+	m.mux.HandleFunc(strings.ReplaceAll(path, "/:id", "/{id}"), h)
+}
+
+func (m muxRouter) Param(r *http.Request, key string) string { return r.PathValue(key) }
+
+func (m muxRouter) ServeHTTP(w http.ResponseWriter, r *http.Request) { m.mux.ServeHTTP(w, r) }
+
+type server struct {
+	t *testing.T
+	h http.Handler
+}
+
+func newServer(t *testing.T, h *Handler) server {
+	if h.Store == nil {
+		h.Store = FileStore{Dir: t.TempDir()}
+	}
+	rt := muxRouter{mux: http.NewServeMux()}
+	h.Register(rt, "/files/")
+	return server{t, rt}
+}
+
+func (s server) do(method, target string, headers map[string]string, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.Header.Set("Tus-Resumable", Version)
+	for k, v := range headers {
+		r.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	s.h.ServeHTTP(w, r)
+	return w
+}
+
+func (s server) create(size string) string {
+	s.t.Helper()
+	w := s.do(http.MethodPost, "/files", map[string]string{"Upload-Length": size, "Upload-Metadata": "filename YS50eHQ="}, "")
+	if w.Code != http.StatusCreated {
+		s.t.Fatalf("creación: status = %d", w.Code)
+	}
+	return w.Header().Get("Location")
+}
+
+func patch(offset string) map[string]string {
+	return map[string]string{"Content-Type": "application/offset+octet-stream", "Upload-Offset": offset}
+}
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    int
+	}{
+		{"válida", map[string]string{"Upload-Length": "10"}, http.StatusCreated},
+		{"sin Upload-Length", nil, http.StatusBadRequest},
+		{"Upload-Length negativo", map[string]string{"Upload-Length": "-1"}, http.StatusBadRequest},
+		{"excede MaxSize", map[string]string{"Upload-Length": "101"}, http.StatusRequestEntityTooLarge},
+		{"metadata inválida", map[string]string{"Upload-Length": "10", "Upload-Metadata": "a !!"}, http.StatusBadRequest},
+		{"versión no soportada", map[string]string{"Upload-Length": "10", "Tus-Resumable": "0.2.0"}, http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newServer(t, &Handler{MaxSize: 100})
+			w := s.do(http.MethodPost, "/files", tt.headers, "")
+			if w.Code != tt.want {
+				t.Fatalf("status = %d, se esperaba %d", w.Code, tt.want)
+			}
+			if w.Code == http.StatusCreated && !strings.HasPrefix(w.Header().Get("Location"), "/files/") {
+				t.Errorf("Location = %q", w.Header().Get("Location"))
+			}
+		})
+	}
+}
+
+func TestPatch(t *testing.T) {
+	var completed []Upload
+	s := newServer(t, &Handler{OnComplete: func(_ context.Context, u Upload) { completed = append(completed, u) }})
+	loc := s.create("10")
+
+	steps := []struct {
+		name       string
+		headers    map[string]string
+		body       string
+		wantStatus int
+		wantOffset string
+	}{
+		{"sin Content-Type", map[string]string{"Upload-Offset": "0"}, "hola", http.StatusUnsupportedMediaType, ""},
+		{"primer tramo", patch("0"), "hola ", http.StatusNoContent, "5"},
+		{"offset desfasado", patch("0"), "hola ", http.StatusConflict, ""},
+		{"excede Upload-Length", patch("5"), "mundo!", http.StatusRequestEntityTooLarge, "5"},
+		{"completa", patch("5"), "mundo", http.StatusNoContent, "10"},
+		{"subida ya completa", patch("10"), "", http.StatusNoContent, "10"},
+	}
+	for _, tt := range steps {
+		t.Run(tt.name, func(t *testing.T) {
+			w := s.do(http.MethodPatch, loc, tt.headers, tt.body)
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, se esperaba %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Upload-Offset"); got != tt.wantOffset {
+				t.Errorf("Upload-Offset = %q, se esperaba %q", got, tt.wantOffset)
+			}
+		})
+	}
+
+	// El tramo excedido no escribió nada y el siguiente completó la subida.
+	w := s.do(http.MethodHead, loc, nil, "")
+	if w.Code != http.StatusOK || w.Header().Get("Upload-Offset") != "10" || w.Header().Get("Upload-Length") != "10" {
+		t.Errorf("HEAD: status = %d, offset = %q", w.Code, w.Header().Get("Upload-Offset"))
+	}
+	if w.Header().Get("Upload-Metadata") != "filename YS50eHQ=" {
+		t.Errorf("Upload-Metadata = %q", w.Header().Get("Upload-Metadata"))
+	}
+	if len(completed) != 1 {
+		t.Errorf("OnComplete invocado %d veces, se esperaba 1", len(completed))
+	}
+	if w := s.do(http.MethodPatch, loc, patch("10"), "x"); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("PATCH sobre subida completa: status = %d", w.Code)
+	}
+}
+
+// doChunked envía un PATCH sin Content-Length, como un cuerpo chunked.
+func (s server) doChunked(target, offset, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodPatch, target, strings.NewReader(body))
+	r.ContentLength = -1
+	r.Header.Set("Tus-Resumable", Version)
+	for k, v := range patch(offset) {
+		r.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	s.h.ServeHTTP(w, r)
+	return w
+}
+
+func TestPatchChunked(t *testing.T) {
+	tests := []struct {
+		name       string
+		size       string
+		body       string
+		wantStatus int
+		wantOffset string
+		completed  int
+	}{
+		{"exacto", "5", "abcde", http.StatusNoContent, "5", 1},
+		{"parcial", "5", "abc", http.StatusNoContent, "3", 0},
+		{"un byte", "1", "a", http.StatusNoContent, "1", 1},
+		{"excede", "5", "abcdef", http.StatusRequestEntityTooLarge, "4", 0},
+		{"excede de un byte", "1", "ab", http.StatusRequestEntityTooLarge, "0", 0},
+		{"vacío", "5", "", http.StatusNoContent, "0", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			completed := 0
+			s := newServer(t, &Handler{OnComplete: func(context.Context, Upload) { completed++ }})
+			loc := s.create(tt.size)
+			w := s.doChunked(loc, "0", tt.body)
+			if w.Code != tt.wantStatus || w.Header().Get("Upload-Offset") != tt.wantOffset {
+				t.Errorf("status = %d, Upload-Offset = %q; se esperaba %d, %q", w.Code, w.Header().Get("Upload-Offset"), tt.wantStatus, tt.wantOffset)
+			}
+			if completed != tt.completed {
+				t.Errorf("OnComplete invocado %d veces, se esperaban %d", completed, tt.completed)
+			}
+			if h := s.do(http.MethodHead, loc, nil, ""); h.Header().Get("Upload-Offset") != tt.wantOffset {
+				t.Errorf("HEAD: Upload-Offset = %q, se esperaba %q", h.Header().Get("Upload-Offset"), tt.wantOffset)
+			}
+		})
+	}
+
+	// Una subida que recibió un cuerpo excedido se retoma desde su offset.
+	completed := 0
+	s := newServer(t, &Handler{OnComplete: func(context.Context, Upload) { completed++ }})
+	loc := s.create("5")
+	s.doChunked(loc, "0", "abcdef")
+	if w := s.doChunked(loc, "4", "e"); w.Code != http.StatusNoContent || w.Header().Get("Upload-Offset") != "5" || completed != 1 {
+		t.Errorf("reanudación: status = %d, Upload-Offset = %q, OnComplete %d", w.Code, w.Header().Get("Upload-Offset"), completed)
+	}
+}
+
+func TestLocksReleased(t *testing.T) {
+	h := &Handler{}
+	s := newServer(t, h)
+	loc := s.create("3")
+	id := loc[strings.LastIndexByte(loc, '/')+1:]
+
+	s.do(http.MethodPatch, loc, patch("0"), "abc")
+	s.do(http.MethodPatch, "/files/"+strings.Repeat("0", 32), patch("0"), "abc")
+	s.do(http.MethodPatch, loc, patch("3"), "")
+
+	n := 0
+	h.locks.Range(func(any, any) bool { n++; return true })
+	if n != 0 {
+		t.Errorf("quedaron %d locks tras completar %s", n, id)
+	}
+}
+
+func TestResource(t *testing.T) {
+	s := newServer(t, &Handler{})
+	loc := s.create("3")
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		want   int
+	}{
+		{"HEAD", http.MethodHead, loc, http.StatusOK},
+		{"OPTIONS", http.MethodOptions, loc, http.StatusNoContent},
+		{"método no permitido", http.MethodPut, loc, http.StatusMethodNotAllowed},
+		{"ID inexistente", http.MethodHead, "/files/" + strings.Repeat("a", 32), http.StatusNotFound},
+		{"ID inválido", http.MethodHead, "/files/..%2F..%2Fetc", http.StatusNotFound},
+		{"ID en mayúsculas", http.MethodHead, "/files/" + strings.Repeat("A", 32), http.StatusNotFound},
+		{"DELETE", http.MethodDelete, loc, http.StatusNoContent},
+		{"HEAD tras DELETE", http.MethodHead, loc, http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := s.do(tt.method, tt.target, nil, ""); w.Code != tt.want {
+				t.Errorf("status = %d, se esperaba %d", w.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestFileStorePaths(t *testing.T) {
+	s := FileStore{Dir: t.TempDir()}
+	for _, id := range []string{"", "../x", "../../etc/passwd", strings.Repeat("g", 32), strings.Repeat("a", 31)} {
+		if _, _, err := s.paths(id); !errors.Is(err, ErrNotFound) {
+			t.Errorf("paths(%q) err = %v, se esperaba ErrNotFound", id, err)
+		}
+		if p := s.Path(id); p != "" {
+			t.Errorf("Path(%q) = %q, se esperaba vacío", id, p)
+		}
+	}
+	if err := s.Create(context.Background(), Upload{ID: "../escape"}); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Create con ID inválido: err = %v", err)
+	}
+}
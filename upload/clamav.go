@@ -0,0 +1,92 @@
+package upload
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+// ClamAV es un Scanner que envía el contenido a clamd con el comando
+// INSTREAM. El tamaño máximo lo define StreamMaxLength en clamd.conf.
+type ClamAV struct {
+	// Network y Address indican dónde escucha clamd, p. ej. "tcp" y
+	// "127.0.0.1:3310" o "unix" y "/run/clamav/clamd.ctl".
+	Network string
+	Address string
+	// Timeout limita cada análisis; por defecto un minuto.
+	Timeout time.Duration
+}
+
+// clamChunk es el tamaño de cada bloque INSTREAM.
+const clamChunk = 32 << 10
+
+// Scan implementa Scanner.
+func (c *ClamAV) Scan(ctx context.Context, _ FileInfo, r io.Reader) (Verdict, error) {
+	timeout := c.Timeout
+	if timeout <= 0 {
+		timeout = time.Minute
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, c.Network, c.Address)
+	if err != nil {
+		return Verdict{}, err
+	}
+	defer conn.Close()
+	deadline, _ := ctx.Deadline()
+	conn.SetDeadline(deadline)
+
+	w := bufio.NewWriterSize(conn, clamChunk+4)
+	if _, err := w.WriteString("zINSTREAM\x00"); err != nil {
+		return Verdict{}, err
+	}
+	buf := make([]byte, clamChunk)
+	for {
+		n, rerr := r.Read(buf)
+		if n > 0 {
+			if err := binary.Write(w, binary.BigEndian, uint32(n)); err != nil {
+				return Verdict{}, err
+			}
+			if _, err := w.Write(buf[:n]); err != nil {
+				return Verdict{}, err
+			}
+		}
+		if rerr == io.EOF {
+			break
+		}
+		if rerr != nil {
+			return Verdict{}, rerr
+		}
+	}
+	if err := binary.Write(w, binary.BigEndian, uint32(0)); err != nil {
+		return Verdict{}, err
+	}
+	if err := w.Flush(); err != nil {
+		return Verdict{}, err
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && err != io.EOF {
+		return Verdict{}, err
+	}
+	return parseClamReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseClamReply interpreta respuestas como "stream: OK" o
+// "stream: Eicar-Signature FOUND".
+func parseClamReply(reply string) (Verdict, error) {
+	_, result, _ := strings.Cut(reply, ": ")
+	switch {
+	case result == "OK":
+		return Verdict{Clean: true}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return Verdict{Threat: strings.TrimSuffix(result, " FOUND")}, nil
+	}
+	return Verdict{}, fmt.Errorf("upload: respuesta de clamd inesperada: %q", reply)
+}
@@ -0,0 +1,157 @@
+package upload
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/profe-ajedrez/transwarp/internal/workerpool"
+)
+
+// ErrInfected indica que un Scanner encontró una amenaza; el error
+// concreto es *InfectedError.
+var ErrInfected = errors.New("upload: archivo infectado")
+
+// Scanner analiza el contenido de un archivo recibido, p. ej. con un
+// antivirus. Las implementaciones deben ser seguras para uso concurrente.
+type Scanner interface {
+	// Scan lee r completo y retorna el veredicto. Un error indica que no
+	// se pudo analizar, no que el archivo esté infectado.
+	Scan(ctx context.Context, info FileInfo, r io.Reader) (Verdict, error)
+}
+
+// Verdict es el resultado de un análisis.
+type Verdict struct {
+	Clean bool
+	// Threat es el nombre de la amenaza cuando Clean es false.
+	Threat string
+}
+
+// InfectedError es el error de Files cuando un archivo no pasa el análisis.
+type InfectedError struct {
+	Filename string
+	Threat   string
+}
+
+func (e *InfectedError) Error() string {
+	return fmt.Sprintf("upload: %s infectado: %s", e.Filename, e.Threat)
+}
+
+func (e *InfectedError) Is(target error) bool { return target == ErrInfected }
+
+// scan analiza en el momento el archivo ya escrito en f.Path.
+func scan(ctx context.Context, s Scanner, f File) error {
+	file, err := os.Open(f.Path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	v, err := s.Scan(ctx, f.FileInfo, file)
+	if err != nil {
+		return err
+	}
+	if !v.Clean {
+		return &InfectedError{Filename: f.Filename, Threat: v.Threat}
+	}
+	return nil
+}
+
+// teeScan analiza el contenido a medida que se escribe en dst, para los
+// destinos de Options.Dest que no pueden releerse. wait retorna el
+// resultado una vez cerrado el writer.
+func teeScan(ctx context.Context, s Scanner, info FileInfo, dst io.WriteCloser) (w io.WriteCloser, wait func() error) {
+	pr, pw := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		v, err := s.Scan(ctx, info, pr)
+		// Se descarta lo que el scanner no haya leído para no bloquear
+		// la escritura.
+		_, _ = io.Copy(io.Discard, pr)
+		if err == nil && !v.Clean {
+			err = &InfectedError{Filename: info.Filename, Threat: v.Threat}
+		}
+		done <- err
+	}()
+	w = &teeWriter{dst: dst, pw: pw}
+	return w, func() error { return <-done }
+}
+
+type teeWriter struct {
+	dst io.WriteCloser
+	pw  *io.PipeWriter
+}
+
+func (t *teeWriter) Write(p []byte) (int, error) {
+	n, err := t.dst.Write(p)
+	if n > 0 {
+		_, _ = t.pw.Write(p[:n])
+	}
+	return n, err
+}
+
+func (t *teeWriter) Close() error {
+	t.pw.Close()
+	return t.dst.Close()
+}
+
+// ScanQueue acota los análisis en segundo plano de Options.AsyncScanAbove
+// a una cantidad fija de workers. Se crea una vez y se comparte entre
+// peticiones mediante Options.ScanQueue.
+type ScanQueue struct {
+	pool *workerpool.Pool
+}
+
+// NewScanQueue crea una ScanQueue con workers análisis simultáneos (por
+// defecto 4) y hasta queue archivos en espera (por defecto workers).
+func NewScanQueue(workers, queue int) *ScanQueue {
+	if workers <= 0 {
+		workers = 4
+	}
+	return &ScanQueue{pool: workerpool.New(workerpool.Options{Workers: workers, Queue: queue})}
+}
+
+// Close deja de aceptar archivos y espera los análisis pendientes hasta
+// que ctx se cancele.
+func (q *ScanQueue) Close(ctx context.Context) error {
+	return q.pool.Close(ctx)
+}
+
+// defaultScanQueue atiende a las Options sin ScanQueue.
+var defaultScanQueue = sync.OnceValue(func() *ScanQueue { return NewScanQueue(0, 0) })
+
+// scanAsync encola el análisis de f y reporta si pudo encolarlo; con la
+// cola llena el caller lo analiza en el momento. Si f no está limpio, o si
+// no pudo analizarse, el archivo se mueve a opts.Quarantine o se elimina.
+func scanAsync(opts *Options, f File) bool {
+	f.ScanPending = true
+	q := opts.ScanQueue
+	if q == nil {
+		q = defaultScanQueue()
+	}
+	err := q.pool.TrySubmit(func() {
+		file, err := os.Open(f.Path)
+		var v Verdict
+		if err == nil {
+			v, err = opts.Scanner.Scan(context.Background(), f.FileInfo, file)
+			file.Close()
+		}
+		if err != nil || !v.Clean {
+			quarantine(opts.Quarantine, f.Path)
+		}
+		if opts.OnScan != nil {
+			opts.OnScan(f, v, err)
+		}
+	})
+	return err == nil
+}
+
+func quarantine(dir, path string) {
+	if dir != "" && os.Rename(path, filepath.Join(dir, filepath.Base(path))) == nil {
+		return
+	}
+	os.Remove(path)
+}
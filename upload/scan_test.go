@@ -0,0 +1,134 @@
+package upload
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseClamReply(t *testing.T) {
+	tests := []struct {
+		reply   string
+		want    Verdict
+		wantErr bool
+	}{
+		{"stream: OK", Verdict{Clean: true}, false},
+		{"stream: Eicar-Signature FOUND", Verdict{Threat: "Eicar-Signature"}, false},
+		{"stream: Win.Test.EICAR_HDB-1 FOUND", Verdict{Threat: "Win.Test.EICAR_HDB-1"}, false},
+		{"INSTREAM size limit exceeded. ERROR", Verdict{}, true},
+		{"stream: lstat() failed ERROR", Verdict{}, true},
+		{"", Verdict{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reply, func(t *testing.T) {
+			got, err := parseClamReply(tt.reply)
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("parseClamReply = %+v, %v; se esperaba %+v (error: %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestICAPThreat(t *testing.T) {
+	tests := []struct{ in, want string }{
+		{"Type=0; Resolution=2; Threat=Eicar;", "Eicar"},
+		{"Type=0; Threat=EICAR-Test-File; Resolution=2", "EICAR-Test-File"},
+		{"Eicar-Test-Signature", "Eicar-Test-Signature"},
+		{"  Trojan.Gen  ", "Trojan.Gen"},
+	}
+	for _, tt := range tests {
+		if got := icapThreat(tt.in); got != tt.want {
+			t.Errorf("icapThreat(%q) = %q, se esperaba %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestReadICAPReply(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		want    Verdict
+		wantErr bool
+	}{
+		{"204 limpio", "ICAP/1.0 204 No Content\r\nISTag: x\r\n\r\n", Verdict{Clean: true}, false},
+		{"200 con X-Infection-Found", "ICAP/1.0 200 OK\r\nX-Infection-Found: Type=0; Resolution=2; Threat=Eicar;\r\n\r\n", Verdict{Threat: "Eicar"}, false},
+		{"200 con X-Virus-Id", "ICAP/1.0 200 OK\r\nX-Virus-ID: EICAR\r\n\r\n", Verdict{Threat: "EICAR"}, false},
+		{"200 sin cabecera de amenaza", "ICAP/1.0 200 OK\r\nEncapsulated: res-hdr=0\r\n\r\n", Verdict{Threat: "bloqueado por el servidor ICAP"}, false},
+		{"error del servidor", "ICAP/1.0 500 Server Error\r\n\r\n", Verdict{}, true},
+		{"línea de estado inválida", "basura\r\n\r\n", Verdict{}, true},
+		{"sin respuesta", "", Verdict{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := readICAPReply(strings.NewReader(tt.reply))
+			if (err != nil) != tt.wantErr || got != tt.want {
+				t.Errorf("readICAPReply = %+v, %v; se esperaba %+v (error: %v)", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// slowScanner retiene los archivos que empiezan con "lento" hasta que se
+// cierre release, avisando en started.
+type slowScanner struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s slowScanner) Scan(_ context.Context, _ FileInfo, r io.Reader) (Verdict, error) {
+	b, err := io.ReadAll(r)
+	if strings.HasPrefix(string(b), "lento") {
+		s.started <- struct{}{}
+		<-s.release
+	}
+	return Verdict{Clean: true}, err
+}
+
+func TestScanQueueBounded(t *testing.T) {
+	q := NewScanQueue(1, 1)
+	defer q.Close(context.Background())
+	sc := slowScanner{started: make(chan struct{}, 2), release: make(chan struct{})}
+	scanned := make(chan string, 3)
+	opts := Options{
+		Dir:            t.TempDir(),
+		Scanner:        sc,
+		AsyncScanAbove: 4,
+		ScanQueue:      q,
+		OnScan:         func(f File, v Verdict, err error) { scanned <- f.Filename },
+	}
+	upload := func(name, body string) File {
+		t.Helper()
+		res, err := Files(form(t, part{"a", name, body}), opts)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return res.Files[0]
+	}
+
+	// El único worker queda ocupado con el primero y el segundo ocupa la
+	// cola; el tercero se analiza en el momento.
+	if f := upload("1.txt", "lento 1"); !f.ScanPending {
+		t.Fatal("el primero debe analizarse en segundo plano")
+	}
+	<-sc.started
+	if f := upload("2.txt", "lento 2"); !f.ScanPending {
+		t.Fatal("el segundo debe quedar en la cola")
+	}
+	if f := upload("3.txt", "rápido"); f.ScanPending {
+		t.Fatal("con la cola llena el análisis debe ser inmediato")
+	}
+
+	close(sc.release)
+	for _, want := range []string{"1.txt", "2.txt"} {
+		select {
+		case got := <-scanned:
+			if got != want {
+				t.Errorf("OnScan(%s), se esperaba %s", got, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("OnScan no se invocó para %s", want)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+// Package upload recibe archivos multipart en streaming, aplicando límites
+// de cantidad y tamaño, filtrando por tipo MIME detectado a partir del
+// contenido y, opcionalmente, analizando cada archivo con un Scanner, sin
+// cargar el cuerpo completo en memoria.
+package upload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// sniffLen es cuántos bytes usa http.DetectContentType.
+const sniffLen = 512
+
+// maxValueBytes limita cada campo de formulario que no es archivo.
+const maxValueBytes = 1 << 20
+
+// Topes por defecto de Options.MaxValuesSize y Options.MaxValues.
+const (
+	defaultMaxValuesSize = 10 << 20
+	defaultMaxValues     = 1000
+)
+
+// Errores de Files; se comparan con errors.Is.
+var (
+	ErrNotMultipart  = errors.New("upload: la petición no es multipart/form-data")
+	ErrTooManyFiles  = errors.New("upload: demasiados archivos")
+	ErrFileTooLarge  = errors.New("upload: archivo demasiado grande")
+	ErrTotalTooLarge = errors.New("upload: la subida excede el tamaño total")
+	ErrType          = errors.New("upload: tipo de archivo no permitido")
+	ErrValueTooLarge = errors.New("upload: los campos del formulario exceden el tamaño permitido")
+	ErrTooManyValues = errors.New("upload: demasiados campos de formulario")
+)
+
+// FileInfo describe un archivo antes de escribirlo.
+type FileInfo struct {
+	Field    string
+	Filename string
+	// ContentType es el tipo detectado a partir del contenido.
+	ContentType string
+	// DeclaredType es el Content-Type que declara el cliente para la
+	// parte; no es confiable por sí solo.
+	DeclaredType string
+}
+
+// File describe un archivo recibido.
+type File struct {
+	FileInfo
+	Size int64
+	// Path es la ruta del archivo si se escribió en Options.Dir.
+	Path string
+	// ScanPending indica que el archivo se está analizando en segundo
+	// plano; no debe publicarse hasta que Options.OnScan informe el
+	// resultado.
+	ScanPending bool
+}
+
+// Options configura Files.
+type Options struct {
+	// MaxFiles limita la cantidad de archivos; cero no limita.
+	MaxFiles int
+	// MaxFileSize limita cada archivo en bytes; cero no limita.
+	MaxFileSize int64
+	// MaxTotalSize limita la suma de los archivos; cero no limita.
+	MaxTotalSize int64
+	// MaxValuesSize limita la suma de los campos que no son archivo,
+	// nombres incluidos; por defecto 10 MiB. Cada campo admite además
+	// hasta 1 MiB.
+	MaxValuesSize int64
+	// MaxValues limita la cantidad de campos que no son archivo; por
+	// defecto 1000.
+	MaxValues int
+	// Types restringe los tipos MIME detectados; admite comodines como
+	// "image/*". Vacío acepta cualquiera.
+	Types []string
+	// Dir es el directorio donde se crean los archivos; por defecto
+	// os.TempDir(). Se ignora si Dest no es nil.
+	Dir string
+	// Dest abre el destino de cada archivo, en lugar de crearlo en Dir.
+	Dest func(info FileInfo) (io.WriteCloser, error)
+	// Check valida cada archivo a partir de sus primeros bytes antes de
+	// escribirlo, por ejemplo con contenttype.Policy.Upload. Su error se
+	// retorna tal cual.
+	Check func(info FileInfo, head []byte) error
+	// Scanner analiza cada archivo una vez escrito; uno infectado aborta
+	// la subida con *InfectedError. Con Dest el análisis ocurre mientras
+	// se escribe y el destino queda a cargo del caller si falla.
+	Scanner Scanner
+	// AsyncScanAbove analiza en segundo plano los archivos escritos en
+	// Dir de más de estos bytes, marcándolos con ScanPending, para no
+	// retener la petición. Cero analiza todo en el momento.
+	AsyncScanAbove int64
+	// ScanQueue ejecuta los análisis en segundo plano; por defecto una
+	// cola compartida de 4 workers. Si está llena, el archivo se analiza
+	// en el momento.
+	ScanQueue *ScanQueue
+	// Quarantine es el directorio al que se mueven los archivos que no
+	// pasan un análisis en segundo plano; vacío los elimina.
+	Quarantine string
+	// OnScan recibe el resultado de cada análisis en segundo plano; err
+	// indica que no pudo completarse y el archivo se trató como infectado.
+	OnScan func(f File, v Verdict, err error)
+}
+
+// Result es el resultado de Files.
+type Result struct {
+	Files  []File
+	Values url.Values
+}
+
+// Remove borra los archivos escritos en disco.
+func (res *Result) Remove() {
+	for _, f := range res.Files {
+		if f.Path != "" {
+			os.Remove(f.Path)
+		}
+	}
+}
+
+// Files lee el cuerpo multipart de r parte por parte. Si se viola algún
+// límite se retorna el error correspondiente y los archivos ya escritos en
+// disco se eliminan. Los campos que no son archivo se retornan en Values.
+func Files(r *http.Request, opts Options) (*Result, error) {
+	mr, err := r.MultipartReader()
+	if err != nil {
+		return nil, ErrNotMultipart
+	}
+	res := &Result{Values: url.Values{}}
+	var total int64
+	valuesLeft := opts.MaxValuesSize
+	if valuesLeft <= 0 {
+		valuesLeft = defaultMaxValuesSize
+	}
+	maxValues := opts.MaxValues
+	if maxValues <= 0 {
+		maxValues = defaultMaxValues
+	}
+	var nValues int
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			return res, nil
+		}
+		if err != nil {
+			res.Remove()
+			return nil, err
+		}
+		if part.FileName() == "" {
+			if nValues++; nValues > maxValues {
+				part.Close()
+				res.Remove()
+				return nil, ErrTooManyValues
+			}
+			valuesLeft -= int64(len(part.FormName()))
+			// Se lee un byte más que el límite para detectar el exceso en
+			// lugar de truncar el valor.
+			limit := min(int64(maxValueBytes), valuesLeft)
+			v, err := io.ReadAll(io.LimitReader(part, max(limit, 0)+1))
+			part.Close()
+			if err == nil && int64(len(v)) > limit {
+				err = ErrValueTooLarge
+			}
+			if err != nil {
+				res.Remove()
+				return nil, err
+			}
+			valuesLeft -= int64(len(v))
+			res.Values.Add(part.FormName(), string(v))
+			continue
+		}
+		if opts.MaxFiles > 0 && len(res.Files) >= opts.MaxFiles {
+			part.Close()
+			res.Remove()
+			return nil, ErrTooManyFiles
+		}
+		f, err := save(r.Context(), part, part.FormName(), filepath.Base(part.FileName()), part.Header.Get("Content-Type"), &opts, total)
+		part.Close()
+		if err != nil {
+			res.Remove()
+			return nil, err
+		}
+		total += f.Size
+		res.Files = append(res.Files, f)
+	}
+}
+
+func save(ctx context.Context, part io.Reader, field, filename, declared string, opts *Options, total int64) (File, error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(part, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return File{}, err
+	}
+	head = head[:n]
+	f := File{FileInfo: FileInfo{Field: field, Filename: filename, ContentType: http.DetectContentType(head), DeclaredType: declared}}
+	if !allowed(opts.Types, f.ContentType) {
+		return File{}, fmt.Errorf("%w: %s", ErrType, f.ContentType)
+	}
+	if opts.Check != nil {
+		if err := opts.Check(f.FileInfo, head); err != nil {
+			return File{}, err
+		}
+	}
+
+	var dst io.WriteCloser
+	if opts.Dest != nil {
+		dst, err = opts.Dest(f.FileInfo)
+	} else {
+		var tmp *os.File
+		tmp, err = os.CreateTemp(opts.Dir, "upload-*"+safeExt(filename))
+		if tmp != nil {
+			f.Path = tmp.Name()
+			dst = tmp
+		}
+	}
+	if err != nil {
+		return File{}, err
+	}
+	var scanned func() error
+	if opts.Scanner != nil && opts.Dest != nil {
+		dst, scanned = teeScan(ctx, opts.Scanner, f.FileInfo, dst)
+	}
+
+	// Se lee un byte más que el límite para distinguir "justo en el
+	// límite" de "excedido" sin depender de Content-Length.
+	limit := int64(-1)
+	if opts.MaxFileSize > 0 {
+		limit = opts.MaxFileSize
+	}
+	if opts.MaxTotalSize > 0 && (limit < 0 || opts.MaxTotalSize-total < limit) {
+		limit = opts.MaxTotalSize - total
+	}
+	src := io.MultiReader(bytes.NewReader(head), part)
+	if limit >= 0 {
+		src = io.LimitReader(src, limit+1)
+	}
+	f.Size, err = io.Copy(dst, src)
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil && limit >= 0 && f.Size > limit {
+		err = ErrFileTooLarge
+		if opts.MaxFileSize <= 0 || f.Size <= opts.MaxFileSize {
+			err = ErrTotalTooLarge
+		}
+	}
+	if scanned != nil {
+		if serr := scanned(); err == nil {
+			err = serr
+		}
+	}
+	if err == nil && opts.Scanner != nil && f.Path != "" {
+		if opts.AsyncScanAbove > 0 && f.Size > opts.AsyncScanAbove {
+			f.ScanPending = scanAsync(opts, f)
+		}
+		if !f.ScanPending {
+			err = scan(ctx, opts.Scanner, f)
+		}
+	}
+	if err != nil {
+		if f.Path != "" {
+			os.Remove(f.Path)
+		}
+		return File{}, err
+	}
+	return f, nil
+}
+
+func allowed(types []string, ct string) bool {
+	if len(types) == 0 {
+		return true
+	}
+	mt, _, err := mime.ParseMediaType(ct)
+	if err != nil {
+		return false
+	}
+	return slices.ContainsFunc(types, func(t string) bool {
+		if prefix, ok := strings.CutSuffix(t, "/*"); ok {
+			return strings.HasPrefix(mt, prefix+"/")
+		}
+		return t == mt
+	})
+}
+
+// safeExt conserva la extensión del nombre original si es corta y simple,
+// para que el archivo temporal sea reconocible.
+func safeExt(name string) string {
+	ext := filepath.Ext(name)
+	if len(ext) > 10 || strings.ContainsAny(ext, `/\ `) {
+		return ""
+	}
+	return ext
+}
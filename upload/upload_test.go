@@ -0,0 +1,203 @@
+package upload
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// part es un campo del formulario; con filename es un archivo.
+type part struct {
+	field, filename, body string
+}
+
+func form(t *testing.T, parts ...part) *http.Request {
+	t.Helper()
+	var buf bytes.Buffer
+	mw := multipart.NewWriter(&buf)
+	for _, p := range parts {
+		var w io.Writer
+		var err error
+		if p.filename != "" {
+			w, err = mw.CreateFormFile(p.field, p.filename)
+		} else {
+			w, err = mw.CreateFormField(p.field)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		io.WriteString(w, p.body)
+	}
+	mw.Close()
+	r := httptest.NewRequest(http.MethodPost, "/", &buf)
+	r.Header.Set("Content-Type", mw.FormDataContentType())
+	return r
+}
+
+// fakeScanner marca como infectado todo contenido que incluya "EICAR".
+type fakeScanner struct {
+	err error
+}
+
+func (s fakeScanner) Scan(_ context.Context, _ FileInfo, r io.Reader) (Verdict, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return Verdict{}, err
+	}
+	if s.err != nil {
+		return Verdict{}, s.err
+	}
+	if bytes.Contains(b, []byte("EICAR")) {
+		return Verdict{Threat: "Eicar-Test-Signature"}, nil
+	}
+	return Verdict{Clean: true}, nil
+}
+
+func TestFilesLimits(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  Options
+		parts []part
+		want  error
+	}{
+		{"sin límites", Options{}, []part{{"a", "a.txt", "hola"}, {"nota", "", "x"}}, nil},
+		{"archivo justo en el límite", Options{MaxFileSize: 4}, []part{{"a", "a.txt", "hola"}}, nil},
+		{"archivo excedido", Options{MaxFileSize: 3}, []part{{"a", "a.txt", "hola"}}, ErrFileTooLarge},
+		{"total excedido", Options{MaxTotalSize: 6}, []part{{"a", "a.txt", "hola"}, {"b", "b.txt", "hola"}}, ErrTotalTooLarge},
+		{"demasiados archivos", Options{MaxFiles: 1}, []part{{"a", "a.txt", "1"}, {"b", "b.txt", "2"}}, ErrTooManyFiles},
+		{"tipo no permitido", Options{Types: []string{"image/*"}}, []part{{"a", "a.txt", "hola"}}, ErrType},
+		{"campo excedido", Options{MaxValuesSize: 8}, []part{{"nota", "", "12345"}}, ErrValueTooLarge},
+		{"el nombre cuenta", Options{MaxValuesSize: 8}, []part{{"nota", "", "1234"}}, nil},
+		{"demasiados campos", Options{MaxValues: 1}, []part{{"a", "", "1"}, {"b", "", "2"}}, ErrTooManyValues},
+		{"check rechaza", Options{Check: func(FileInfo, []byte) error { return ErrType }}, []part{{"a", "a.txt", "x"}}, ErrType},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			tt.opts.Dir = dir
+			res, err := Files(form(t, tt.parts...), tt.opts)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, se esperaba %v", err, tt.want)
+			}
+			if err != nil {
+				if left, _ := os.ReadDir(dir); len(left) != 0 {
+					t.Errorf("quedaron %d archivos tras el error", len(left))
+				}
+				return
+			}
+			res.Remove()
+		})
+	}
+}
+
+func TestFilesValues(t *testing.T) {
+	res, err := Files(form(t, part{"nota", "", "hola"}, part{"nota", "", "chau"}), Options{Dir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Join(res.Values["nota"], ","); got != "hola,chau" {
+		t.Errorf("Values = %q", got)
+	}
+}
+
+func TestFilesScanner(t *testing.T) {
+	boom := errors.New("antivirus caído")
+	tests := []struct {
+		name    string
+		scanner Scanner
+		body    string
+		dest    bool
+		want    error
+	}{
+		{"limpio", fakeScanner{}, "hola", false, nil},
+		{"infectado", fakeScanner{}, "X5O EICAR", false, ErrInfected},
+		{"error del scanner", fakeScanner{err: boom}, "hola", false, boom},
+		{"limpio con Dest", fakeScanner{}, "hola", true, nil},
+		{"infectado con Dest", fakeScanner{}, "X5O EICAR", true, ErrInfected},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			opts := Options{Dir: dir, Scanner: tt.scanner}
+			var dst bytes.Buffer
+			if tt.dest {
+				opts.Dest = func(FileInfo) (io.WriteCloser, error) { return nopCloser{&dst}, nil }
+			}
+			res, err := Files(form(t, part{"a", "a.txt", tt.body}), opts)
+			if !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, se esperaba %v", err, tt.want)
+			}
+			var infected *InfectedError
+			if errors.As(err, &infected) && infected.Filename != "a.txt" {
+				t.Errorf("Filename = %q", infected.Filename)
+			}
+			if left, _ := os.ReadDir(dir); err != nil && len(left) != 0 {
+				t.Errorf("el archivo rechazado sigue en disco")
+			}
+			if err == nil && res.Files[0].ScanPending {
+				t.Error("un análisis en el momento no queda pendiente")
+			}
+		})
+	}
+}
+
+type nopCloser struct{ io.Writer }
+
+func (nopCloser) Close() error { return nil }
+
+func TestFilesAsyncScan(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		quarantined bool
+	}{
+		{"limpio", strings.Repeat("a", 64), false},
+		{"infectado", "EICAR" + strings.Repeat("a", 64), true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, qdir := t.TempDir(), t.TempDir()
+			done := make(chan Verdict, 1)
+			opts := Options{
+				Dir:            dir,
+				Scanner:        fakeScanner{},
+				AsyncScanAbove: 16,
+				Quarantine:     qdir,
+				OnScan:         func(f File, v Verdict, err error) { done <- v },
+			}
+			res, err := Files(form(t, part{"a", "a.txt", tt.body}), opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			f := res.Files[0]
+			if !f.ScanPending {
+				t.Fatal("un archivo sobre AsyncScanAbove debe quedar pendiente")
+			}
+
+			select {
+			case v := <-done:
+				if v.Clean == tt.quarantined {
+					t.Errorf("Clean = %v", v.Clean)
+				}
+			case <-time.After(5 * time.Second):
+				t.Fatal("OnScan no se invocó")
+			}
+			_, err = os.Stat(filepath.Join(qdir, filepath.Base(f.Path)))
+			if moved := err == nil; moved != tt.quarantined {
+				t.Errorf("en cuarentena = %v, se esperaba %v", moved, tt.quarantined)
+			}
+			if _, err := os.Stat(f.Path); (err == nil) == tt.quarantined {
+				t.Errorf("el archivo original existe = %v", err == nil)
+			}
+		})
+	}
+}